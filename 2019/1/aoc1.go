@@ -13,10 +13,22 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
+
+	"github.com/erik-adelbert/aoc/lib/num"
 )
 
 func main() {
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2) // part 1 & 2
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
 	Σ1, Σ2 := 0, 0
 
 	f := func(x int) int { return x/3 - 2 }
@@ -29,20 +41,26 @@ func main() {
 		return
 	}
 
-	input := bufio.NewScanner(os.Stdin)
-	for input.Scan() {
-		x := atoi(input.Text())
+	masses, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
+
+	for _, x := range masses {
 		Σ1 += f(x)
 		Σ2 += fofo(f(x))
 	}
-	fmt.Println(Σ1, Σ2) // part 1 & 2
+
+	return Result{Σ1, Σ2}, nil
 }
 
-// strconv.Atoi simplified core loop
-// s is ^\d+$
-func atoi(s string) (n int) {
-	for i := range s {
-		n = 10*n + int(s[i]-'0')
+// parse reads the module masses, one per line
+func parse(r io.Reader) ([]int, error) {
+	var masses []int
+
+	input := bufio.NewScanner(r)
+	for input.Scan() {
+		masses = append(masses, num.Atoi(input.Text()))
 	}
-	return
+	return masses, input.Err()
 }
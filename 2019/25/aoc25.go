@@ -12,31 +12,43 @@ package main
 
 import (
 	"bufio"
+	"flag"
 	"fmt"
 	"io"
 	"os"
 	"slices"
 	"strings"
 	"time"
+
+	"github.com/erik-adelbert/aoc/lib/intcode"
 )
 
 func main() {
-	if len(os.Args) < 2 {
+	os.Exit(run())
+}
+
+// run plays the game and returns its exit status, main exits once the
+// profiles are written
+func run() int {
+	defer profile()()
+
+	if flag.NArg() < 1 {
 		fmt.Println("Usage: program <intcode_file>")
-		return
+		return 2
 	}
 
-	raw, err := loadIntCode(os.Args[1])
+	raw, err := loadIntCode(flag.Arg(0))
 	if err != nil {
 		fmt.Printf("Error loading IntCode program: %v\n", err)
-		return
+		return 1
+	}
+	code, err := intcode.Parse(raw)
+	if err != nil {
+		fmt.Printf("Error loading IntCode program: %v\n", err)
+		return 1
 	}
-	code := newIC(raw)
-
-	in := make(chan int, 1)
-	cpu := newIntCodeCPU(0, in)
 
-	go cpu.run(code)
+	cpu := intcode.New(0, code)
 
 	// Interactive shell
 	input := bufio.NewScanner(os.Stdin)
@@ -44,9 +56,11 @@ func main() {
 	var room *room
 	var starship dungeon
 
+	var here *intcode.Snapshot // before the current room, for save
 	var skiproom bool
 	for {
 		if !skiproom {
+			here = cpu.Snapshot()
 			r, err := getroom(cpu)
 			switch {
 			case err != nil:
@@ -54,7 +68,7 @@ func main() {
 
 				if strings.Contains(err.Error(), "hello") {
 					fmt.Println("Exiting shell...")
-					return
+					return 0
 				}
 			case r.name != "":
 				room = r
@@ -70,20 +84,20 @@ func main() {
 		switch input.Text() {
 		case "exit", "q":
 			fmt.Println("Exiting shell...")
-			return
+			return 0
 
 		case "n", "north", "s", "south", "e", "east", "w", "west":
 			d := input.Text()[0:1]
 			if strings.Contains(room.doors, d) {
-				writeln(in, DIRS[d[0]], false)
+				writeln(cpu, DIRS[d[0]], false)
 			} else {
 				fmt.Println("You can't go that way.")
 				skiproom = true
 			}
 
 		case "a", "automap":
-			starship = automap(room, cpu, in)
-			writeln(in, "inv", false)
+			starship = automap(room, cpu)
+			writeln(cpu, "inv", false)
 
 		case "b", "breakin":
 			skiproom = true
@@ -96,18 +110,18 @@ func main() {
 				if starship[room.name][x] != "" {
 					continue
 				}
-				if breakin(cpu, in, x) {
+				if breakin(cpu, x) {
 					fmt.Println("Exiting shell...")
-					return
+					return 0
 				}
 			}
 
 		case "i", "inv":
-			writeln(in, "inv", false)
+			writeln(cpu, "inv", false)
 
 		case "r":
 			skiproom = true
-			fmt.Println("read:", cpu.readln())
+			fmt.Println("read:", cpu.ReadLine())
 
 		default:
 			line := input.Text()
@@ -124,10 +138,21 @@ func main() {
 					}
 
 					if strings.Contains(available, item) && !strings.Contains(blacklisted, item) {
-						writeln(in, "take "+item, false)
+						writeln(cpu, "take "+item, false)
 						skiproom = false
 					}
 				}
+			case strings.HasPrefix(line, "save "):
+				skiproom = true
+				if err := save(here, line[5:]); err != nil {
+					fmt.Println(err)
+				}
+			case strings.HasPrefix(line, "load "):
+				skiproom = false
+				if err := load(cpu, line[5:]); err != nil {
+					fmt.Println(err)
+					skiproom = true
+				}
 			case strings.HasPrefix(input.Text(), "go"):
 				skiproom = true
 				if len(starship) == 0 {
@@ -141,14 +166,15 @@ func main() {
 				}
 				if _, ok := starship[dst]; !ok {
 					fmt.Println("Invalid destination")
-				} else if rr := autogo(cpu, in, starship, room.name, dst); rr != nil {
+				} else if rr := autogo(cpu, starship, room.name, dst); rr != nil {
 					room = rr
 				}
 			default:
-				writeln(in, input.Text(), false)
+				writeln(cpu, input.Text(), false)
 			}
 		}
 	}
+	return 0
 }
 
 func loadIntCode(filename string) (string, error) {
@@ -165,6 +191,31 @@ func loadIntCode(filename string) (string, error) {
 	return string(code), nil
 }
 
+// save writes s to file
+func save(s *intcode.Snapshot, file string) error {
+	data, err := s.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, data, 0o644)
+}
+
+// load resumes cpu from the snapshot in file, the room it was taken in
+// shows again
+func load(cpu *intcode.CPU, file string) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	var s intcode.Snapshot
+	if err := s.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	cpu.Restore(&s)
+	return nil
+}
+
 type dungeon map[string]map[rune]string
 
 func (d dungeon) add(name string) {
@@ -180,7 +231,7 @@ type room struct {
 	doors string
 }
 
-func getroom(cpu *IntCodeCPU) (*room, error) {
+func getroom(cpu *intcode.CPU) (*room, error) {
 	room := &room{}
 
 	const (
@@ -196,7 +247,7 @@ func getroom(cpu *IntCodeCPU) (*room, error) {
 	empty := 0
 	state := PROLOG
 	for {
-		line := cpu.readln()
+		line := cpu.ReadLine()
 		// fmt.Println(line)
 		switch state {
 		case PROLOG:
@@ -264,17 +315,14 @@ func getroom(cpu *IntCodeCPU) (*room, error) {
 
 var blacklist = []string{"escape po\x17", "escape pod", "giant electromagnet", "photons", "molten lava", "mutex", "infinite loop"}
 
-func writeln(in chan int, s string, trace bool) {
+func writeln(cpu *intcode.CPU, s string, trace bool) {
 	if trace {
 		fmt.Println(">", s)
 	}
-	for _, c := range s {
-		in <- int(c)
-	}
-	in <- '\n'
+	cpu.WriteLine(s)
 }
 
-func automap(root *room, cpu *IntCodeCPU, in chan int) dungeon {
+func automap(root *room, cpu *intcode.CPU) dungeon {
 	fmt.Println("Automapping...")
 	starship := make(dungeon, 19)
 
@@ -282,23 +330,15 @@ func automap(root *room, cpu *IntCodeCPU, in chan int) dungeon {
 	reexplore = func(r *room) {
 		starship.add(r.name)
 
-		// writeln := func(s string) {
-		// 	// fmt.Println(">", s)
-		// 	for _, c := range s {
-		// 		in <- int(c)
-		// 	}
-		// 	in <- '\n'
-		// }
-
 		take := func(i string) {
 			if slices.Index(blacklist, i) != -1 {
 				return
 			}
 
 			// fmt.Println("> take", i)
-			writeln(in, "take "+i, false)
+			writeln(cpu, "take "+i, false)
 			for i := 0; i < 4; i++ {
-				if line := cpu.readln(); i == 1 {
+				if line := cpu.ReadLine(); i == 1 {
 					fmt.Println(line)
 				}
 			}
@@ -311,7 +351,7 @@ func automap(root *room, cpu *IntCodeCPU, in chan int) dungeon {
 		}
 
 		move := func(d rune) *room {
-			writeln(in, DIRS[d], false)
+			writeln(cpu, DIRS[d], false)
 			rr, _ := getroom(cpu)
 			return rr
 		}
@@ -347,11 +387,11 @@ func automap(root *room, cpu *IntCodeCPU, in chan int) dungeon {
 	return starship
 }
 
-func autogo(cpu *IntCodeCPU, in chan int, starship dungeon, src, dst string) *room {
+func autogo(cpu *intcode.CPU, starship dungeon, src, dst string) *room {
 	fmt.Printf("Autogo: %s -> %s\n", src, dst)
 
 	move := func(d rune) *room {
-		writeln(in, DIRS[d], true)
+		writeln(cpu, DIRS[d], true)
 		r, _ := getroom(cpu)
 		return r
 	}
@@ -391,14 +431,14 @@ func autogo(cpu *IntCodeCPU, in chan int, starship dungeon, src, dst string) *ro
 	return dfs(src, dst)
 }
 
-func breakin(cpu *IntCodeCPU, in chan int, out rune) bool {
+func breakin(cpu *intcode.CPU, out rune) bool {
 	inventory := make([]string, 0, 8)
-	writeln(in, "inv", false)
-	cpu.readln()
-	cpu.readln()
+	writeln(cpu, "inv", false)
+	cpu.ReadLine()
+	cpu.ReadLine()
 INVENTORY:
 	for {
-		line := cpu.readln()
+		line := cpu.ReadLine()
 		switch line {
 		case "Command?":
 			break INVENTORY
@@ -410,19 +450,10 @@ INVENTORY:
 	slices.Sort(inventory)
 	// fmt.Println("inventory:", inventory, len(inventory))
 
-	take := func(i string) {
-		writeln(in, "take "+i, false)
-		for i := 0; i < 4; i++ {
-			if line := cpu.readln(); i == 1 {
-				fmt.Println(line)
-			}
-		}
-	}
-
 	drop := func(i string) {
-		writeln(in, "drop "+i, false)
+		writeln(cpu, "drop "+i, false)
 		for i := 0; i < 4; i++ {
-			if line := cpu.readln(); i == 1 {
+			if line := cpu.ReadLine(); i == 1 {
 				fmt.Println(line)
 			}
 		}
@@ -430,51 +461,39 @@ INVENTORY:
 
 	now := time.Now()
 	tryexit := func() bool {
-		writeln(in, DIRS[out], false)
+		writeln(cpu, DIRS[out], false)
 
-		line := cpu.readln()
+		line := cpu.ReadLine()
 		for i := 0; i < 9; i++ {
-			line = cpu.readln()
+			line = cpu.ReadLine()
 		}
 		if strings.Contains(line, "proceed") {
-			cpu.readln()
-			fmt.Println(cpu.readln())
+			cpu.ReadLine()
+			fmt.Println(cpu.ReadLine())
 			fmt.Println("Time:", time.Since(now))
 			return true
 		}
-		_, _ = getroom(cpu)
 
 		return false
 	}
 
-	for _, x := range inventory[4:] {
-		drop(x)
-	}
-
-	combs := mkcombs(len(inventory), 4)
-	cur, combs := combs[0], combs[1:]
-
-	for _, new := range combs[1:] {
-		if tryexit() {
-			return true
-		}
-
-		for _, x := range cur {
-			if slices.Index(new, x) == -1 {
-				drop(inventory[x])
-				break
+	// every combination starts over from the full inventory
+	base := cpu.Snapshot()
+	for k := len(inventory); k > 0; k-- {
+		for _, comb := range mkcombs(len(inventory), k) {
+			cpu.Restore(base)
+			for i, x := range inventory {
+				if slices.Index(comb, i) == -1 {
+					drop(x)
+				}
 			}
-		}
 
-		for _, x := range new {
-			if slices.Index(cur, x) == -1 {
-				take(inventory[x])
-				break
+			if tryexit() {
+				return true
 			}
 		}
-
-		cur = new
 	}
+	cpu.Restore(base)
 
 	return false
 }
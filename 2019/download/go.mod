@@ -1,3 +0,0 @@
-module github.com/erik-adelbert/aoc/2022/download
-
-go 1.21.1
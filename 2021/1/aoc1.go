@@ -13,6 +13,7 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 )
@@ -21,12 +22,25 @@ import (
 const MaxInt = int(^uint(0) >> 1)
 
 func main() {
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1) // part1
+		fmt.Println(res.Part2) // part2
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
 	old1, old2, old3 := MaxInt, MaxInt, MaxInt // 3 last depths window
 
+	depths, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
+
 	n1, n2 := 0, 0
-	input := bufio.NewScanner(os.Stdin)
-	for input.Scan() {
-		cur, _ := strconv.Atoi(input.Text())
+	for _, cur := range depths {
 		if old1 < cur { // increase!
 			n1++
 		}
@@ -35,11 +49,21 @@ func main() {
 		}
 		old1, old2, old3 = cur, old1, old2 // shift/update window
 	}
-	fmt.Println(n1) // part1
-	fmt.Println(n2) // part2
+
+	return Result{n1, n2}, nil
 }
 
-func atoi(s string) (n int) {
-	n, _ = strconv.Atoi(s)
-	return
+// parse reads the sonar depths, one per line
+func parse(r io.Reader) ([]int, error) {
+	var depths []int
+
+	input := bufio.NewScanner(r)
+	for input.Scan() {
+		depth, err := strconv.Atoi(input.Text())
+		if err != nil {
+			return nil, err
+		}
+		depths = append(depths, depth)
+	}
+	return depths, input.Err()
 }
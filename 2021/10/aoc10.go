@@ -12,12 +12,24 @@ package main
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"sort"
 )
 
 func main() {
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1) // part1
+		fmt.Println(res.Part2) // part2
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
 	stack := make([]byte, 0, 128)
 
 	empty := func() bool {
@@ -45,12 +57,17 @@ func main() {
 		2: {')': 1, ']': 2, '}': 3, '>': 4},         // for part2
 	}
 
+	lines, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
+
 	scores := make([]int64, 0, 128)
-	sum, input := int64(0), bufio.NewScanner(os.Stdin)
+	sum := int64(0)
 SCAN:
-	for input.Scan() {
+	for _, line := range lines {
 		stack = stack[:0] // reset
-		for _, b := range input.Bytes() {
+		for _, b := range line {
 			if v := closing[b]; v > 0 {
 				push(v)
 			} else if a := pop(); a != b { // discard corrupted
@@ -68,8 +85,22 @@ SCAN:
 		}
 	}
 
-	fmt.Println(sum) // part1
-
 	sort.Slice(scores, func(i, j int) bool { return scores[i] < scores[j] })
-	fmt.Println(scores[len(scores)/2]) // median, part2
+
+	return Result{sum, scores[len(scores)/2]}, nil // median, part2
+}
+
+// parse reads the navigation subsystem, lines of brackets
+func parse(r io.Reader) ([][]byte, error) {
+	var lines [][]byte
+
+	input := bufio.NewScanner(r)
+	for input.Scan() {
+		line := input.Bytes()
+		if len(bytes.Trim(line, "()[]{}<>")) > 0 {
+			return nil, fmt.Errorf("bad chunk line %q", line)
+		}
+		lines = append(lines, bytes.Clone(line))
+	}
+	return lines, input.Err()
 }
@@ -12,7 +12,9 @@ package main
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 )
@@ -115,17 +117,19 @@ func (c *cave) cascade(glob, cur blast) int {
 }
 
 func main() {
-	ctob := func(b byte) byte {
-		return b - '0' // fast convert
-	}
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1) // part1
+		fmt.Println(res.Part2) // part2
+	}))
+}
 
-	cave := newCave(10, 10)
-	j, input := 0, bufio.NewScanner(os.Stdin)
-	for input.Scan() {
-		for i, c := range input.Bytes() {
-			cave.cells[j*10+i] = ctob(c)
-		}
-		j++
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	cave, err := parse(r)
+	if err != nil {
+		return Result{}, err
 	}
 
 	var i, popcnt int
@@ -133,10 +137,34 @@ func main() {
 		popcnt += flash(cave)
 		i++
 	}
-	fmt.Println(popcnt) // part1
 
 	for flash(cave) != cave.popcount() { // while not all flashing
 		i++
 	}
-	fmt.Println(i + 1) // part2
+
+	return Result{popcnt, i + 1}, nil
+}
+
+// parse reads the 10x10 energy levels of the octopuses
+func parse(r io.Reader) (*cave, error) {
+	ctob := func(b byte) byte {
+		return b - '0' // fast convert
+	}
+
+	cave := newCave(10, 10)
+	j, input := 0, bufio.NewScanner(r)
+	for input.Scan() {
+		line := input.Bytes()
+		if j == cave.h || len(line) != cave.w || len(bytes.Trim(line, "0123456789")) > 0 {
+			return nil, fmt.Errorf("bad energy levels %q", line)
+		}
+		for i, c := range line {
+			cave.cells[j*10+i] = ctob(c)
+		}
+		j++
+	}
+	if j != cave.h {
+		return nil, fmt.Errorf("%d rows of energy levels, expected %d", j, cave.h)
+	}
+	return cave, input.Err()
 }
@@ -0,0 +1,13 @@
+package main
+
+import "testing"
+
+func FuzzParse(f *testing.F) {
+	seedExamples(f)
+	fuzzParse(f, reader(parse))
+}
+
+func FuzzCave(f *testing.F) {
+	seedExamples(f)
+	fuzzRoundTrip(f, reader(parse))
+}
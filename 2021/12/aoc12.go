@@ -13,6 +13,7 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"unicode"
@@ -24,6 +25,9 @@ func (i id) String() string {
 	return names[i]
 }
 
+// ncaves bounds the cave count, edges are indexed by cave id
+const ncaves = 12
+
 type edges []int
 
 func (e edges) empty() bool {
@@ -47,7 +51,8 @@ var (
 	nids  map[string]id
 )
 
-func init() {
+// reset clears the graph of a previous Solve
+func reset() {
 	graph = make([][]id, 0, 16)
 	names = make([]string, 0, 16)
 	bigs = make([]bool, 16)
@@ -90,7 +95,7 @@ func adjacency() {
 		}
 		for _, i := range graph[nid] {
 			if adjs[nid] == nil {
-				adjs[nid] = make(edges, 12)
+				adjs[nid] = make(edges, ncaves)
 			}
 			if bigs[i] { // bigs act like teleports
 				for _, j := range graph[i] {
@@ -126,7 +131,7 @@ func dfs(part bool) int {
 		return pop.s, pop.t, pop.d, pop.m
 	}
 
-	hops := make(edges, 12)
+	hops := make(edges, ncaves)
 	for i := range hops {
 		hops[i] = 1
 	}
@@ -155,9 +160,18 @@ func dfs(part bool) int {
 }
 
 func main() {
-	input := bufio.NewScanner(os.Stdin)
-	for input.Scan() {
-		link(strings.Split(input.Text(), "-"))
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1)
+		fmt.Println(res.Part2)
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	if _, err := parse(r); err != nil {
+		return Result{}, err
 	}
 	adjacency()
 
@@ -166,6 +180,24 @@ func main() {
 		part2 = !part1
 	)
 
-	fmt.Println(dfs(part1))
-	fmt.Println(dfs(part2))
+	return Result{dfs(part1), dfs(part2)}, nil
+}
+
+// parse reads the cave links into the graph, it returns the cave names
+func parse(r io.Reader) ([]string, error) {
+	reset()
+
+	input := bufio.NewScanner(r)
+	for input.Scan() {
+		args := strings.Split(input.Text(), "-")
+		if len(args) != 2 || args[0] == "" || args[1] == "" {
+			return nil, fmt.Errorf("bad link %q", input.Text())
+		}
+
+		link(args)
+		if len(names) > ncaves {
+			return nil, fmt.Errorf("more than %d caves", ncaves)
+		}
+	}
+	return names, input.Err()
 }
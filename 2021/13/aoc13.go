@@ -13,6 +13,7 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"strings"
@@ -29,7 +30,20 @@ type vec struct { // dot or axis
 }
 
 func main() {
-	dots := make([]vec, 0, 1024)
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1) // part1
+		fmt.Println(res.Part2) // part2
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	dots, folds, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
 
 	fold := func(a byte, n int) { // fold along axis (x|y) = n
 		for i, d := range dots {
@@ -43,8 +57,31 @@ func main() {
 		}
 	}
 
-	first := true
-	input := bufio.NewScanner(os.Stdin)
+	first, count := true, 0
+	for _, f := range folds {
+		fold(f.a, f.n)
+
+		if first {
+			first = false
+			_, frame := frame(dots)
+			count = len(frame) // part1
+		}
+	}
+
+	return Result{count, display(dots)}, nil // part2
+}
+
+// folding is a fold instruction, along axis a = n
+type folding struct {
+	a byte // x|y
+	n int
+}
+
+// parse reads the dots (coded) then the fold instructions (decode)
+func parse(r io.Reader) (dots []vec, folds []folding, err error) {
+	dots = make([]vec, 0, 1024)
+
+	input := bufio.NewScanner(r)
 	for input.Scan() {
 		line := input.Text()
 		if args := strings.Split(line, ","); len(args) > 1 { // dots (coded)
@@ -53,19 +90,15 @@ func main() {
 			dots = append(dots, vec{x, y})
 		}
 		if args := strings.Split(line, "="); len(args) > 1 { // fold (decode)
+			if !strings.HasSuffix(args[0], "x") && !strings.HasSuffix(args[0], "y") {
+				return nil, nil, fmt.Errorf("bad fold %q", line)
+			}
 			a := args[0][len(args[0])-1] // a = (x|y)
 			n, _ := strconv.Atoi(args[1])
-			fold(a, n)
-
-			if first {
-				first = false
-				_, frame := frame(dots)
-				fmt.Println(len(frame)) // part1
-			}
+			folds = append(folds, folding{a, n})
 		}
 	}
-
-	display(dots) // part2
+	return dots, folds, input.Err()
 }
 
 func frame(dots []vec) (bbox, map[vec]int) {
@@ -77,7 +110,7 @@ func frame(dots []vec) (bbox, map[vec]int) {
 	return b, frame
 }
 
-func display(dots []vec) {
+func display(dots []vec) string {
 	const (
 		Black = ' '
 		White = '\uFFFD' // undefined is very bright
@@ -98,9 +131,11 @@ func display(dots []vec) {
 		fb[d.y][d.x] = White // light up! could use color LUT
 	}
 
-	for _, r := range fb { // scan display
-		fmt.Println(string(r))
+	rows := make([]string, len(fb))
+	for i, r := range fb { // scan display
+		rows[i] = string(r)
 	}
+	return strings.Join(rows, "\n")
 }
 
 type bbox struct { // aabb
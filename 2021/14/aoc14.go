@@ -13,6 +13,7 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 )
@@ -24,11 +25,6 @@ var (
 	cache map[string][]histo
 )
 
-func init() {
-	rules = make(map[string]byte)
-	cache = make(map[string][]histo)
-}
-
 func merge(a, b histo) histo {
 	for k, v := range b {
 		a[k] += v
@@ -54,22 +50,30 @@ func popcnt(rule string, depth int) histo {
 }
 
 func main() {
-	var seed []byte
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1) // part1
+		fmt.Println(res.Part2) // part2
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
 
 	const (
 		depth1 = 10
 		depth2 = 40
 	)
 
-	input := bufio.NewScanner(os.Stdin)
-	for input.Scan() {
-		line := input.Text()
-		if args := strings.Split(line, " -> "); len(args) == 2 {
-			rules[args[0]] = args[1][0]
-			cache[args[0]] = make([]histo, depth2+1) // allocate cache space to accommodate for new rule
-		} else if line != "" {
-			seed = []byte(line)
-		}
+	var seed []byte
+	seed, rules, err = parse(r)
+	if err != nil {
+		return Result{}, err
+	}
+
+	cache = make(map[string][]histo, len(rules))
+	for pair := range rules {
+		cache[pair] = make([]histo, depth2+1) // allocate cache space to accommodate for new rule
 	}
 
 	extent := func(depth int) int64 {
@@ -85,8 +89,46 @@ func main() {
 		return max - min
 	}
 
-	fmt.Println(extent(depth1)) // part1
-	fmt.Println(extent(depth2)) // part2
+	return Result{extent(depth1), extent(depth2)}, nil
+}
+
+// parse reads the polymer template and the pair insertion rules, every
+// pair of the template and of the rules products has a rule
+func parse(r io.Reader) (seed []byte, rules map[string]byte, err error) {
+	rules = make(map[string]byte)
+
+	input := bufio.NewScanner(r)
+	for input.Scan() {
+		line := input.Text()
+		if args := strings.Split(line, " -> "); len(args) == 2 {
+			if len(args[0]) != 2 || len(args[1]) != 1 {
+				return nil, nil, fmt.Errorf("bad rule %q", line)
+			}
+			rules[args[0]] = args[1][0]
+		} else if line != "" {
+			seed = []byte(line)
+		}
+	}
+	if len(seed) < 2 {
+		return nil, nil, fmt.Errorf("polymer template %q too short", seed)
+	}
+
+	// the insertions must stay within the rules
+	for i := range seed[:len(seed)-1] {
+		if _, ok := rules[string(seed[i:i+2])]; !ok {
+			return nil, nil, fmt.Errorf("no rule for pair %q", seed[i:i+2])
+		}
+	}
+	for pair, b := range rules {
+		l, r := string([]byte{pair[0], b}), string([]byte{b, pair[1]})
+		if _, ok := rules[l]; !ok {
+			return nil, nil, fmt.Errorf("no rule for pair %q", l)
+		}
+		if _, ok := rules[r]; !ok {
+			return nil, nil, fmt.Errorf("no rule for pair %q", r)
+		}
+	}
+	return seed, rules, input.Err()
 }
 
 func extrema(m histo) (int64, int64) {
@@ -12,36 +12,19 @@ package main
 
 import (
 	"bufio"
-	hp "container/heap"
+	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"strings"
+
+	"github.com/erik-adelbert/aoc/lib/heap"
 )
 
 type cell struct {
 	y, x, v int
 }
 
-type heap []*cell
-
-func (h heap) Len() int { return len(h) }
-
-func (h heap) Less(i, j int) bool { return h[i].v < h[j].v }
-
-func (h heap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
-
-func (h *heap) Push(x interface{}) {
-	c := x.(cell)
-	*h = append(*h, &c)
-}
-
-func (h *heap) Pop() interface{} {
-	q, i := *h, len(*h)-1
-	pop := q[i]
-	*h, q[i] = q[:i], nil
-	return pop
-}
-
 const N = 128
 
 type grid struct {
@@ -94,14 +77,13 @@ func safest(g *grid, factor int) int {
 	δy := []int{+0, 1, 0, -1}
 	δx := []int{-1, 0, 1, +0}
 
-	heap := make(heap, 0, 1024)
-	hp.Init(&heap)
+	q := heap.New(func(a, b cell) bool { return a.v < b.v }, 1024)
 
-	hp.Push(&heap, cell{})
+	q.Push(cell{})
 	dist[0] = g.get(0, 0)
 
-	for heap.Len() > 0 {
-		v := hp.Pop(&heap).(*cell)
+	for q.Len() > 0 {
+		v := q.Pop()
 		dist[(h*v.y)+v.x] = v.v
 
 		if v.y == h-1 && v.x == w-1 {
@@ -116,7 +98,7 @@ func safest(g *grid, factor int) int {
 
 			if dist[(h*u.y)+u.x] > dist[(h*v.y)+v.x]+g.get(u.y, u.x) {
 				dist[(h*u.y)+u.x] = dist[(h*v.y)+v.x] + g.get(u.y, u.x)
-				hp.Push(&heap, cell{u.y, u.x, dist[(h*u.y)+u.x]})
+				q.Push(cell{u.y, u.x, dist[(h*u.y)+u.x]})
 			}
 		}
 	}
@@ -125,19 +107,48 @@ func safest(g *grid, factor int) int {
 }
 
 func main() {
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1) // part1
+		fmt.Println(res.Part2) // part2
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	cave, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
+
+	return Result{safest(cave, 1), safest(cave, 5)}, nil
+}
+
+// parse reads the square map of risk levels
+func parse(r io.Reader) (*grid, error) {
 	cave := newGrid()
 
-	j, input := 0, bufio.NewScanner(os.Stdin)
+	j, w, input := 0, 0, bufio.NewScanner(r)
 	for input.Scan() {
-		for i, b := range input.Bytes() {
+		line := input.Bytes()
+		if j == 0 {
+			w = len(line)
+		}
+		if j == N || len(line) != w || w > N || len(bytes.Trim(line, "0123456789")) > 0 {
+			return nil, fmt.Errorf("bad risk levels %q", line)
+		}
+		for i, b := range line {
 			cave.d[j*N+i] = int(b - '0')
 		}
 		j++
 	}
+	if j == 0 || j != w {
+		return nil, fmt.Errorf("%dx%d risk levels, expected a square map", j, w)
+	}
 	cave.redim(j, j)
 
-	fmt.Println(safest(cave, 1)) // part1
-	fmt.Println(safest(cave, 5)) // part2
+	return cave, input.Err()
 }
 
 func min(a, b int) int {
@@ -13,14 +13,16 @@ package main
 import (
 	"bufio"
 	"bytes"
+	"errors"
 	"fmt"
 	"math/big"
 	"os"
 
 	bs "github.com/bearmini/bitstream-go"
+	"io"
 )
 
-var nread uint // global bit count
+var nread, nbits uint // global bit count, out of the transmission bits
 
 type seg struct { // datagram segment
 	ver uint8
@@ -61,7 +63,10 @@ func subs(r *bs.Reader) []seg {
 
 	subs := make([]seg, 0, 16)
 	for {
-		sub := load(r) // recursive loading
+		sub := load(r)  // recursive loading
+		if sub == nil { // truncated
+			break
+		}
 		subs = append(subs, sub...)
 		nsub--
 
@@ -87,6 +92,10 @@ const ( // cmd map
 func load(r *bs.Reader) []seg {
 	var segs []seg
 
+	if nread+6 > nbits { // truncated
+		return nil
+	}
+
 	ver, _ := r.ReadNBitsAsUint8(3)
 	nread += 3
 	typ, _ := r.ReadNBitsAsUint8(3)
@@ -156,15 +165,46 @@ func sum(dgram []seg) int {
 }
 
 func main() {
-	n, input := new(big.Int), bufio.NewScanner(os.Stdin)
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1)
+		fmt.Println(res.Part2)
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	dgram, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
+
+	return Result{sum(dgram), eval(dgram[0])}, nil
+}
+
+// parse reads the hexadecimal transmission into its datagram
+func parse(r io.Reader) ([]seg, error) {
+	n, input := new(big.Int), bufio.NewScanner(r)
 	for input.Scan() {
-		n, _ = n.SetString(input.Text(), 16)
+		var ok bool
+		if n, ok = n.SetString(input.Text(), 16); !ok {
+			return nil, fmt.Errorf("bad transmission %q", input.Text())
+		}
 	}
-	bits := bs.NewReader(bytes.NewReader(n.Bytes()), nil) // go pipeline
-	dgram := load(bits)                                   // datagram from bit stream (singleton)
+	if err := input.Err(); err != nil {
+		return nil, err
+	}
+
+	data := n.Bytes()
+	nread, nbits = 0, uint(8*len(data))
 
-	fmt.Println(sum(dgram))
-	fmt.Println(eval(dgram[0]))
+	bits := bs.NewReader(bytes.NewReader(data), nil) // go pipeline
+	dgram := load(bits)                              // datagram from bit stream (singleton)
+	if dgram == nil || nread > nbits {
+		return nil, errors.New("truncated transmission")
+	}
+	return dgram, nil
 }
 
 // MaxInt and MinInt are defined in the idiomatic way
@@ -12,7 +12,9 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"math"
+	"os"
 )
 
 // Axis
@@ -26,11 +28,8 @@ type (
 	speed [2]int
 )
 
-// target area: x=150..193, y=-136..-86
-var (
-	min = point{150, -136}
-	max = point{193, -86}
-)
+// target area, eg. x=150..193, y=-136..-86
+var min, max point
 
 func hit(v speed) bool {
 	var p point
@@ -58,6 +57,22 @@ func hit(v speed) bool {
 }
 
 func main() {
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1) // part1
+		fmt.Println(res.Part2) // part2
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	area, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
+	min, max = area[0], area[1]
+
 	vmin := speed{
 		int(math.Sqrt(float64(2 * min[X]))), // FPU rules!
 		min[Y],
@@ -68,7 +83,7 @@ func main() {
 		int(math.Abs(float64(min[Y] + 1))), // since we use math...
 	}
 
-	fmt.Println((vmax[Y] + 1) * vmax[Y] / 2) // part1
+	top := (vmax[Y] + 1) * vmax[Y] / 2 // part1
 
 	n := 0
 	for vx := vmin[X]; vx <= vmax[X]; vx++ {
@@ -78,5 +93,13 @@ func main() {
 			}
 		}
 	}
-	fmt.Println(n) // part2
+
+	return Result{top, n}, nil
+}
+
+// parse reads the target area, its min and max corners
+func parse(r io.Reader) (area [2]point, err error) {
+	lo, hi := &area[0], &area[1]
+	_, err = fmt.Fscanf(r, "target area: x=%d..%d, y=%d..%d", &lo[X], &hi[X], &lo[Y], &hi[Y])
+	return
 }
@@ -12,7 +12,9 @@ package main
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"reflect"
 	"strconv"
@@ -92,6 +94,69 @@ func SNum(args ...interface{}) snum {
 	return sn
 }
 
+// parseSNum checks a is a pair, whose elements are pairs or regular numbers,
+// nested no deeper than explode expects before it makes it a snum
+func parseSNum(a []byte) (snum, error) {
+	i := 0
+
+	var pair func(depth int) error
+	elem := func(depth int) error {
+		switch {
+		case i < len(a) && a[i] == '[':
+			return pair(depth + 1)
+		case i < len(a) && '0' <= a[i] && a[i] <= '9':
+			j := i
+			for i < len(a) && '0' <= a[i] && a[i] <= '9' {
+				i++
+			}
+			if _, err := strconv.Atoi(string(a[j:i])); err != nil {
+				return fmt.Errorf("column %d: %w", j+1, err)
+			}
+			return nil
+		}
+		return unexpected(a, i)
+	}
+	pair = func(depth int) error {
+		if depth > 5 {
+			return fmt.Errorf("column %d: pair nested too deep", i+1)
+		}
+		i++ // [
+		if err := elem(depth); err != nil {
+			return err
+		}
+		if i == len(a) || a[i] != ',' {
+			return unexpected(a, i)
+		}
+		i++
+		if err := elem(depth); err != nil {
+			return err
+		}
+		if i == len(a) || a[i] != ']' {
+			return unexpected(a, i)
+		}
+		i++
+		return nil
+	}
+
+	if len(a) == 0 || a[0] != '[' {
+		return snum{}, unexpected(a, 0)
+	}
+	if err := pair(1); err != nil {
+		return snum{}, err
+	}
+	if i < len(a) {
+		return snum{}, unexpected(a, i)
+	}
+	return SNum(tokenize(string(a))), nil
+}
+
+func unexpected(a []byte, i int) error {
+	if i == len(a) {
+		return errors.New("unexpected end of number")
+	}
+	return fmt.Errorf("column %d: unexpected %q", i+1, a[i])
+}
+
 func clone(sn snum) snum {
 	// tedious but faster than append(sn.x[:0:0], sn.x...)
 	cn := snum{
@@ -250,12 +315,19 @@ func (sn snum) String() string {
 }
 
 func main() {
-	args := make([]snum, 0, 128)
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1) // part1
+		fmt.Println(res.Part2) // part2
+	}))
+}
 
-	input := bufio.NewScanner(os.Stdin)
-	for input.Scan() {
-		line := input.Text()
-		args = append(args, SNum(tokenize(line)))
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	args, err := parse(r)
+	if err != nil {
+		return Result{}, err
 	}
 
 	// fmt.Println(args[0])
@@ -287,7 +359,7 @@ func main() {
 	for _, sn := range args {
 		num = reduce(SNum(num, clone(sn)))
 	}
-	fmt.Println(mag(num)) // part1
+	sum := mag(num) // part1
 
 	i, max := 0, 0
 	for n := range mags {
@@ -299,7 +371,29 @@ func main() {
 		}
 	}
 	close(mags)
-	fmt.Println(max) // part2
+
+	return Result{sum, max}, nil
+}
+
+// parse reads the homework, at least two snailfish numbers, one per line
+func parse(r io.Reader) ([]snum, error) {
+	args := make([]snum, 0, 128)
+
+	input := bufio.NewScanner(r)
+	for input.Scan() {
+		sn, err := parseSNum(input.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", len(args)+1, err)
+		}
+		args = append(args, sn)
+	}
+	if err := input.Err(); err != nil {
+		return nil, err
+	}
+	if len(args) < 2 {
+		return nil, errors.New("less than two numbers")
+	}
+	return args, nil
 }
 
 func remove(a []int, i int) ([]int, bool) {
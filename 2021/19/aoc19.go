@@ -12,12 +12,15 @@ package main
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"slices"
 	"sort"
-	"strconv"
 	"strings"
+
+	"github.com/erik-adelbert/aoc/lib/num"
 )
 
 var (
@@ -36,27 +39,19 @@ func init() {
 }
 
 func main() {
-	points := make(reading, 0, 32)
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1) // part1
+		fmt.Println(res.Part2) // part2
+	}))
+}
 
-	input := bufio.NewScanner(os.Stdin)
-	for input.Scan() {
-		switch line := input.Text(); line != "" {
-		default:
-			args := strings.Split(line, ",") // trust input
-			points = append(points, vec{
-				X: atoi(args[0]),
-				Y: atoi(args[1]),
-				Z: atoi(args[2]),
-			})
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
 
-		case strings.HasPrefix(line, "---"):
-			if len(points) > 0 {
-				reads = append(reads, clone(points))
-				points = points[:0] // reset
-			}
-		}
+	if reads, err = parse(r); err != nil {
+		return Result{}, err
 	}
-	reads = append(reads, points) // last reading
 
 	for _, p := range reads[0] { // origin system
 		fixed[p] = true
@@ -74,7 +69,7 @@ func main() {
 		}
 		reads = reads[:i] // retry with pushed back
 	}
-	fmt.Println(len(fixed)) // part1
+	nbeacon := len(fixed) // part1
 
 	diam := 0
 	for i, v0 := range scans {
@@ -84,7 +79,42 @@ func main() {
 			}
 		}
 	}
-	fmt.Println(diam) // part2
+
+	return Result{nbeacon, diam}, nil
+}
+
+// parse reads the scanner reports, the beacons each scanner sees
+func parse(r io.Reader) ([]reading, error) {
+	reads := make([]reading, 0, 32)
+	points := make(reading, 0, 32)
+
+	input := bufio.NewScanner(r)
+	for input.Scan() {
+		switch line := input.Text(); line != "" {
+		default:
+			args := strings.Split(line, ",")
+			if len(args) != 3 {
+				return nil, fmt.Errorf("bad beacon %q", line)
+			}
+			points = append(points, vec{
+				X: num.Atoi(args[0]),
+				Y: num.Atoi(args[1]),
+				Z: num.Atoi(args[2]),
+			})
+
+		case strings.HasPrefix(line, "---"):
+			if len(points) > 0 {
+				reads = append(reads, clone(points))
+				points = points[:0] // reset
+			}
+		}
+	}
+	if len(points) == 0 {
+		return nil, errors.New("empty scanner report")
+	}
+	reads = append(reads, points) // last reading
+
+	return reads, input.Err()
 }
 
 // Axis
@@ -103,7 +133,7 @@ func (v vec) sub(u vec) vec {
 }
 
 func (v vec) manh() int {
-	return abs(v[X]) + abs(v[Y]) + abs(v[Z])
+	return num.Abs(v[X]) + num.Abs(v[Y]) + num.Abs(v[Z])
 }
 
 type reading []vec
@@ -287,15 +317,3 @@ func align(r reading) bool {
 	}
 	return false
 }
-
-func abs(a int) int {
-	if a > 0 {
-		return a
-	}
-	return -a
-}
-
-func atoi(s string) int {
-	n, _ := strconv.Atoi(s) // trust s
-	return n
-}
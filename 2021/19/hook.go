@@ -0,0 +1 @@
+../../hook/hook.go
\ No newline at end of file
@@ -13,33 +13,64 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
-	"strconv"
 	"strings"
+
+	"github.com/erik-adelbert/aoc/lib/num"
 )
 
 func main() {
-	x, y, aim := 0, 0, 0
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1) // part1
+		fmt.Println(res.Part2) // part2
+	}))
+}
 
-	input := bufio.NewScanner(os.Stdin)
-	for input.Scan() {
-		line := input.Text()
-		arg := atoi(strings.Fields(line)[1])
-		switch line[0] {
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	moves, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
+
+	x, y, aim := 0, 0, 0
+	for _, m := range moves {
+		switch m.dir {
 		case 'f': // forward
-			x += arg
-			y += aim * arg
+			x += m.arg
+			y += aim * m.arg
 		case 'u': // up
-			aim -= arg
+			aim -= m.arg
 		case 'd': // down
-			aim += arg
+			aim += m.arg
 		}
 	}
-	fmt.Println(x * aim) // part1
-	fmt.Println(x * y)   // part2
+
+	return Result{x * aim, x * y}, nil
+}
+
+// move is a course command, its direction initial and its argument
+type move struct {
+	dir byte
+	arg int
 }
 
-func atoi(s string) (n int) {
-	n, _ = strconv.Atoi(s)
-	return
+// parse reads the planned course, one command per line
+func parse(r io.Reader) ([]move, error) {
+	var moves []move
+
+	input := bufio.NewScanner(r)
+	for input.Scan() {
+		line := input.Text()
+
+		dir, arg, ok := strings.Cut(line, " ")
+		if !ok || dir != "forward" && dir != "up" && dir != "down" {
+			return nil, fmt.Errorf("bad command %q", line)
+		}
+		moves = append(moves, move{dir[0], num.Atoi(arg)})
+	}
+	return moves, input.Err()
 }
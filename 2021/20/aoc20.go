@@ -12,7 +12,9 @@ package main
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 )
@@ -37,34 +39,79 @@ var (
 )
 
 func main() {
-	for i := range bufs {
-		bufs[i] = newImage()
-	}
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1) // part1
+		fmt.Println(res.Part2) // part2
+	}))
+}
 
-	j, i := 0, MAXLEN
-	input := bufio.NewScanner(os.Stdin)
-	for input.Scan() {
-		bit := strings.NewReplacer(".", "\x00", "#", "\x01")
-		switch len(input.Bytes()) {
-		case 0:
-			// continue
-		case len(kern):
-			copy(kern[:], input.Bytes())
-		default:
-			low, max := slice(j, i)
-			i = copy(bufs[cur].bmap[low:max:max], []byte(bit.Replace(input.Text())))
-			j++
-		}
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	var img *image
+	if kern, img, err = parse(r); err != nil {
+		return Result{}, err
 	}
-	bufs[cur].redim(j, i)
+	bufs[cur], bufs[nxt] = img, newImage()
 
+	var lit int
 	for i := 0; i < 50; i++ {
 		if i == 2 {
-			fmt.Println(bufs[cur].popcnt) // part1
+			lit = bufs[cur].popcnt // part1
 		}
 		enhance()
 	}
-	fmt.Println(bufs[cur].popcnt) // part2
+
+	return Result{lit, bufs[cur].popcnt}, nil
+}
+
+// parse reads the enhancement kernel and the input image
+func parse(r io.Reader) (kernel, *image, error) {
+	var k kernel
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return k, nil, err
+	}
+
+	line, rest, _ := bytes.Cut(data, []byte("\n"))
+	if len(line) != len(k) || len(bytes.Trim(line, ".#")) > 0 {
+		return k, nil, fmt.Errorf("bad enhancement kernel %.16q", line)
+	}
+	copy(k[:], line)
+
+	img, err := parseImage(bytes.TrimLeft(rest, "\n"))
+	return k, img, err
+}
+
+// parseImage reads rows of . and # of the same width, small enough for
+// the image to grow by a pixel on each side for 50 steps
+func parseImage(data []byte) (*image, error) {
+	const grow = 2 * 50
+
+	img, bit := newImage(), strings.NewReplacer(".", "\x00", "#", "\x01")
+
+	j, i := 0, 0
+	input := bufio.NewScanner(bytes.NewReader(data))
+	for input.Scan() {
+		line := input.Bytes()
+		switch {
+		case len(line) == 0, j > 0 && len(line) != i:
+			return nil, fmt.Errorf("image row %d: ragged image", j+1)
+		case len(line)+grow > MAXLEN || j+grow >= MAXLEN:
+			return nil, fmt.Errorf("image larger than %dx%d", MAXLEN-grow, MAXLEN-grow)
+		case len(bytes.Trim(line, ".#")) > 0:
+			return nil, fmt.Errorf("bad image row %q", line)
+		}
+
+		low, max := slice(j, len(line))
+		i = copy(img.bmap[low:max:max], []byte(bit.Replace(string(line))))
+		j++
+	}
+	img.redim(j, i)
+
+	return img, input.Err()
 }
 
 func enhance() {
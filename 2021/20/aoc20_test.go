@@ -0,0 +1,22 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func FuzzParse(f *testing.F) {
+	seedExamples(f)
+	fuzzParse(f, func(data []byte) (*image, error) {
+		_, img, err := parse(bytes.NewReader(data))
+		return img, err
+	})
+}
+
+func FuzzImage(f *testing.F) {
+	for _, data := range examples(f) {
+		_, img, _ := bytes.Cut(data, []byte("\n\n"))
+		f.Add(img)
+	}
+	fuzzRoundTrip(f, parseImage)
+}
@@ -13,6 +13,7 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"strings"
@@ -85,19 +86,49 @@ func play(c [2]uint64) string { // starting cells
 }
 
 func main() {
-	var c [2]uint64 // player start cells
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1) // part1
+		fmt.Println(res.Part2) // part2
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	c, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
+
+	score := play(c) // part1
+
+	stats := solve(game{c[p1], 0, c[p2], 0}) // solve all games
+
+	return Result{score, max(stats[p1], stats[p2])}, nil
+}
 
-	i, input := 0, bufio.NewScanner(os.Stdin)
+// parse reads the player start cells
+func parse(r io.Reader) ([2]uint64, error) {
+	var c [2]uint64
+
+	i, input := 0, bufio.NewScanner(r)
 	for input.Scan() {
 		line := input.Text()
-		args := strings.Split(line, ": ")
-		n, _ := strconv.ParseUint(args[1], 10, 64)
+		_, arg, ok := strings.Cut(line, ": ")
+		if i == len(c) || !ok {
+			return c, fmt.Errorf("bad player line %q", line)
+		}
+
+		n, err := strconv.ParseUint(arg, 10, 64)
+		if err != nil || n < 1 || n > 10 {
+			return c, fmt.Errorf("bad start cell %q", arg)
+		}
 		c[i] = n
 		i++
 	}
-
-	fmt.Println(play(c)) // part1
-
-	stats := solve(game{c[p1], 0, c[p2], 0}) // solve all games
-	fmt.Println(max(stats[p1], stats[p2]))   // part2
+	if i != len(c) {
+		return c, fmt.Errorf("%d players, expected %d", i, len(c))
+	}
+	return c, input.Err()
 }
@@ -13,6 +13,7 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"regexp"
 	"strconv"
@@ -214,14 +215,55 @@ func recount(n *node) int64 {
 }
 
 func main() {
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1) // part1
+		fmt.Println(res.Part2) // part2
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(rd io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	steps, err := parse(rd)
+	if err != nil {
+		return Result{}, err
+	}
+
 	var p1, p2 *node
+	for _, s := range steps {
+		part1 := cubo{vec{-50, -50, -50}, vec{50, 50, 50}}
+		if sub := s.c.trim(part1); sub.ok() {
+			p1 = reboot(p1, s.on, sub)
+		}
+
+		p2 = reboot(p2, s.on, s.c)
+	}
+
+	return Result{recount(p1), recount(p2)}, nil
+}
+
+// step is a reboot step, it turns the cuboid c on or off
+type step struct {
+	on bool
+	c  cubo
+}
+
+// parse reads the reboot steps
+func parse(rd io.Reader) ([]step, error) {
+	var steps []step
+
 	r := regexp.MustCompile(
 		`(on|off) x=(-?\d+)..(-?\d+),y=(-?\d+)..(-?\d+),z=(-?\d+)..(-?\d+)`,
 	)
 
-	input := bufio.NewScanner(os.Stdin)
+	input := bufio.NewScanner(rd)
 	for input.Scan() {
-		matches := r.FindAllStringSubmatch(input.Text(), -1)[0]
+		matches := r.FindStringSubmatch(input.Text())
+		if matches == nil {
+			return nil, fmt.Errorf("bad reboot step %q", input.Text())
+		}
+
 		args := make([]int, 6)
 		for i := 0; i < 6; i++ {
 			args[i], _ = strconv.Atoi(matches[i+2])
@@ -231,17 +273,9 @@ func main() {
 			vec{args[0], args[2], args[4]},
 			vec{args[1], args[3], args[5]},
 		}
-
-		part1 := cubo{vec{-50, -50, -50}, vec{50, 50, 50}}
-		if sub := c.trim(part1); sub.ok() {
-			p1 = reboot(p1, on, sub)
-		}
-
-		p2 = reboot(p2, on, c)
+		steps = append(steps, step{on, c})
 	}
-
-	fmt.Println(recount(p1)) // part1
-	fmt.Println(recount(p2)) // part2
+	return steps, input.Err()
 }
 
 func min(a, b vec) vec {
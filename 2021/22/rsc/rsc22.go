@@ -3,13 +3,14 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"strings"
 )
 
 func main() {
-	data, _ := os.ReadFile("input.txt")
+	data, _ := io.ReadAll(os.Stdin)
 	text := strings.Fields(r.Replace(string(data)))
 
 	type dim struct {
@@ -44,21 +45,33 @@ func main() {
 
 	remap := new([3][2 * Off]int)
 	width := new([3][N]int)
+	start := new([3][N]int)
 	for _, f := range prog {
 		for i, d := range f.p {
 			remap[i][d.min] = 1
 			remap[i][d.max] = 1
 		}
 	}
+	for i := range remap {
+		// part 1 bounds, a cell is either in or out of the region
+		remap[i][Off-50] = 1
+		remap[i][Off+51] = 1
+	}
 
 	for i := range remap {
 		t := 0
 		for j, v := range &remap[i] {
 			t += v
+			if v == 1 {
+				start[i][t] = j - Off
+			}
 			remap[i][j] = t
 			width[i][t]++
 		}
 	}
+	in := func(i, t int) bool {
+		return -50 <= start[i][t] && start[i][t] <= 50
+	}
 	for _, f := range prog {
 		for i, d := range f.p {
 			f.p[i] = dim{remap[i][d.min], remap[i][d.max]}
@@ -75,15 +88,19 @@ func main() {
 			}
 		}
 	}
-	total := 0
+	total1, total := 0, 0
 	for x := range sw {
 		for y := range sw {
 			for z := range sw {
-				total += width[0][x] * width[1][y] * width[2][z] * int(sw[x][y][z])
+				n := width[0][x] * width[1][y] * width[2][z] * int(sw[x][y][z])
+				total += n
+				if n > 0 && in(0, x) && in(1, y) && in(2, z) {
+					total1 += n
+				}
 			}
 		}
 	}
-	fmt.Println(total)
+	fmt.Println(total1, total)
 }
 
 func atoi(s string) int {
@@ -14,7 +14,9 @@ import (
 	"bufio"
 	"bytes"
 	hp "container/heap"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 )
@@ -72,14 +74,71 @@ type (
 
 // main entry point
 func main() {
-	input := bufio.NewScanner(os.Stdin)
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1)
+		fmt.Println(res.Part2)
+	}))
+}
 
-	// part#1,2
-	parts := mkburos(input)
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	parts, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
+
+	var costs [2]cost
 	for p := range parts {
 		start := newState(&parts[p], 0)
-		fmt.Println(start.solve())
+		costs[p] = start.solve()
+	}
+
+	return Result{costs[0], costs[1]}, nil
+}
+
+// parse reads the burrow of part#1 and unfolds it for part#2, ? stands
+// for a pawn and each pawn comes twice
+func parse(r io.Reader) ([]buro, error) {
+	shape := []string{
+		"#############",
+		"#...........#",
+		"###?#?#?#?###",
+		"  #?#?#?#?#",
+		"  #########",
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var pawns [4]int
+	rows := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(rows) != len(shape) {
+		return nil, fmt.Errorf("%d burrow rows, expected %d", len(rows), len(shape))
+	}
+	for j, row := range rows {
+		row = strings.TrimRight(row, " ")
+		if len(row) != len(shape[j]) {
+			return nil, fmt.Errorf("bad burrow row %q", row)
+		}
+		for i := range row {
+			switch c := row[i]; {
+			case shape[j][i] == '?' && 'A' <= c && c <= 'D':
+				pawns[c-'A']++
+			case shape[j][i] != c:
+				return nil, fmt.Errorf("bad burrow row %q", row)
+			}
+		}
+	}
+	if pawns != [...]int{2, 2, 2, 2} {
+		return nil, errors.New("each pawn should come twice")
 	}
+
+	// part#1,2
+	return mkburos(bufio.NewScanner(bytes.NewReader(data))), nil
 }
 
 // uncomment and fix for runtime basic metrics
@@ -243,3 +243,8 @@ func BenchmarkFNV1HashmapRoundTrip(b *testing.B) {
 
 	result = r
 }
+
+func FuzzParse(f *testing.F) {
+	seedExamples(f)
+	fuzzParse(f, reader(parse))
+}
@@ -8,24 +8,33 @@
 // -------------------------------------------
 // 2021-12-24: initial commit
 
-//
 package main
 
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"strings"
+
+	"github.com/erik-adelbert/aoc/lib/num"
 )
 
 func main() {
-	code := make([]string, 0, 256)
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1) // part1
+		fmt.Println(res.Part2) // part2
+	}))
+}
 
-	input := bufio.NewScanner(os.Stdin)
-	for input.Scan() {
-		line := input.Text()
-		code = append(code, line)
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	blocks, err := parse(r)
+	if err != nil {
+		return Result{}, err
 	}
 
 	type item struct {
@@ -47,13 +56,8 @@ func main() {
 	p := 99999999999999
 	q := 11111111111111
 
-	for i := 0; i < 14; i++ {
-		arg := strings.Fields(code[18*i+5])
-		a, _ := strconv.Atoi(arg[len(arg)-1])
-
-		arg = strings.Fields(code[18*i+15])
-		b, _ := strconv.Atoi(arg[len(arg)-1])
-
+	for i, ab := range blocks {
+		a, b := ab[0], ab[1]
 		if a > 0 {
 			push(i, b)
 		} else {
@@ -67,19 +71,66 @@ func main() {
 				xq += i - j
 			}
 
-			p -= abs(a+b) * pow(10, xp)
-			q += abs(a+b) * pow(10, xq)
+			p -= num.Abs(a+b) * pow(10, xp)
+			q += num.Abs(a+b) * pow(10, xq)
 		}
 	}
-	fmt.Println(p) // part1
-	fmt.Println(q) // part2
+
+	return Result{p, q}, nil
 }
 
-func abs(a int) int {
-	if a < 0 {
-		return -a
+// parse reads the MONAD program, 14 blocks of 18 instructions, one per
+// digit, and keeps their constants: the x offset a, which pops a digit when
+// it is not positive, and the y offset b
+func parse(r io.Reader) ([14][2]int, error) {
+	var blocks [14][2]int
+
+	code := make([]string, 0, 256)
+
+	input := bufio.NewScanner(r)
+	for input.Scan() {
+		line := input.Text()
+		code = append(code, line)
+	}
+	if err := input.Err(); err != nil {
+		return blocks, err
+	}
+	if len(code) != 18*len(blocks) {
+		return blocks, fmt.Errorf("%d instructions, expected %d", len(code), 18*len(blocks))
+	}
+
+	// arg reads the last operand of instruction i, a number
+	arg := func(i int) (int, error) {
+		args := strings.Fields(code[i])
+		if len(args) == 0 {
+			return 0, fmt.Errorf("line %d: empty instruction", i+1)
+		}
+		n, err := strconv.Atoi(args[len(args)-1])
+		if err != nil {
+			return 0, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		return n, nil
+	}
+
+	depth := 0 // pushed digits
+	for i := range blocks {
+		a, err := arg(18*i + 5)
+		if err != nil {
+			return blocks, err
+		}
+		b, err := arg(18*i + 15)
+		if err != nil {
+			return blocks, err
+		}
+
+		if a > 0 {
+			depth++
+		} else if depth--; depth < 0 {
+			return blocks, fmt.Errorf("block %d pops an empty stack", i+1)
+		}
+		blocks[i] = [2]int{a, b}
 	}
-	return a
+	return blocks, nil
 }
 
 func pow(a, n int) int {
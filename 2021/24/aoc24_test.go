@@ -0,0 +1,8 @@
+package main
+
+import "testing"
+
+func FuzzParse(f *testing.F) {
+	seedExamples(f)
+	fuzzParse(f, reader(parse))
+}
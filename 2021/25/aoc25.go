@@ -12,32 +12,42 @@ package main
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 )
 
-const (
-	w = 139
-	h = 137
-)
-
-type board [w * h]byte
+// board holds the sea cucumbers, rows of w cells
+type board struct {
+	cells []byte
+	h, w  int
+}
 
 func main() {
-	var nxt board
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1)
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
 
-	input := bufio.NewScanner(os.Stdin)
-	for j := 0; input.Scan(); j++ {
-		low, max := slice(j)
-		copy(nxt[low:max:max], input.Bytes())
+	nxt, err := parse(r)
+	if err != nil {
+		return Result{}, err
 	}
+	h, w := nxt.h, nxt.w
+
+	cur := &board{make([]byte, len(nxt.cells)), h, w}
 
 	s, n := 0, 1 // step, change count
 	for n > 0 {
 		s, n = s+1, 0 // advance step, reset change count
 
-		cur := nxt
+		copy(cur.cells, nxt.cells)
 		// east scan
 		for j := 0; j < h; j++ {
 			crow := cur.row(j)
@@ -51,7 +61,7 @@ func main() {
 			}
 		}
 
-		cur = nxt
+		copy(cur.cells, nxt.cells)
 		// south scan
 		for j := 0; j < h; j++ {
 			jj := (j + 1) % h
@@ -65,29 +75,38 @@ func main() {
 			}
 		}
 	}
-	fmt.Println(s)
-}
 
-func at(j, i int) int {
-	return j*w + i
+	return Result{s, nil}, nil
 }
 
-func slice(j int) (low, max int) {
-	low = j * w
-	max = low + w
-	return
+// parse reads the sea cucumbers map, rows of the same width
+func parse(r io.Reader) (*board, error) {
+	b := new(board)
+
+	input := bufio.NewScanner(r)
+	for input.Scan() {
+		row := input.Bytes()
+		if b.h == 0 {
+			b.w = len(row)
+		}
+		if len(row) == 0 || len(row) != b.w || len(bytes.Trim(row, ".>v")) > 0 {
+			return nil, fmt.Errorf("bad map row %q", row)
+		}
+		b.cells = append(b.cells, row...)
+		b.h++
+	}
+	return b, input.Err()
 }
 
 func (b *board) row(j int) []byte {
-	low, max := slice(j)
-	return b[low:max:max]
+	low, max := j*b.w, (j+1)*b.w
+	return b.cells[low:max:max]
 }
 
 func (b *board) String() string {
 	var sb strings.Builder
-	for j := 0; j < h; j++ {
-		low, max := slice(j)
-		sb.Write(b[low:max:max])
+	for j := 0; j < b.h; j++ {
+		sb.Write(b.row(j))
 		sb.WriteByte('\n')
 	}
 
@@ -13,8 +13,10 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
+	"strings"
 )
 
 const (
@@ -64,12 +66,19 @@ func rate(nums []string, g gas) (int64, error) {
 }
 
 func main() {
-	nums := make([]string, 0, 1024)
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1) // part1
+		fmt.Println(res.Part2) // part2
+	}))
+}
 
-	input := bufio.NewScanner(os.Stdin)
-	for input.Scan() {
-		line := input.Text()
-		nums = append(nums, line)
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	nums, err := parse(r)
+	if err != nil {
+		return Result{}, err
 	}
 
 	rates := make(chan int64)
@@ -93,11 +102,28 @@ func main() {
 		}
 	}
 	γ := ^ε & bitmask
-	fmt.Println(ε * γ) // part1
+	power := ε * γ
 
 	n := int64(1)
 	for i := 0; i < 2; i++ {
 		n *= <-rates
 	}
-	fmt.Println(n) // part2
+
+	return Result{power, n}, nil
+}
+
+// parse reads the diagnostic report, binary numbers of at most width bits
+// kept as text
+func parse(r io.Reader) ([]string, error) {
+	nums := make([]string, 0, 1024)
+
+	input := bufio.NewScanner(r)
+	for input.Scan() {
+		line := input.Text()
+		if line == "" || len(line) > width || strings.Trim(line, "01") != "" {
+			return nil, fmt.Errorf("bad report number %q", line)
+		}
+		nums = append(nums, line)
+	}
+	return nums, input.Err()
 }
@@ -13,6 +13,7 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"strings"
@@ -62,11 +63,51 @@ func (ca *card) sum() int {
 }
 
 func main() {
-	draw := make([]int, 0, 128)
-	deck := make([]*card, 0, 128)
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1) // part1
+		fmt.Println(res.Part2) // part2
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	draw, deck, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
+	stack = stack[:0] // wins of a previous Solve
+
+	for _, n := range draw {
+		i := 0
+		for _, card := range deck {
+			if card.biff(n) { // win
+				push(n, card) // move to the stack
+				continue
+			}
+			deck[i] = card
+			i++
+		}
+		deck = deck[:i]
+	}
+
+	n, c := deq() // part1: first win
+	first := n * c.sum()
+
+	n, c = pop() // part2: last win
+	last := n * c.sum()
+
+	return Result{first, last}, nil
+}
+
+// parse reads the drawn numbers and the bingo cards
+func parse(r io.Reader) (draw []int, deck []*card, err error) {
+	draw = make([]int, 0, 128)
+	deck = make([]*card, 0, 128)
 	cur, row := newCard(), 0
 
-	input := bufio.NewScanner(os.Stdin)
+	input := bufio.NewScanner(r)
 	for input.Scan() {
 		line := input.Text()
 		args := strings.Fields(line)
@@ -77,12 +118,18 @@ func main() {
 				cur, row = newCard(), 0
 			}
 		case 5: // cardboard row
+			if row == 5 {
+				return nil, nil, fmt.Errorf("card longer than 5 rows at %q", line)
+			}
 			for col, s := range args {
 				n, _ := strconv.Atoi(s)
 				cur.add(n, row, col)
 			}
 			row++
 		default: // first line
+			if len(args) > 1 || len(draw) > 0 {
+				return nil, nil, fmt.Errorf("bad line %q", line)
+			}
 			args = strings.Split(args[0], ",")
 			for _, s := range args {
 				n, _ := strconv.Atoi(s)
@@ -93,25 +140,7 @@ func main() {
 	if row > 0 { // no newline at eof
 		deck = append(deck, cur)
 	}
-
-	for _, n := range draw {
-		i := 0
-		for _, card := range deck {
-			if card.biff(n) { // win
-				push(n, card) // move to the stack
-				continue
-			}
-			deck[i] = card
-			i++
-		}
-		deck = deck[:i]
-	}
-
-	n, c := deq() // part1: first win
-	fmt.Println(n * c.sum())
-
-	n, c = pop() // part2: last win
-	fmt.Println(n * c.sum())
+	return draw, deck, input.Err()
 }
 
 // stack!! heavy but easy & reliable
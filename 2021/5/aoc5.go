@@ -13,6 +13,7 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"strings"
@@ -83,16 +84,51 @@ func popcounts() (int, int) {
 }
 
 func main() {
-	input := bufio.NewScanner(os.Stdin)
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2) // part1 & part2
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	vents, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
+	can1, can2 = canvas{}, canvas{} // plots of a previous Solve
+
+	for _, v := range vents {
+		plot(v[0], v[1])
+	}
+
+	p1, p2 := popcounts()
+
+	return Result{p1, p2}, nil
+}
+
+// parse reads the lines of vents, their ends on the canvas
+func parse(r io.Reader) ([][2]point, error) {
+	var vents [][2]point
+
+	input := bufio.NewScanner(r)
 	for input.Scan() {
 		line := strings.Replace(input.Text(), "->", ",", 1)
 		args := strings.Split(line, ",")
+		if len(args) != 4 {
+			return nil, fmt.Errorf("bad vent line %q", input.Text())
+		}
 
 		a, b := Point(args[0], args[1]), Point(args[2], args[3])
-		plot(a, b)
+		for _, p := range []point{a, b} {
+			if p.x < 0 || p.x >= w || p.y < 0 || p.y >= h {
+				return nil, fmt.Errorf("vent line %q off the canvas", input.Text())
+			}
+		}
+		vents = append(vents, [2]point{a, b})
 	}
-
-	fmt.Println(popcounts()) // part1 & part2
+	return vents, input.Err()
 }
 
 func sort(a, b int) (int, int) {
@@ -13,6 +13,7 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"strings"
@@ -36,22 +37,46 @@ func popcnt(p popcnts) uint64 {
 }
 
 func main() {
-	var fishes popcnts
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1) // part1
+		fmt.Println(res.Part2) // part2
+	}))
+}
 
-	input := bufio.NewScanner(os.Stdin)
-	for input.Scan() {
-		args := strings.Split(input.Text(), ",")
-		for _, arg := range args {
-			i, _ := strconv.Atoi(arg)
-			fishes[i]++
-		}
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	fishes, err := parse(r)
+	if err != nil {
+		return Result{}, err
 	}
 
+	var n80 uint64
 	for i := 0; i < 256; i++ {
 		if i == 80 {
-			fmt.Println(popcnt(fishes)) // part1
+			n80 = popcnt(fishes) // part1
 		}
 		incube(fishes[:]) // pass slice
 	}
-	fmt.Println(popcnt(fishes)) // part2
+
+	return Result{n80, popcnt(fishes)}, nil
+}
+
+// parse counts the lanternfishes by timer
+func parse(r io.Reader) (popcnts, error) {
+	var fishes popcnts
+
+	input := bufio.NewScanner(r)
+	for input.Scan() {
+		args := strings.Split(input.Text(), ",")
+		for _, arg := range args {
+			i, err := strconv.Atoi(arg)
+			if err != nil || i < 0 || i >= len(fishes) {
+				return popcnts{}, fmt.Errorf("bad timer %q", arg)
+			}
+			fishes[i]++
+		}
+	}
+	return fishes, input.Err()
 }
@@ -13,11 +13,14 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"math"
 	"os"
 	"sort"
 	"strconv"
 	"strings"
+
+	"github.com/erik-adelbert/aoc/lib/num"
 )
 
 type pos []int
@@ -42,28 +45,45 @@ func (p pos) sumdist() (int, int) {
 
 	sum1, sum2 := 0, 0
 	for _, x := range p {
-		sum1 += abs(x - m1)    // dist1
-		sum2 += g(abs(x - m2)) // dist2
+		sum1 += num.Abs(x - m1)    // dist1
+		sum2 += g(num.Abs(x - m2)) // dist2
 	}
 	return sum1, sum2
 }
 
 func main() {
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2) // part1 & part2
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	crabs, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
+
+	p1, p2 := crabs.sumdist()
+
+	return Result{p1, p2}, nil
+}
+
+// parse reads the crab positions
+func parse(r io.Reader) (pos, error) {
 	crabs := make(pos, 0, 1000)
 
-	input := bufio.NewScanner(os.Stdin)
+	input := bufio.NewScanner(r)
 	for input.Scan() {
 		for _, arg := range strings.Split(input.Text(), ",") {
-			x, _ := strconv.Atoi(arg)
+			x, err := strconv.Atoi(arg)
+			if err != nil {
+				return nil, err
+			}
 			crabs = append(crabs, x)
 		}
 	}
-	fmt.Println(crabs.sumdist()) // part1 & part2
-}
-
-func abs(n int) int {
-	if n < 0 {
-		return -n
-	}
-	return n
+	return crabs, input.Err()
 }
@@ -13,6 +13,7 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 )
@@ -86,40 +87,79 @@ func match(out []seg, sigs [][]seg) int {
 }
 
 func main() {
-	var sigs [][][]seg
-	var outs [][]seg
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1) // part1
+		fmt.Println(res.Part2) // part2
+	}))
+}
 
-	counts := make([]int, 8)
-	input := bufio.NewScanner(os.Stdin)
-	for input.Scan() {
-		args := strings.Split(input.Text(), "|")
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
 
-		tokens := strings.Fields(strings.TrimSpace(args[0]))
-		sig := make([][]seg, 8)
-		for _, t := range tokens {
-			s := Seg(t)
-			sig[len(s)] = append(sig[len(s)], s)
-		}
-		sigs = append(sigs, sig)
+	sigs, outs, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
 
-		tokens = strings.Fields(strings.TrimSpace(args[1]))
-		out := make([]seg, 0, 4)
-		for _, t := range tokens {
-			counts[len(t)]++
-			out = append(out, Seg(t))
+	counts := make([]int, 8)
+	for _, out := range outs {
+		for _, s := range out {
+			counts[len(s)]++
 		}
-		outs = append(outs, out)
 	}
 
 	sum1 := 0
 	for _, n := range []int{1, 4, 7, 8} {
 		sum1 += counts[segs[n]]
 	}
-	fmt.Println(sum1) // part1
 
 	sum2 := 0
 	for i, out := range outs {
 		sum2 += match(out, sigs[i])
 	}
-	fmt.Println(sum2) // part2
+
+	return Result{sum1, sum2}, nil
+}
+
+// parse reads the notes, the signals of each entry sorted by segment
+// count and its output digits
+func parse(r io.Reader) (sigs [][][]seg, outs [][]seg, err error) {
+	input := bufio.NewScanner(r)
+	for input.Scan() {
+		line := input.Text()
+
+		left, right, ok := strings.Cut(line, "|")
+		if !ok {
+			return nil, nil, fmt.Errorf("bad entry %q", line)
+		}
+
+		digits := func(s string) ([]seg, error) {
+			var ds []seg
+			for _, t := range strings.Fields(s) {
+				if len(t) > 7 || strings.Trim(t, "abcdefg") != "" {
+					return nil, fmt.Errorf("bad digit %q in entry %q", t, line)
+				}
+				ds = append(ds, Seg(t))
+			}
+			return ds, nil
+		}
+
+		ins, err := digits(left)
+		if err != nil {
+			return nil, nil, err
+		}
+		sig := make([][]seg, 8)
+		for _, s := range ins {
+			sig[len(s)] = append(sig[len(s)], s)
+		}
+		sigs = append(sigs, sig)
+
+		out, err := digits(right)
+		if err != nil {
+			return nil, nil, err
+		}
+		outs = append(outs, out)
+	}
+	return sigs, outs, input.Err()
 }
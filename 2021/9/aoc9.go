@@ -12,7 +12,9 @@ package main
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"sort"
 	"strings"
@@ -139,15 +141,20 @@ func (g *grid) String() string {
 }
 
 func main() {
-	g := new(grid) // data
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1) // part1
+		fmt.Println(res.Part2) // part2
+	}))
+}
 
-	h, w, input := 0, 0, bufio.NewScanner(os.Stdin)
-	for input.Scan() {
-		data := input.Bytes()
-		w = g.copy(h, data) // data ('0'..'9')
-		h++
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	g, err := parse(r)
+	if err != nil {
+		return Result{}, err
 	}
-	g.redim(h, w)
 
 	sum := 0
 	for y := 0; y < g.h; y++ {
@@ -155,11 +162,34 @@ func main() {
 			sum += g.filter(y, x)
 		}
 	}
-	fmt.Println(sum) // part1
 
 	popcnts := values(g.groups())
 	sort.Sort(sort.Reverse(sort.IntSlice(popcnts)))
-	fmt.Println(popcnts[0] * popcnts[1] * popcnts[2]) // part2
+
+	return Result{sum, popcnts[0] * popcnts[1] * popcnts[2]}, nil
+}
+
+// parse reads the heightmap, rows of digits of the same width
+func parse(r io.Reader) (*grid, error) {
+	g := new(grid) // data
+
+	h, w, input := 0, 0, bufio.NewScanner(r)
+	for input.Scan() {
+		data := input.Bytes()
+		switch {
+		case h+2 >= len(g.d) || len(data)+2 >= len(g.d[0]):
+			return nil, fmt.Errorf("heightmap larger than %dx%d", len(g.d)-2, len(g.d[0])-2)
+		case len(data) == 0, h > 0 && len(data) != w:
+			return nil, fmt.Errorf("ragged heightmap row %d", h+1)
+		case len(bytes.Trim(data, "0123456789")) > 0:
+			return nil, fmt.Errorf("bad heightmap row %q", data)
+		}
+		w = g.copy(h, data) // data ('0'..'9')
+		h++
+	}
+	g.redim(h, w)
+
+	return g, input.Err()
 }
 
 func values(m map[int]int) []int {
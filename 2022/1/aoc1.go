@@ -13,10 +13,23 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
+	"strings"
+
+	"github.com/erik-adelbert/aoc/lib/num"
 )
 
 func main() {
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2) // part 1 & 2
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
 	var m1, m2, m3, sum int
 
 	max3 := func() {
@@ -30,26 +43,40 @@ func main() {
 		}
 	}
 
-	input := bufio.NewScanner(os.Stdin)
-	for input.Scan() {
-		if line := input.Text(); len(line) > 0 {
-			sum += atoi(line)
-			continue
-		}
+	elves, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
 
-		max3()
+	for _, elf := range elves {
 		sum = 0
+		for _, cal := range elf {
+			sum += cal
+		}
+		max3()
 	}
-	max3()
 
-	fmt.Println(m1, m1+m2+m3) // part 1 & 2
+	return Result{m1, m1 + m2 + m3}, nil
 }
 
-// strconv.Atoi simplified core loop
-// s is ^\d+$
-func atoi(s string) (n int) {
-	for i := range s {
-		n = 10*n + int(s[i]-'0')
+// parse reads the calories each elf carries, elves are blank line separated
+func parse(r io.Reader) ([][]int, error) {
+	var elf []int
+	elves := make([][]int, 0, 256)
+
+	input := bufio.NewScanner(r)
+	for input.Scan() {
+		if line := input.Text(); len(line) > 0 {
+			if strings.Trim(line, "0123456789") != "" {
+				return nil, fmt.Errorf("bad calories %q", line)
+			}
+			elf = append(elf, num.Atoi(line))
+			continue
+		}
+
+		elves, elf = append(elves, elf), nil
 	}
-	return
+	elves = append(elves, elf)
+
+	return elves, input.Err()
 }
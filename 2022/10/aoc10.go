@@ -14,10 +14,25 @@ import (
 	"bufio"
 	"bytes"
 	"fmt"
+	"io"
 	"os"
+	"strconv"
+	"strings"
+
+	"github.com/erik-adelbert/aoc/lib/num"
 )
 
 func main() {
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1) // part1
+		fmt.Println(res.Part2) // part2
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
 	// machine initial state
 	// register X, clock and signal strength
 	X := 1
@@ -47,23 +62,24 @@ func main() {
 
 		// beam
 		pix := Black
-		if abs(X-bmx) <= 1 {
+		if num.Abs(X-bmx) <= 1 {
 			// in range, light on!
 			pix = White
 		}
 		fb.WriteByte(byte(pix))
 	}
 
-	input := bufio.NewScanner(os.Stdin)
-	for input.Scan() {
-		// fetch instruction
-		ins := input.Text()
+	prog, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
 
+	for _, ins := range prog { // fetch instruction
 		clk++ // tick
 		crt() // beam CRT
 
 		// decode, monitor power, beam CRT, execute
-		switch ins[0] {
+		switch ins.op {
 		case 'a':
 			// part1 sync signal monitoring
 			switch clk % 40 {
@@ -80,7 +96,7 @@ func main() {
 			crt() // beam CRT
 
 			// addx
-			X += atoi(ins[5:])
+			X += ins.v
 		case 'n':
 			// part1 sync signal monitoring
 			if clk%40 == 20 {
@@ -91,27 +107,33 @@ func main() {
 
 	}
 
-	fmt.Print(sig)           // part1
-	fmt.Println(fb.String()) // part2
+	return Result{sig, strings.TrimPrefix(fb.String(), "\n")}, nil
 }
 
-func abs(n int) int {
-	if n < 0 {
-		return -n
-	}
-	return n
+// instr is an instruction, addx v or noop, op is its initial
+type instr struct {
+	op byte
+	v  int
 }
 
-// strconv.Atoi simplified core loop
-// s is ^-?\d+$
-func atoi(s string) (n int) {
-	neg := 1
-	if s[0] == '-' {
-		neg, s = -1, s[1:]
-	}
+// parse reads the program
+func parse(r io.Reader) ([]instr, error) {
+	prog := make([]instr, 0, 256)
 
-	for i := range s {
-		n = 10*n + int(s[i]-'0')
+	input := bufio.NewScanner(r)
+	for input.Scan() {
+		ins := input.Text()
+		if ins == "noop" {
+			prog = append(prog, instr{'n', 0})
+			continue
+		}
+
+		arg, ok := strings.CutPrefix(ins, "addx ")
+		v, err := strconv.Atoi(arg)
+		if !ok || err != nil {
+			return nil, fmt.Errorf("bad instruction %q", ins)
+		}
+		prog = append(prog, instr{'a', v})
 	}
-	return neg * n
+	return prog, input.Err()
 }
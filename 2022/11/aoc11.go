@@ -13,8 +13,11 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"strings"
+
+	"github.com/erik-adelbert/aoc/lib/num"
 )
 
 type part struct {
@@ -24,14 +27,19 @@ type part struct {
 }
 
 func main() {
-	ctx1 := new(context)
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1) // part1
+		fmt.Println(res.Part2) // part2
+	}))
+}
 
-	// load inputs, compute running lcm
-	m, n := 1, 0
-	input := bufio.NewScanner(os.Stdin)
-	for input.Scan() {
-		m *= ctx1[n].load(input)
-		n++
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	ctx1, m, err := parse(r)
+	if err != nil {
+		return Result{}, err
 	}
 
 	// parameterized part 1 & 2
@@ -49,9 +57,43 @@ func main() {
 	}
 
 	// run simulations
-	for _, p := range parts {
-		fmt.Println(p.solve())
+	var business [2]int
+	for i, p := range parts {
+		business[i] = p.solve()
 	}
+
+	return Result{business[0], business[1]}, nil
+}
+
+// parse loads the monkeys, it returns them with the running lcm of their
+// test moduli
+func parse(r io.Reader) (*context, int, error) {
+	ctx := new(context)
+
+	// load inputs, compute running lcm
+	m, n := 1, 0
+	input := bufio.NewScanner(r)
+	for input.Scan() {
+		if n == len(ctx) {
+			return nil, 0, fmt.Errorf("more than %d monkeys", len(ctx))
+		}
+
+		mod, err := ctx[n].load(input)
+		if err != nil {
+			return nil, 0, fmt.Errorf("monkey %d: %w", n, err)
+		}
+		m *= mod
+		n++
+	}
+
+	for i := range n {
+		for _, j := range ctx[i].links {
+			if j < 0 || j >= n {
+				return nil, 0, fmt.Errorf("monkey %d throws to no monkey %d", i, j)
+			}
+		}
+	}
+	return ctx, m, input.Err()
 }
 
 func (p part) solve() int {
@@ -101,45 +143,58 @@ type state struct {
 	count int
 }
 
-func (s *state) load(input *bufio.Scanner) int {
+func (s *state) load(input *bufio.Scanner) (int, error) {
+	var line, arg string
+
+	// at cuts prefix off line into arg
+	at := func(prefix string) (ok bool) {
+		arg, ok = strings.CutPrefix(line, prefix)
+		return
+	}
 
 	for input.Scan() {
-		var line string
 		if line = strings.Trim(input.Text(), " "); len(line) == 0 {
-			return s.mod
+			break
 		}
 
-		switch line[0] {
-		case 'M':
+		switch {
+		case at("Monkey "):
 			// discard name
-		case 'S':
-			items := strings.Split(line[16:], ", ")
+		case at("Starting items: "):
+			items := strings.Split(arg, ", ")
 
 			for _, v := range items {
-				s.items = append(s.items, atoi(v))
+				s.items = append(s.items, num.Atoi(v))
+			}
+		case at("Operation: new = "):
+			cmd := strings.Fields(arg)
+			if len(cmd) != 3 || len(cmd[1]) != 1 {
+				return 0, fmt.Errorf("bad operation %q", line)
 			}
-		case 'O':
-			cmd := strings.Fields(line[17:])
 			s.cmd.op = cmd[1][0]
 
 			s.cmd.args[0], s.cmd.args[1] = -1, -1
-			if cmd[0][0] != 'o' {
-				s.cmd.args[0] = atoi(cmd[0])
+			if cmd[0] != "old" {
+				s.cmd.args[0] = num.Atoi(cmd[0])
 			}
-			if cmd[2][0] != 'o' {
-				s.cmd.args[1] = atoi(cmd[2])
-			}
-		case 'T':
-			s.mod = atoi(line[19:])
-		case 'I':
-			if line[3] == 't' {
-				s.links[0] = atoi(line[25:])
-			} else {
-				s.links[1] = atoi(line[26:])
+			if cmd[2] != "old" {
+				s.cmd.args[1] = num.Atoi(cmd[2])
 			}
+		case at("Test: divisible by "):
+			s.mod = num.Atoi(arg)
+		case at("If true: throw to monkey "):
+			s.links[0] = num.Atoi(arg)
+		case at("If false: throw to monkey "):
+			s.links[1] = num.Atoi(arg)
+		default:
+			return 0, fmt.Errorf("bad line %q", line)
 		}
 	}
-	return s.mod
+
+	if s.mod <= 0 {
+		return 0, fmt.Errorf("test modulus %d", s.mod)
+	}
+	return s.mod, nil
 }
 
 func (s *state) update(p part) {
@@ -183,12 +238,3 @@ func (a arit) eval(f func(int) int, n int) int {
 
 	return f(x)
 }
-
-// strconv.Atoi simplified core loop
-// s is ^\d+$
-func atoi(s string) (n int) {
-	for i := range s {
-		n = 10*n + int(s[i]-'0')
-	}
-	return
-}
@@ -13,7 +13,9 @@ package main
 import (
 	"bufio"
 	hp "container/heap"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 )
 
@@ -24,14 +26,42 @@ type grid struct {
 }
 
 func main() {
-	world := newGrid()
-	var one, all []*cell
-	var end *cell
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2) // part 1&2
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	world, all, end, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
+
+	p1, p2 := world.shortest(all, end)
 
-	h, w, input := 0, 0, bufio.NewScanner(os.Stdin)
+	return Result{p1, p2}, nil
+}
+
+// parse reads the heightmap, it returns the start cells, S first then
+// every a, and the end cell E
+func parse(r io.Reader) (world *grid, all []*cell, end *cell, err error) {
+	world = newGrid()
+	var one []*cell
+
+	h, w, input := 0, 0, bufio.NewScanner(r)
 	for input.Scan() {
 		line := input.Bytes()
-		for i, b := range input.Bytes() {
+		switch {
+		case h == len(world.d) || len(line) > len(world.d[0]):
+			return nil, nil, nil, fmt.Errorf("heightmap larger than %dx%d", len(world.d), len(world.d[0]))
+		case len(line) == 0 || h > 0 && len(line) != w:
+			return nil, nil, nil, fmt.Errorf("heightmap row %d: ragged map", h+1)
+		}
+
+		for i, b := range line {
 			world.d[h][i] = int(b)
 
 			switch b {
@@ -46,6 +76,10 @@ func main() {
 			case 'E':
 				world.d[h][i] = int('z')
 				end = &cell{h, i, 0}
+			default:
+				if b < 'a' || b > 'z' {
+					return nil, nil, nil, fmt.Errorf("bad elevation %q", b)
+				}
 			}
 		}
 		w = len(line)
@@ -53,9 +87,12 @@ func main() {
 	}
 	world.redim(h, w)
 
+	if len(one) != 1 || end == nil {
+		return nil, nil, nil, errors.New("the heightmap needs a single start S and an end E")
+	}
 	all = append(one, all...)
 
-	fmt.Println(world.shortest(all, end)) // part 1&2
+	return world, all, end, input.Err()
 }
 
 // solve computes the shortest distance between e and all
@@ -14,56 +14,88 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
-	"strings"
 )
 
 func main() {
-	// parity, pair index, ordered pair count
-	par, idx, popcnt := 0, 0, 0
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2)
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	pairs, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
+
+	// ordered pair count
+	popcnt := 0
 
 	// part2 subkeys indices
 	subkeys := []int{1, 2}
 
-	// last 2 packets buffer
-	packets := [2]string{}
+	for idx, packets := range pairs {
+		// part1
+		if cmp(packets[0], packets[1]) < 1 {
+			// packets are ordered
+			popcnt += idx + 1
+		}
 
-	input := bufio.NewScanner(os.Stdin)
-	for input.Scan() {
-		packet := input.Text()
+		// part2
+		for _, packet := range packets {
+			switch {
+			case cmp(packet, "2") < 1:
+				// packet goes before subkeys
+				subkeys[0]++
+				subkeys[1]++
+			case cmp(packet, "6") < 1:
+				// packet goes between subkeys
+				subkeys[1]++
+			}
+		}
+	}
 
-		// part1
-		if len(packet) == 0 {
-			idx++
+	// part 1&2
+
+	return Result{popcnt, subkeys[0] * subkeys[1]}, nil
+}
+
+// parse reads the pairs of packets, pairs are blank line separated
+func parse(r io.Reader) ([][2]string, error) {
+	var pairs [][2]string
+	var pair []string
 
-			// last two packets
-			a := packets[0]
-			b := packets[1]
+	input := bufio.NewScanner(r)
+	for input.Scan() {
+		line := input.Text()
 
-			if cmp(a, b) < 1 {
-				// packets are ordered
-				popcnt += idx
+		if len(line) == 0 {
+			if len(pair) != 2 {
+				return nil, fmt.Errorf("pair %d: %d packets", len(pairs)+1, len(pair))
 			}
-			continue // process next pair
+			pairs, pair = append(pairs, [2]string(pair)), pair[:0]
+			continue
 		}
 
-		// part2
-		switch {
-		case cmp(packet, "2") < 1:
-			// packet goes before subkeys
-			subkeys[0]++
-			subkeys[1]++
-		case cmp(packet, "6") < 1:
-			// packet goes between subkeys
-			subkeys[1]++
+		if _, err := parsePacket([]byte(line)); err != nil {
+			return nil, err
 		}
-
-		// memoize for part1 according to parity
-		packets[par], par = packet, 1-par
+		pair = append(pair, line)
 	}
 
-	// part 1&2
-	fmt.Println(popcnt, subkeys[0]*subkeys[1])
+	switch len(pair) {
+	case 0: // trailing blank line
+	case 2:
+		pairs = append(pairs, [2]string(pair))
+	default:
+		return nil, fmt.Errorf("pair %d: %d packets", len(pairs)+1, len(pair))
+	}
+	return pairs, input.Err()
 }
 
 type packet struct {
@@ -94,10 +126,61 @@ func (p *packet) next() byte {
 	return data[i]
 }
 
+// parsePacket checks s is a list of lists and integers up to 10, which next
+// reads as single digits, before it makes it a packet
+func parsePacket(s []byte) (*packet, error) {
+	i := 0
+
+	var list func() error
+	elem := func() error {
+		switch {
+		case i < len(s) && s[i] == '[':
+			return list()
+		case i+1 < len(s) && s[i] == '1' && s[i+1] == '0':
+			i += 2
+			return nil
+		case i < len(s) && '0' <= s[i] && s[i] <= '9':
+			i++
+			return nil
+		}
+		return fmt.Errorf("packet %q: column %d: expected a list or an integer", s, i+1)
+	}
+	list = func() error {
+		i++ // [
+		if i < len(s) && s[i] == ']' {
+			i++
+			return nil
+		}
+		for {
+			if err := elem(); err != nil {
+				return err
+			}
+			switch {
+			case i < len(s) && s[i] == ',':
+				i++
+			case i < len(s) && s[i] == ']':
+				i++
+				return nil
+			default:
+				return fmt.Errorf("packet %q: column %d: expected , or ]", s, i+1)
+			}
+		}
+	}
+
+	if len(s) == 0 || s[0] != '[' {
+		return nil, fmt.Errorf("packet %q: expected a list", s)
+	}
+	if err := list(); err != nil {
+		return nil, err
+	}
+	if i != len(s) {
+		return nil, fmt.Errorf("packet %q: column %d: trailing data", s, i+1)
+	}
+	return newPacket(string(s)), nil
+}
+
 func (p *packet) String() string {
-	var sb strings.Builder
-	fmt.Fprintf(&sb, "{ s: %s, i: %d, x: '%s' }", p.data, p.index, string(p.extra))
-	return sb.String()
+	return string(p.data)
 }
 
 func cmp(a, b string) int {
@@ -0,0 +1,13 @@
+package main
+
+import "testing"
+
+func FuzzParse(f *testing.F) {
+	seedExamples(f)
+	fuzzParse(f, reader(parse))
+}
+
+func FuzzPacket(f *testing.F) {
+	seedLines(f)
+	fuzzRoundTrip(f, parsePacket)
+}
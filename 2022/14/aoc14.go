@@ -13,8 +13,11 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"strings"
+
+	"github.com/erik-adelbert/aoc/lib/num"
 )
 
 var world worldmap
@@ -31,11 +34,28 @@ const (
 )
 
 func main() {
-	box := AABB{{INF, 0}, {0, 0}}
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2)
 
-	input := bufio.NewScanner(os.Stdin)
-	for input.Scan() {
-		box.merge(mkworld(input.Text()))
+		// !!rise your term resolution!!
+		// uncomment next line for visualization
+		// fmt.Println(world)
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	walls, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
+
+	world = worldmap{}
+	box := AABB{{INF, 0}, {0, 0}}
+	for _, wall := range walls {
+		box.merge(mkworld(wall))
 	}
 
 	// offset to sand boundaries
@@ -48,11 +68,45 @@ func main() {
 	part1 := fill(depth+1, depth, box)
 	part2 := part1 + 1 + fill(depth+2, 0, box)
 
-	fmt.Println(part1, part2)
+	return Result{part1, part2}, nil
+}
+
+// parse reads the rock paths, translated to fit the world with room for
+// the sand boundaries and the floor
+func parse(r io.Reader) ([][]XY, error) {
+	walls := make([][]XY, 0, 256)
+
+	input := bufio.NewScanner(r)
+	for input.Scan() {
+		line := input.Text()
+
+		wall := make([]XY, 0, 128)
+		for _, segs := range strings.Split(line, " -> ") {
+			var seg XY
 
-	// !!rise your term resolution!!
-	// uncomment next line for visualization
-	// fmt.Println(world)
+			args := strings.Split(segs, ",")
+			if len(args) != 2 {
+				return nil, fmt.Errorf("bad rock path %q", line)
+			}
+			for i, s := range args {
+				if s == "" || strings.Trim(s, "0123456789") != "" {
+					return nil, fmt.Errorf("bad rock path %q", line)
+				}
+				seg[i] = num.Atoi(s)
+			}
+			seg[0] -= XOFF // translate x to fit
+
+			if seg[X] < 1 || seg[X] >= len(world[0])-1 || seg[Y] >= len(world)-3 {
+				return nil, fmt.Errorf("rock path %q out of the world", line)
+			}
+			if n := len(wall); n > 0 && wall[n-1][X] != seg[X] && wall[n-1][Y] != seg[Y] {
+				return nil, fmt.Errorf("rock path %q: diagonal segment", line)
+			}
+			wall = append(wall, seg)
+		}
+		walls = append(walls, wall)
+	}
+	return walls, input.Err()
 }
 
 func fill(floor int, depth int, box AABB) int {
@@ -113,18 +167,10 @@ func fill(floor int, depth int, box AABB) int {
 	return cnt
 }
 
-func mkworld(s string) AABB {
-	wall := make([]XY, 0, 128)
+func mkworld(wall []XY) AABB {
 	box := AABB{{INF, INF}, {0, 0}}
-	for _, segs := range strings.Split(s, " -> ") {
-		var seg XY
-		for i, s := range strings.Split(segs, ",") {
-			seg[i] = atoi(s)
-		}
-		seg[0] -= XOFF // translate x to fit
-
+	for _, seg := range wall {
 		box.add(seg)
-		wall = append(wall, seg)
 	}
 	for i := range wall[:len(wall)-1] {
 		a, b := wall[i], wall[i+1]
@@ -211,15 +257,6 @@ func (a *AABB) merge(b AABB) {
 	a[Max][Y] = max(a[Max][Y], b[Max][Y])
 }
 
-// strconv.Atoi modified core loop
-// s is ^\d+$
-func atoi(s string) (n int) {
-	for i := range s {
-		n = 10*n + int(s[i]-'0')
-	}
-	return
-}
-
 func cmp(a, b int) int {
 	switch {
 	case a > b:
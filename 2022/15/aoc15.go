@@ -12,38 +12,46 @@ package main
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"slices"
 	"sort"
 	"strings"
-)
 
-var sensors []sensor = make([]sensor, 0, 64)
+	"github.com/erik-adelbert/aoc/lib/num"
+)
 
 // YMAX is the world max depth
 var YMAX int = 4_000_000
 
 func main() {
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1) // part1
+		fmt.Println(res.Part2) // part2
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
 	// part2
 	A := make(set, 64)
 	B := make(set, 64)
 	C := make(set, 64)
 	D := make(set, 64)
 
-	input := bufio.NewScanner(os.Stdin)
-	for input.Scan() {
-		args := strings.Split(input.Text(), "=")
-		SB := []XY{
-			{atoi(args[1]), atoi(args[2])}, // sensor
-			{atoi(args[3]), atoi(args[4])}, // beacon
-		}
-		sensors = append(sensors, mksensor(SB))
+	sensors, err := parse(r)
+	if err != nil {
+		return Result{}, err
 	}
 
 	ranges := make([]XY, 0, 64)
 	for _, s := range sensors {
 		// part1
-		if δ := s.R - abs(YMAX/2-s.O[Y]); δ >= 0 {
+		if δ := s.R - num.Abs(YMAX/2-s.O[Y]); δ >= 0 {
 			ranges = append(ranges, XY{s.O[X] - δ, s.O[X] + δ})
 		}
 
@@ -53,6 +61,10 @@ func main() {
 		C.add(s.O[X] + s.O[Y] - s.R - 1)
 		D.add(s.O[X] + s.O[Y] + s.R + 1)
 	}
+	if len(ranges) == 0 {
+		return Result{}, fmt.Errorf("no sensor covers row %d", YMAX/2)
+	}
+
 	sort.Slice(ranges, func(i, j int) bool {
 		return ranges[i][X] < ranges[j][X]
 	})
@@ -63,16 +75,41 @@ func main() {
 	}
 
 	// part1
-	fmt.Println(lims[1] - lims[0] - ngaps)
+	count := lims[1] - lims[0] - ngaps
 
 	A.inter(B)
 	C.inter(D)
 
-	a := A.pop()
-	c := C.pop()
+	a, oka := A.pop()
+	c, okc := C.pop()
+	if !oka || !okc {
+		return Result{}, errors.New("no uncovered position")
+	}
 
 	// part2
-	fmt.Println((a+c)*YMAX/2 + (c-a)/2)
+	freq := (a+c)*YMAX/2 + (c-a)/2
+
+	return Result{count, freq}, nil
+}
+
+// parse reads the sensors and the radius their closest beacon sets
+func parse(r io.Reader) ([]sensor, error) {
+	sensors := make([]sensor, 0, 64)
+
+	input := bufio.NewScanner(r)
+	for n := 1; input.Scan(); n++ {
+		args := strings.Split(input.Text(), "=")
+		if len(args) != 5 || slices.Contains(args[1:], "") {
+			return nil, fmt.Errorf("line %d: expected a sensor and its beacon, got %q", n, input.Text())
+		}
+
+		SB := []XY{
+			{atoi(args[1]), atoi(args[2])}, // sensor
+			{atoi(args[3]), atoi(args[4])}, // beacon
+		}
+		sensors = append(sensors, mksensor(SB))
+	}
+	return sensors, input.Err()
 }
 
 type set map[int]struct{}
@@ -81,11 +118,11 @@ func (A set) add(i int) {
 	A[i] = struct{}{}
 }
 
-func (A set) pop() int {
+func (A set) pop() (int, bool) {
 	for i := range A {
-		return i
+		return i, true
 	}
-	panic("unreachable")
+	return 0, false
 }
 
 func (A set) inter(B set) {
@@ -121,14 +158,7 @@ const (
 type XY [2]int
 
 func (a XY) manh(b XY) int {
-	return abs(a[X]-b[X]) + abs(a[Y]-b[Y])
-}
-
-func abs(n int) int {
-	if n < 0 {
-		return -n
-	}
-	return n
+	return num.Abs(a[X]-b[X]) + num.Abs(a[Y]-b[Y])
 }
 
 // strconv.Atoi modified loop
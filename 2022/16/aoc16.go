@@ -15,21 +15,39 @@ package main
 import (
 	"bufio"
 	"cmp"
+	"errors"
 	"fmt"
+	"io"
 	"math/bits"
 	"os"
 	"slices"
 	"sort"
 	"strings"
+
+	"github.com/erik-adelbert/aoc/lib/num"
 )
 
 func main() {
-	w := readWorld(bufio.NewScanner(os.Stdin))
-	part1(w)
-	part2(w)
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1) // part1
+		fmt.Println(res.Part2) // part2
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	valves, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
+	w := newWorld(valves)
+
+	return Result{part1(w), part2(w)}, nil
 }
 
-func part1(w *world) {
+func part1(w *world) int {
 	score := 0
 	highscore := func(_, flow int) int {
 		score = max(score, flow) // capture score
@@ -38,10 +56,10 @@ func part1(w *world) {
 	part1 := &state{todo: w.state, from: w.from, time: 30, flow: 0}
 	w.bbsolve(part1, highscore) // update score via highscore() closure
 
-	fmt.Println(score)
+	return score
 }
 
-func part2(w *world) {
+func part2(w *world) int {
 
 	step1 := &state{todo: w.state, from: w.from, time: 26, flow: 0}
 	score1, closed := 0, 0
@@ -103,7 +121,7 @@ func part2(w *world) {
 
 	}
 
-	fmt.Println(best)
+	return best
 }
 
 const (
@@ -126,7 +144,9 @@ type valve struct {
 	links []string
 }
 
-func readWorld(input *bufio.Scanner) *world {
+// parse reads the valves, AA the start and the zero flow others the
+// corridors between two valves
+func parse(rd io.Reader) ([]valve, error) {
 	valves := make([]valve, 0, 60)
 
 	var r = strings.NewReplacer(
@@ -135,6 +155,11 @@ func readWorld(input *bufio.Scanner) *world {
 		",", "",
 	)
 
+	isname := func(s string) bool {
+		return len(s) == 2 && 'A' <= s[0] && s[0] <= 'Z' && 'A' <= s[1] && s[1] <= 'Z'
+	}
+
+	input := bufio.NewScanner(rd)
 	for input.Scan() {
 		// input line is:
 		// ^\w+\s([A-Z]{2})(\s\w+){3}=(\d+);(\s\w+){4}\s([A-Z]{2})(,\s([A-Z]{2}))*$
@@ -148,11 +173,45 @@ func readWorld(input *bufio.Scanner) *world {
 		// [\w+, [A-Z]{2}, \w+, \w+, \w+, \d+, \w+, \w+, \w+, \w+, ([A-Z]{2}))+ ]
 		//       name                     flow                     links...
 		args := strings.Fields(r.Replace(input.Text()))
+		if len(args) < 11 || !isname(args[1]) || strings.Trim(args[5], "0123456789") != "" {
+			return nil, fmt.Errorf("bad valve %q", input.Text())
+		}
 		valves = append(valves, valve{
-			name: args[1], flow: atoi(args[5]), links: args[10:],
+			name: args[1], flow: num.Atoi(args[5]), links: args[10:],
 		})
 	}
+	if err := input.Err(); err != nil {
+		return nil, err
+	}
+
+	names, nflow := make(map[string]bool, len(valves)), 0
+	for _, v := range valves {
+		names[v.name] = true
+		if v.flow > 0 {
+			nflow++
+		}
+	}
+	if !names["AA"] {
+		return nil, errors.New("no start valve AA")
+	}
+	if nflow > 62 {
+		return nil, fmt.Errorf("%d valves with a flow, more than a state holds", nflow)
+	}
+	for _, v := range valves {
+		for _, link := range v.links {
+			if !names[link] || link == v.name {
+				return nil, fmt.Errorf("valve %s: bad tunnel to %s", v.name, link)
+			}
+		}
+		if v.flow == 0 && v.name != "AA" && len(v.links) != 2 {
+			return nil, fmt.Errorf("valve %s: zero flow valves are corridors", v.name)
+		}
+	}
+	return valves, nil
+}
 
+// newWorld builds the world between the start and the valves with a flow
+func newWorld(valves []valve) *world {
 	sort.Sort(byDescendingFlow(valves))
 
 	// size is non-zero flow valve count plus 1 for "AA"
@@ -314,15 +373,6 @@ func mkIntSlice(value int, size int) []int {
 	return s
 }
 
-// strconv.Atoi modified core loop
-// s is ^\d+.*$
-func atoi(s string) (n int) {
-	for i := range s {
-		n = 10*n + int(s[i]-'0')
-	}
-	return
-}
-
 var DEBUG = false
 
 func debug(a ...any) {
@@ -12,7 +12,9 @@ package main
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"sort"
 )
@@ -30,15 +32,40 @@ type board [H][W]byte
 var jets []byte
 
 func main() {
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1)
+		fmt.Println(res.Part2)
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
 	b := board{}
 
-	input := bufio.NewScanner(os.Stdin)
-	for input.Scan() {
-		jets = input.Bytes()
+	if jets, err = parse(r); err != nil {
+		return Result{}, err
 	}
 
 	// part 1&2
-	b.play([]int{2022, 1_000_000_000_000})
+	h := b.play([]int{2022, 1_000_000_000_000})
+
+	return Result{h[0], h[1]}, nil
+}
+
+// parse reads the jet pattern, a single line of < and >
+func parse(r io.Reader) ([]byte, error) {
+	var jets []byte
+
+	input := bufio.NewScanner(r)
+	for input.Scan() {
+		jets = bytes.Clone(input.Bytes())
+	}
+	if len(jets) == 0 || len(bytes.Trim(jets, "<>")) > 0 {
+		return nil, fmt.Errorf("bad jet pattern %.16q", jets)
+	}
+	return jets, input.Err()
 }
 
 type state struct {
@@ -50,7 +77,7 @@ type state struct {
 }
 
 // play n tetrominoes with cycle detection
-func (b *board) play(n []int) {
+func (b *board) play(n []int) []int {
 	// initial state
 	s := state{0, 0, 0, [2]int{H, 0}}
 
@@ -103,17 +130,18 @@ func (b *board) play(n []int) {
 		y := s.h[1]
 
 		// simulate for remainders
+		heights := make([]int, 0, len(n))
 		for i := 0; i < rmax; i++ {
 			s.drop(b)
 			if i == rh[j][0] {
 				// tetro count is just over one of the goals:
 				// account for baseline and cycles heights
 				// output total height
-				fmt.Println(rh[j][1] + (y - s.h[1]))
+				heights = append(heights, rh[j][1]+(y-s.h[1]))
 				j++
 			}
 		}
-		return
+		return heights
 	}
 }
 
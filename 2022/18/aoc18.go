@@ -13,30 +13,66 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"strings"
+
+	"github.com/erik-adelbert/aoc/lib/num"
 )
 
 func main() {
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1)
+		fmt.Println(res.Part2)
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	cubes, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
+
 	w := make(world, 4096)
 	b := new(AABB)
-
-	input := bufio.NewScanner(os.Stdin)
-	for input.Scan() {
-		line := strings.Split(input.Text(), ",")
-		p := XYZ{
-			atoi(line[0]), atoi(line[1]), atoi(line[2]),
-		}
+	for _, p := range cubes {
 		b.add(p)
 		w.add(p)
 	}
 
 	// part1
-	fmt.Println(w.area())
+	area := w.area()
 
 	// part2
 	p0 := b[Min].sub(XYZ{-1, -1, -1})
-	fmt.Println(w.flood(p0, b))
+
+	return Result{area, w.flood(p0, b)}, nil
+}
+
+// parse reads the lava cubes
+func parse(r io.Reader) ([]XYZ, error) {
+	cubes := make([]XYZ, 0, 4096)
+
+	input := bufio.NewScanner(r)
+	for input.Scan() {
+		line := strings.Split(input.Text(), ",")
+		if len(line) != 3 {
+			return nil, fmt.Errorf("bad cube %q", input.Text())
+		}
+		for _, s := range line {
+			if s == "" || strings.Trim(s, "0123456789") != "" {
+				return nil, fmt.Errorf("bad cube %q", input.Text())
+			}
+		}
+
+		cubes = append(cubes, XYZ{
+			num.Atoi(line[0]), num.Atoi(line[1]), num.Atoi(line[2]),
+		})
+	}
+	return cubes, input.Err()
 }
 
 type world map[XYZ]struct{}
@@ -161,12 +197,3 @@ func (a XYZ) lte(b XYZ) bool {
 func (a XYZ) gte(b XYZ) bool {
 	return a[X] >= b[X] && a[Y] >= b[Y] && a[Z] >= b[Z]
 }
-
-// strconv.Atoi simplified core loop
-// s is ^\d+$
-func atoi(s string) (n int) {
-	for i := range s {
-		n = 10*n + int(s[i]-'0')
-	}
-	return
-}
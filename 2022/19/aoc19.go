@@ -13,6 +13,7 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 )
 
@@ -35,22 +36,22 @@ type world struct {
 }
 
 func main() {
-	worlds := make([]world, 0, 32)
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2)
+	}))
+}
 
-	input := bufio.NewScanner(os.Stdin)
-	for input.Scan() {
-		line := input.Bytes()
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
 
-		blue := make([]int, 0, 8)
-		for i := 0; i < len(line); i++ {
-			if line[i] < '0' || '9' < line[i] {
-				continue
-			}
+	blues, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
 
-			n, j := atoi(line[i:])
-			blue = append(blue, n)
-			i += j
-		}
+	worlds := make([]world, 0, len(blues))
+	for _, blue := range blues {
 		worlds = append(worlds, mkworld(blue))
 	}
 
@@ -75,7 +76,33 @@ func main() {
 		prd *= bestD
 	}
 
-	fmt.Println(sum, prd)
+	return Result{sum, prd}, nil
+}
+
+// parse reads the blueprints, an id followed by six robot costs each
+func parse(r io.Reader) ([][]int, error) {
+	blues := make([][]int, 0, 32)
+
+	input := bufio.NewScanner(r)
+	for input.Scan() {
+		line := input.Bytes()
+
+		blue := make([]int, 0, 8)
+		for i := 0; i < len(line); i++ {
+			if line[i] < '0' || '9' < line[i] {
+				continue
+			}
+
+			n, j := atoi(line[i:])
+			blue = append(blue, n)
+			i += j
+		}
+		if len(blue) != 7 {
+			return nil, fmt.Errorf("bad blueprint %q", line)
+		}
+		blues = append(blues, blue)
+	}
+	return blues, input.Err()
 }
 
 func (w world) maxout(best *int) {
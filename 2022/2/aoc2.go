@@ -13,32 +13,62 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 )
 
 // see day2 notes
 func main() {
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2)
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
 	scale := []int{
 		1, 2, 0,
 		0, 1, 2,
 		2, 0, 1,
 	}
 
+	rounds, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
+
 	scores := 0
+	for _, rc := range rounds {
+		r, c := rc[0], rc[1] // opponent move, our move or goal
+
+		// pack parts 1&2 scores
+		scores += pack(1+c+3*scale[3*r+c], 1+scale[3*(2-c)+r]+3*c) // apply symmetry
+	}
 
-	input := bufio.NewScanner(os.Stdin)
+	p1, p2 := unpack(scores)
+
+	return Result{p1, p2}, nil
+}
+
+// parse reads the strategy guide, the opponent move and ours, or our goal,
+// as 0..2 each
+func parse(r io.Reader) ([][2]int, error) {
+	rounds := make([][2]int, 0, 2500)
+
+	input := bufio.NewScanner(r)
 	for input.Scan() {
 		line := input.Text()
+		if len(line) != 3 || line[0] < 'A' || line[0] > 'C' || line[1] != ' ' || line[2] < 'X' || line[2] > 'Z' {
+			return nil, fmt.Errorf("bad round %q", line)
+		}
 
 		r := int(line[0] - 'A') // opponent move
 		c := int(line[2] - 'X') // our move or goal
-
-		// pack parts 1&2 scores
-		scores += pack(1+c+3*scale[3*r+c], 1+scale[3*(2-c)+r]+3*c) // apply symmetry
+		rounds = append(rounds, [2]int{r, c})
 	}
-
-	fmt.Println(unpack(scores))
-
+	return rounds, input.Err()
 }
 
 const WIDTH = 16
@@ -12,23 +12,60 @@ package main
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"strconv"
+
+	"github.com/erik-adelbert/aoc/lib/num"
 )
 
 func main() {
-	seq := make([]int, 0, 8192)
-	input := bufio.NewScanner(os.Stdin)
-	for input.Scan() {
-		seq = append(seq, atoi(input.Text()))
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1)
+		fmt.Println(res.Part2)
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	seq, err := parse(r)
+	if err != nil {
+		return Result{}, err
 	}
 
 	// part 1
-	fmt.Println(key(shuffle(seq, 1, 1)))
+	key1 := key(shuffle(seq, 1, 1))
 
 	// part 2
 	const salt = 811_589_153
-	fmt.Println(key(shuffle(seq, salt, 10)))
+
+	return Result{key1, key(shuffle(seq, salt, 10))}, nil
+}
+
+// parse reads the encrypted file, a list of numbers holding a single 0
+func parse(r io.Reader) ([]int, error) {
+	seq := make([]int, 0, 8192)
+
+	nzero := 0
+	input := bufio.NewScanner(r)
+	for input.Scan() {
+		n, err := strconv.Atoi(input.Text())
+		if err != nil {
+			return nil, fmt.Errorf("bad number %q", input.Text())
+		}
+		if n == 0 {
+			nzero++
+		}
+		seq = append(seq, n)
+	}
+	if len(seq) < 2 || nzero != 1 {
+		return nil, errors.New("bad file: want a single 0 among several numbers")
+	}
+	return seq, input.Err()
 }
 
 func shuffle(input []int, salt, nround int) ([]int, int) {
@@ -107,7 +144,7 @@ func shuffle(input []int, salt, nround int) ([]int, int) {
 			}
 
 			// compute new x global index
-			gix = mod(gix+x.val, len(addrs)-1)
+			gix = num.Mod(gix+x.val, len(addrs)-1)
 
 			// remap to bin/off
 			bin, off := 0, 0
@@ -186,31 +223,14 @@ func shuffle(input []int, salt, nround int) ([]int, int) {
 
 func key(a []int, o int) int {
 	// get key components
-	k1 := mod(o+1000, len(a))
-	k2 := mod(o+2000, len(a))
-	k3 := mod(o+3000, len(a))
+	k1 := num.Mod(o+1000, len(a))
+	k2 := num.Mod(o+2000, len(a))
+	k3 := num.Mod(o+3000, len(a))
 
 	// forge & return key
 	return a[k1] + a[k2] + a[k3]
 }
 
-// strconv.Atoi simplified core loop
-// s is ^-?\d+$
-func atoi(s string) (n int) {
-	neg := 1
-	if s[0] == '-' {
-		neg, s = -1, s[1:]
-	}
-	for i := range s {
-		n = 10*n + int(s[i]-'0')
-	}
-	return neg * n
-}
-
-func mod(a, b int) int {
-	return ((a % b) + b) % b
-}
-
 const DEBUG = true
 
 func debug(a ...any) {
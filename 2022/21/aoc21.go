@@ -12,9 +12,13 @@ package main
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
+
+	"github.com/erik-adelbert/aoc/lib/num"
 )
 
 type val struct {
@@ -27,16 +31,54 @@ type val struct {
 var PROG map[string]*val
 
 func main() {
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1)
+		fmt.Println(res.Part2)
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(in io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	if PROG, err = parse(in); err != nil {
+		return Result{}, err
+	}
+
+	return Result{eval(PROG["root"]), solve(PROG["root"], "humn")}, nil
+}
+
+// parse reads the monkeys jobs, root must be an operation and humn a number
+func parse(in io.Reader) (map[string]*val, error) {
 	PROG = make(map[string]*val, 2048)
 
-	input := bufio.NewScanner(os.Stdin)
+	input := bufio.NewScanner(in)
 	for input.Scan() {
 		cmd := strings.Fields(r.Replace(input.Text()))
+		switch {
+		case len(cmd) == 2 && cmd[1] != "" && strings.Trim(cmd[1], "0123456789") == "":
+		case len(cmd) == 4 && len(cmd[2]) == 1 && strings.Contains("+-*/", cmd[2]):
+		default:
+			return nil, fmt.Errorf("bad job %q", input.Text())
+		}
 		load(cmd)
 	}
+	if err := input.Err(); err != nil {
+		return nil, err
+	}
 
-	fmt.Println(eval(PROG["root"]))
-	fmt.Println(solve(PROG["root"], "humn"))
+	for k, v := range PROG {
+		for _, a := range v.a {
+			if PROG[a.s] == nil {
+				return nil, fmt.Errorf("bad job %q: unknown monkey %q", k, a.s)
+			}
+		}
+	}
+	root, humn := PROG["root"], PROG["humn"]
+	if root == nil || root.op == "INT" || humn == nil || humn.op != "INT" {
+		return nil, errors.New("bad jobs: missing root or humn")
+	}
+	return PROG, nil
 }
 
 func eval(v *val) int {
@@ -167,7 +209,7 @@ func load(args []string) {
 	case 2:
 		// var
 		v.op = "INT"
-		v.n = atoi(args[1])
+		v.n = num.Atoi(args[1])
 	default:
 		// binary op
 		v.op = args[2]
@@ -217,12 +259,3 @@ func (v *val) String() string {
 var r = strings.NewReplacer(
 	":", "",
 )
-
-// strconv.Atoi simplified core loop
-// s is ^\d+$
-func atoi(s string) (n int) {
-	for i := range s {
-		n = 10*n + int(s[i]-'0')
-	}
-	return
-}
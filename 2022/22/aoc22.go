@@ -12,9 +12,14 @@ package main
 
 import (
 	"bufio"
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
+
+	"github.com/erik-adelbert/aoc/lib/num"
 )
 
 // fieldalignment auto-layout
@@ -30,13 +35,78 @@ type cubemap struct {
 }
 
 func main() {
-	input := bufio.NewScanner(os.Stdin)
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1)
+		fmt.Println(res.Part2)
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	tex, path, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
+
+	cube := mkcubemap(tex, path)
+
+	return Result{cube.walk(1), cube.walk(2)}, nil
+}
+
+// parse reads the cube net and the path, the net rows come in bands of N
+// tiles for a total of six N×N faces
+func parse(r io.Reader) (bmp, []string, error) {
+	tex := make(bmp, 0, 150)
+
+	input := bufio.NewScanner(r)
+	for input.Scan() {
+		var line []byte
+		if line = input.Bytes(); len(line) == 0 {
+			break
+		}
+		if len(bytes.Trim(bytes.TrimLeft(line, " "), ".#")) > 0 {
+			return nil, nil, fmt.Errorf("bad net row %q", line)
+		}
+		tex = append(tex, bytes.Clone(line))
+	}
+
+	ntile := 0
+	for _, r := range tex {
+		ntile += len(bytes.TrimLeft(r, " "))
+	}
+	N := 0
+	if ntile > 0 {
+		N = isqrt(ntile / 6)
+	}
+	if N == 0 || 6*N*N != ntile || len(tex)%N != 0 {
+		return nil, nil, fmt.Errorf("bad net: %d tiles", ntile)
+	}
+	for i, r := range tex {
+		j := len(r) - len(bytes.TrimLeft(r, " "))
+		if j%N != 0 || len(r)%N != 0 || i%N > 0 && len(r) != len(tex[i-1]) {
+			return nil, nil, fmt.Errorf("bad net row %q", r)
+		}
+	}
+	if !bytes.Contains(tex[0], []byte{'.'}) {
+		return nil, nil, errors.New("bad net: no start")
+	}
 
-	cube := mkcubemap(150)
-	cube.load(input)
+	// cmds
+	input.Scan() // one line
+	rp := strings.NewReplacer(
+		"L", " L ",
+		"R", " R ",
+	)
+	path := strings.Fields(rp.Replace(input.Text()))
+	for _, x := range path {
+		if x != "L" && x != "R" && strings.Trim(x, "0123456789") != "" {
+			return nil, nil, fmt.Errorf("bad path %q", input.Text())
+		}
+	}
 
-	fmt.Println(cube.walk(1))
-	fmt.Println(cube.walk(2))
+	return tex, path, input.Err()
 }
 
 // axis
@@ -60,25 +130,14 @@ type (
 	Euv struct{ E, u, v XYZ }
 )
 
-func mkcubemap(N int) *cubemap {
+func mkcubemap(tex bmp, path []string) *cubemap {
 	c := new(cubemap)
-	c.tex = make([][]byte, 0, N)
-	return c
-}
+	c.tex, c.path = tex, path
 
-func (c *cubemap) load(input *bufio.Scanner) {
 	// cubemap
-	c.h, c.w = 0, 0
-	for input.Scan() {
-		var line []byte
-		if line = input.Bytes(); len(line) == 0 {
-			break
-		}
-		c.h++
-		c.w = max(c.w, len(line))
-		r := make([]byte, len(line))
-		copy(r, line)
-		c.tex = append(c.tex, r)
+	c.h, c.w = len(tex), 0
+	for _, r := range tex {
+		c.w = max(c.w, len(r))
 	}
 
 	minmax := func(n int) []lim {
@@ -106,14 +165,6 @@ func (c *cubemap) load(input *bufio.Scanner) {
 	}
 	c.N = isqrt(c.N / 6)
 
-	// cmds
-	input.Scan() // one line
-	r := strings.NewReplacer(
-		"L", " L ",
-		"R", " R ",
-	)
-	c.path = strings.Fields(r.Replace(input.Text()))
-
 	i0, j0 := 0, 1000
 	for j, c := range c.tex[0] {
 		if c == '.' {
@@ -126,7 +177,7 @@ func (c *cubemap) load(input *bufio.Scanner) {
 	c.faces = make(map[UV]Euv)
 	c.fold(i0, j0, XYZ{0, 0, 0}, XYZ{1, 0, 0}, XYZ{0, 1, 0})
 
-	return
+	return c
 }
 
 func (c *cubemap) out(i, j int) bool {
@@ -227,7 +278,7 @@ func (c *cubemap) walk(p int) int {
 		case "R":
 			δi, δj = δj, -δi
 		default:
-			i, j, δi, δj = c.step(p, atoi(x), i, j, δi, δj)
+			i, j, δi, δj = c.step(p, num.Atoi(x), i, j, δi, δj)
 		}
 	}
 
@@ -285,15 +336,6 @@ func (a XYZ) cross(b XYZ) XYZ {
 	return a
 }
 
-// strconv.Atoi simplified core loop
-// s is ^\d+$
-func atoi(s string) (n int) {
-	for i := range s {
-		n = 10*n + int(s[i]-'0')
-	}
-	return
-}
-
 var tab64 = [64]uint64{
 	63, 0, 58, 1, 59, 47, 53, 2,
 	60, 39, 48, 27, 54, 33, 42, 3,
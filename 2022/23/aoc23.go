@@ -13,14 +13,32 @@ package main
 
 import (
 	"bufio"
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"math/bits"
 	"os"
 	"strings"
 )
 
 func main() {
-	clock, gol := 1, newGame(bufio.NewScanner(os.Stdin))
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1)
+		fmt.Println(res.Part2)
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	grove, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
+
+	clock, gol := 1, newGame(grove)
 	//fmt.Println(gol)
 
 	// part 1
@@ -29,34 +47,57 @@ func main() {
 		gol.tick()
 	}
 	area, popcnt := gol.poparea()
-	fmt.Println(area - popcnt)
+	empty := area - popcnt
 
 	// part 2
 	for gol.tick() {
 		clock++
 	}
-	fmt.Println(clock)
+
+	return Result{empty, clock}, nil
 }
 
+// parse reads the grove scan, it has to fit in an off×off square and
+// hold at least one elf
+func parse(r io.Reader) ([][]byte, error) {
+	grove := make([][]byte, 0, off)
+
+	nelf := 0
+	input := bufio.NewScanner(r)
+	for input.Scan() {
+		line := input.Bytes()
+		if len(grove) == off || len(line) > off || len(bytes.Trim(line, ".#")) > 0 {
+			return nil, fmt.Errorf("bad scan row %q", line)
+		}
+		nelf += bytes.Count(line, []byte{'#'})
+		grove = append(grove, bytes.Clone(line))
+	}
+	if nelf == 0 {
+		return nil, errors.New("bad scan: no elf")
+	}
+	return grove, input.Err()
+}
+
+// grove offset and height on the game board
+const (
+	off    = 74
+	heigth = 222
+)
+
 type golife struct {
 	cells, n, s, w, e []uint256
 	head              int // heading
 }
 
-func newGame(input *bufio.Scanner) (g *golife) {
-	const (
-		off    = 74
-		heigth = 222
-	)
-
+func newGame(grove [][]byte) (g *golife) {
 	n := make([]uint256, heigth)
 	s := make([]uint256, heigth)
 	w := make([]uint256, heigth)
 	e := make([]uint256, heigth)
 
 	cells := make([]uint256, heigth)
-	for j := 0; input.Scan(); j++ {
-		for i, c := range input.Text() {
+	for j, r := range grove {
+		for i, c := range r {
 			if c == '#' {
 				cells[j+off].setbit(i + off) // cells[j][i] is alive
 			}
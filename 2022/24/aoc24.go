@@ -13,26 +13,29 @@ package main
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
+
+	"github.com/erik-adelbert/aoc/lib/num"
 )
 
 func main() {
-	w := make(waze, 0, 32)
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2)
+	}))
+}
 
-	input := bufio.NewScanner(os.Stdin)
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
 
-	var row string
-	input.Scan() // discard first row
-	for input.Scan() {
-		if len(row) > 0 {
-			// discard first and last col
-			w = w.append(row[1 : len(row)-1])
-		}
-		row = input.Text()
+	w, err := parse(r)
+	if err != nil {
+		return Result{}, err
 	}
-	// discard last row
 
 	// build maze over time
 	m := newTaze(w)
@@ -46,7 +49,50 @@ func main() {
 	lap2 := m.solve(lap1, BCK)
 	lap3 := m.solve(lap2, FWD)
 
-	fmt.Println(lap1, lap3)
+	return Result{lap1, lap3}, nil
+}
+
+// parse reads the valley inside its walls, the entrance is top left, the
+// exit bottom right and the valley is at most uint128size wide
+func parse(r io.Reader) (waze, error) {
+	w := make(waze, 0, 32)
+
+	input := bufio.NewScanner(r)
+
+	var rows []string
+	for input.Scan() {
+		rows = append(rows, input.Text())
+	}
+	if err := input.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(rows) < 3 {
+		return nil, errors.New("bad valley: too few rows")
+	}
+	W := len(rows[0]) - 2
+	if W < 1 || W > uint128size {
+		return nil, fmt.Errorf("bad valley width %d", W)
+	}
+
+	top, bot := rows[0], rows[len(rows)-1]
+	if top != "#."+strings.Repeat("#", W) {
+		return nil, fmt.Errorf("bad valley wall %q", top)
+	}
+	if bot != strings.Repeat("#", W)+".#" {
+		return nil, fmt.Errorf("bad valley wall %q", bot)
+	}
+
+	for _, row := range rows[1 : len(rows)-1] {
+		if len(row) != W+2 || row[0] != '#' || row[W+1] != '#' ||
+			strings.Trim(row[1:W+1], ".<>^v") != "" {
+			return nil, fmt.Errorf("bad valley row %q", row)
+		}
+		// discard first and last col
+		w = w.append(row[1 : W+1])
+	}
+
+	return w, nil
 }
 
 // timed waze
@@ -93,8 +139,8 @@ func newTaze(m waze) *taze {
 	cols := make([]uint128, 0, W*H)
 	for t := 0; t < H; t++ {
 		for i := 0; i < W; i++ {
-			u := U[mod(i+t, H)]
-			d := D[mod(i-t, H)]
+			u := U[num.Mod(i+t, H)]
+			d := D[num.Mod(i-t, H)]
 			cols = append(cols, u.and(d))
 		}
 	}
@@ -113,8 +159,8 @@ func (m *taze) solve(t0 int, fwd bool) int {
 		for i := 0; i < H; i++ {
 			old, cur, nxt = cur, nxt, state[i+1]
 			state[i] = cur.or(cur.rsh(1)).or(cur.lsh(1).or(old).or(nxt))
-			state[i] = state[i].and(rows[H*mod(t, W)+i])
-			state[i] = state[i].and(cols[W*mod(t, H)+i])
+			state[i] = state[i].and(rows[H*num.Mod(t, W)+i])
+			state[i] = state[i].and(cols[W*num.Mod(t, H)+i])
 		}
 
 		if fwd {
@@ -181,10 +227,6 @@ func (u uint128) String() string {
 	return sb.String()
 }
 
-func mod(a, b int) int {
-	return ((a % b) + b) % b
-}
-
 var DEBUG = false
 
 func debug(a ...any) (int, error) {
@@ -12,7 +12,10 @@ package main
 
 import (
 	"bufio"
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 )
@@ -28,14 +31,41 @@ func (a snafu) String() string {
 }
 
 func main() {
-	acc := snafu{'0'}
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1)
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	nums, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
 
-	input := bufio.NewScanner(os.Stdin)
-	for input.Scan() {
-		acc = add(acc, mksnafu(input.Bytes()))
+	acc := snafu{'0'}
+	for _, a := range nums {
+		acc = add(acc, a)
 	}
 
-	fmt.Println(acc)
+	return Result{acc, nil}, nil
+}
+
+// parse reads the fuel requirements, one snafu number per line
+func parse(r io.Reader) ([]snafu, error) {
+	var nums []snafu
+
+	input := bufio.NewScanner(r)
+	for line := 1; input.Scan(); line++ {
+		a, err := parseSnafu(input.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		nums = append(nums, a)
+	}
+	return nums, input.Err()
 }
 
 func add(a, b snafu) snafu {
@@ -121,6 +151,19 @@ func add(a, b snafu) snafu {
 	return a
 }
 
+// parseSnafu reads a snafu number, the bytes of a are left untouched
+func parseSnafu(a []byte) (snafu, error) {
+	if len(a) == 0 {
+		return nil, errors.New("empty snafu number")
+	}
+	for i, c := range a {
+		if !strings.ContainsRune("=-012", rune(c)) {
+			return nil, fmt.Errorf("snafu digit %d: %q", i+1, c)
+		}
+	}
+	return mksnafu(bytes.Clone(a)), nil
+}
+
 func mksnafu(a []byte) snafu {
 	// reverse slice
 	for l, r := 0, len(a)-1; l < r; l, r = l+1, r-1 {
@@ -0,0 +1,13 @@
+package main
+
+import "testing"
+
+func FuzzParse(f *testing.F) {
+	seedExamples(f)
+	fuzzParse(f, reader(parse))
+}
+
+func FuzzSnafu(f *testing.F) {
+	seedLines(f)
+	fuzzRoundTrip(f, parseSnafu)
+}
@@ -13,7 +13,9 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 )
 
 // part indices
@@ -23,6 +25,15 @@ const (
 )
 
 func main() {
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2)
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
 	// stricly positive integers are required
 	// see day3 notes
 	const (
@@ -39,14 +50,16 @@ func main() {
 		}
 	}
 
+	sacks, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
+
 	nline := 0
 	chunk := [2][]int{}
-	input := bufio.NewScanner(os.Stdin)
-	for input.Scan() {
+	for _, line := range sacks {
 		seen := make([]int, 128)
 
-		line := input.Text()
-
 		// part1 scan
 		head, tail := line[:len(line)/2], line[len(line)/2:]
 
@@ -81,5 +94,24 @@ func main() {
 		nline++ // input is 300 lines
 	}
 
-	fmt.Println(prios[Part1], prios[Part2])
+	return Result{prios[Part1], prios[Part2]}, nil
+}
+
+// parse reads the rucksacks, two compartments of letters each
+func parse(r io.Reader) ([]string, error) {
+	sacks := make([]string, 0, 300)
+
+	letter := func(c rune) bool {
+		return !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z')
+	}
+
+	input := bufio.NewScanner(r)
+	for input.Scan() {
+		line := input.Text()
+		if len(line)%2 != 0 || strings.IndexFunc(line, letter) >= 0 {
+			return nil, fmt.Errorf("bad rucksack %q", line)
+		}
+		sacks = append(sacks, line)
+	}
+	return sacks, input.Err()
 }
@@ -13,8 +13,11 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"strings"
+
+	"github.com/erik-adelbert/aoc/lib/num"
 )
 
 // part indices
@@ -24,24 +27,24 @@ const (
 )
 
 func main() {
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2)
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
 	counts := [2]int{}
 
-	input := bufio.NewScanner(os.Stdin)
-	for input.Scan() {
-		// input text: ^(\d+)-(\d+),(\d+)-(\d+)$
-		// fields:        0     1     2     3
-		// varname:       l1    r1    l2    r2
-		fields := strings.FieldsFunc(
-			input.Text(),
-			func(r rune) bool {
-				return r == '-' || r == ','
-			},
-		)
+	pairs, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
 
-		l1 := atoi(fields[0])
-		r1 := atoi(fields[1])
-		l2 := atoi(fields[2])
-		r2 := atoi(fields[3])
+	for _, p := range pairs {
+		l1, r1, l2, r2 := p[0], p[1], p[2], p[3]
 
 		// closed segments layout ex.
 		//  l1        r1
@@ -57,14 +60,37 @@ func main() {
 	}
 
 	// every contained segment is intersecting as well
-	fmt.Println(counts[Part1], counts[Part1]+counts[Part2])
+
+	return Result{counts[Part1], counts[Part1] + counts[Part2]}, nil
 }
 
-// strconv.Atoi simplified core loop
-// s is ^\d+$
-func atoi(s string) (n int) {
-	for i := range s {
-		n = 10*n + int(s[i]-'0')
+// parse reads the section assignment pairs
+func parse(r io.Reader) ([][4]int, error) {
+	pairs := make([][4]int, 0, 1000)
+
+	input := bufio.NewScanner(r)
+	for input.Scan() {
+		// input text: ^(\d+)-(\d+),(\d+)-(\d+)$
+		// fields:        0     1     2     3
+		// varname:       l1    r1    l2    r2
+		fields := strings.FieldsFunc(
+			input.Text(),
+			func(r rune) bool {
+				return r == '-' || r == ','
+			},
+		)
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("bad assignment pair %q", input.Text())
+		}
+
+		var p [4]int
+		for i, f := range fields {
+			if strings.Trim(f, "0123456789") != "" {
+				return nil, fmt.Errorf("bad section %q", f)
+			}
+			p[i] = num.Atoi(f)
+		}
+		pairs = append(pairs, p)
 	}
-	return
+	return pairs, input.Err()
 }
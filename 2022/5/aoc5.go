@@ -12,26 +12,61 @@ package main
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
+
+	"github.com/erik-adelbert/aoc/lib/num"
 )
 
 func main() {
-	var ws worlds // part1&2
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1)
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
 
-	input := bufio.NewScanner(os.Stdin)
-	ws.load(input)
+	ws, moves, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
+
+	for _, m := range moves {
+		ws.move(m[2], m[1], m[0])
+	}
+
+	return Result{ws, nil}, nil
+}
+
+// parse reads the starting stacks and the rearrangement moves, their
+// crate count, source and target stacks
+func parse(r io.Reader) (ws worlds, moves [][3]int, err error) {
+	input := bufio.NewScanner(r)
+	if err := ws.load(input); err != nil {
+		return ws, nil, err
+	}
 
 	// read moves
 	for input.Scan() {
 		// input text: ^move (\d+) from (\d+) to (\d+)$
 		// args:          0    1     2    3    4   5
 		args := strings.Fields(input.Text())
-		ws.move(args[5], args[3], args[1])
-	}
+		if len(args) != 6 || args[0] != "move" || args[2] != "from" || args[4] != "to" {
+			return ws, nil, fmt.Errorf("bad move %q", input.Text())
+		}
 
-	fmt.Println(ws)
+		m := [3]int{num.Atoi(args[1]), num.Atoi(args[3]), num.Atoi(args[5])}
+		if m[1] < 1 || m[1] >= len(ws[Part1]) || m[2] < 1 || m[2] >= len(ws[Part1]) {
+			return ws, nil, fmt.Errorf("move %q: no such stack", input.Text())
+		}
+		moves = append(moves, m)
+	}
+	return ws, moves, input.Err()
 }
 
 // a world is a slice of adressable byte stacks
@@ -95,7 +130,7 @@ func (ws worlds) String() string {
 
 // read initial state from input
 // part 1&2 worlds start in the same state
-func (ws *worlds) load(input *bufio.Scanner) {
+func (ws *worlds) load(input *bufio.Scanner) error {
 	//                 [B] [L]     [J]
 	//             [B] [Q] [R]     [D] [T]
 	//             [G] [H] [H] [M] [N] [F]
@@ -123,9 +158,17 @@ func (ws *worlds) load(input *bufio.Scanner) {
 		}
 		lines = append(lines, line)
 	}
+	if len(lines) == 0 {
+		return errors.New("no stacks")
+	}
+	for _, line := range lines {
+		if len(line) > len(lines[len(lines)-1]) {
+			return errors.New("crates out of the numbered stacks")
+		}
+	}
 
 	// demux
-	for len(ws[Part1]) <= len(lines[0]) {
+	for len(ws[Part1]) <= len(lines[len(lines)-1]) {
 		ws[Part1] = append(ws[Part1], make([]byte, 0, 16))
 		ws[Part2] = append(ws[Part2], make([]byte, 0, 16))
 	}
@@ -140,22 +183,12 @@ func (ws *worlds) load(input *bufio.Scanner) {
 			}
 		}
 	}
+	return nil
 }
 
 // muxed move for part1&2 worlds
-func (ws worlds) move(dst, src, size string) {
-	d, s, n := atoi(dst), atoi(src), atoi(size)
-
+func (ws worlds) move(d, s, n int) {
 	// demux
 	ws[Part1].push(d, ws[Part1].pop(s, n))
 	ws[Part2].push(d, ws[Part2].cut(s, n))
 }
-
-// strconv.Atoi simplified core loop
-// s is ^(\d+)$
-func atoi(s string) (n int) {
-	for i := range s {
-		n = 10*n + int(s[i]-'0')
-	}
-	return
-}
@@ -0,0 +1,14 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func FuzzParse(f *testing.F) {
+	seedExamples(f)
+	fuzzParse(f, func(data []byte) ([][3]int, error) {
+		_, moves, err := parse(bytes.NewReader(data))
+		return moves, err
+	})
+}
@@ -12,11 +12,23 @@ package main
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 )
 
 func main() {
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1) // part1
+		fmt.Println(res.Part2) // part2
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
 	// state handlers for parts 1&2
 	// https://go.dev/talks/2011/lex.slide
 	type state func(int) (next state, match bool)
@@ -45,10 +57,15 @@ func main() {
 	// initial state is solving for part1
 	check := part1
 
+	var marks []int // part1 & part2 markers
+
+	stream, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
+
 	// slide over single line input
-	input := bufio.NewScanner(os.Stdin)
-	input.Scan()
-	for i, c := range input.Bytes() {
+	for i, c := range stream {
 		//   outside current window?
 		//   extend window!
 		// or
@@ -66,9 +83,9 @@ func main() {
 		// states are self transitioning check functions
 		var match bool
 		if check, match = check(wlen); match {
-			fmt.Println(i + 1)
+			marks = append(marks, i+1)
 			if check == nil { // terminal state
-				return
+				return Result{marks[0], marks[1]}, nil
 			}
 		}
 
@@ -82,4 +99,20 @@ func main() {
 		// }
 
 	}
+
+	return Result{}, errors.New("no marker found")
+}
+
+// parse reads the datastream, a single line of letters
+func parse(r io.Reader) ([]byte, error) {
+	input := bufio.NewScanner(r)
+	input.Scan()
+
+	stream := input.Bytes()
+	for _, c := range stream {
+		if c < 'a' || c > 'z' {
+			return nil, fmt.Errorf("bad datastream character %q", c)
+		}
+	}
+	return stream, input.Err()
 }
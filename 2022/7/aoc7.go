@@ -12,73 +12,108 @@ package main
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"slices"
 	"sort"
 	"strings"
+
+	"github.com/erik-adelbert/aoc/lib/num"
 )
 
 var subdirs []int // subdir sizes
 
 func main() {
-	var root int // root size
-	input := bufio.NewScanner(os.Stdin)
-	input.Scan()
-	_ = input.Text() // discard initial cd /
-	root = tree(input)
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2)
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	root, dirs, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
 
 	// part1 sum
 	smalls := 0
-	for i := 0; subdirs[i] <= 100_000; i++ {
-		smalls += subdirs[i]
+	for i := 0; dirs[i] <= 100_000; i++ {
+		smalls += dirs[i]
 	}
 
 	// part2 binsearch
-	i := sort.SearchInts(subdirs, root-40_000_000)
+	i := sort.SearchInts(dirs, root-40_000_000)
+
+	return Result{smalls, dirs[i]}, nil
+}
+
+// parse replays the terminal output, it returns the root size and the
+// sorted sizes of the directories under it
+func parse(r io.Reader) (root int, dirs []int, err error) {
+	subdirs = subdirs[:0]
 
-	fmt.Println(smalls, subdirs[i])
+	input := bufio.NewScanner(r)
+	input.Scan()
+	_ = input.Text() // discard initial cd /
+	if root, err = tree(input); err != nil {
+		return 0, nil, err
+	}
+	slices.Sort(subdirs)
+
+	return root, subdirs, input.Err()
 }
 
-func tree(input *bufio.Scanner) int {
+func tree(input *bufio.Scanner) (int, error) {
 	root := 0
 	for input.Scan() {
 		line := input.Text()
+		if line == "" {
+			return 0, errors.New("empty terminal line")
+		}
 
 		switch line[0] {
 		case 'd':
 			// discard dir
 		case '$':
-			fields := strings.Fields(line[2:])
-			if fields[0] == "cd" {
+			fields := strings.Fields(line[1:])
+			switch {
+			case len(fields) == 1 && fields[0] == "ls":
+				// discard ls
+			case len(fields) == 2 && fields[0] == "cd":
 				switch fields[1] {
 				case "..":
-					return root
+					return root, nil
 				default:
-					subdir := tree(input)
+					subdir, err := tree(input)
+					if err != nil {
+						return 0, err
+					}
 					root += subdir
 					subdirs = append(subdirs, subdir)
 				}
+			default:
+				return 0, fmt.Errorf("bad command %q", line)
 			}
-			// discard ls
 		default:
-			root += file(line)
+			size, err := file(line)
+			if err != nil {
+				return 0, err
+			}
+			root += size
 		}
 	}
-	slices.Sort(subdirs)
-	return root
+	return root, nil
 }
 
-func file(line string) int {
+func file(line string) (int, error) {
 	fields := strings.Fields(line)
-	return atoi(fields[0])
-}
-
-// strconv.Atoi simplified core loop
-// s is ^\d+$
-func atoi(s string) (n int) {
-	for i := range s {
-		n = 10*n + int(s[i]-'0')
+	if len(fields) != 2 || strings.Trim(fields[0], "0123456789") != "" {
+		return 0, fmt.Errorf("bad file %q", line)
 	}
-	return
+	return num.Atoi(fields[0]), nil
 }
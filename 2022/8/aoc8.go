@@ -12,7 +12,10 @@ package main
 
 import (
 	"bufio"
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 )
 
@@ -23,13 +26,21 @@ const (
 )
 
 func main() {
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2)
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
 	counts := [2]int{0, 0}
 
 	// store all axis
-	M := make([][]byte, 0, 128)
-	input := bufio.NewScanner(os.Stdin)
-	for input.Scan() {
-		M = append(M, []byte(input.Text()))
+	M, err := parse(r)
+	if err != nil {
+		return Result{}, err
 	}
 
 	W, Σ := mirror(M), transpose(M)
@@ -82,7 +93,28 @@ func main() {
 		}
 	}
 
-	fmt.Println(counts[Part1], counts[Part2])
+	return Result{counts[Part1], counts[Part2]}, nil
+}
+
+// parse reads the tree heights, rows of digits of the same width
+func parse(r io.Reader) ([][]byte, error) {
+	M := make([][]byte, 0, 128)
+
+	input := bufio.NewScanner(r)
+	for input.Scan() {
+		row := []byte(input.Text())
+		if len(row) == 0 || len(M) > 0 && len(row) != len(M[0]) {
+			return nil, fmt.Errorf("tree row %d: ragged map", len(M)+1)
+		}
+		if len(bytes.Trim(row, "0123456789")) > 0 {
+			return nil, fmt.Errorf("bad tree row %q", row)
+		}
+		M = append(M, row)
+	}
+	if len(M) == 0 {
+		return nil, errors.New("no trees")
+	}
+	return M, input.Err()
 }
 
 func transpose(m [][]byte) [][]byte {
@@ -13,7 +13,11 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
+	"strings"
+
+	"github.com/erik-adelbert/aoc/lib/num"
 )
 
 // part indices
@@ -26,6 +30,15 @@ const (
 type XY [2]int
 
 func main() {
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2)
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
 	visits := [2]set{}
 
 	visits[Part1].add(XY{0, 0})
@@ -38,12 +51,13 @@ func main() {
 
 	knots := [10]XY{}
 
-	input := bufio.NewScanner(os.Stdin)
-	for input.Scan() {
-		// input text: ^([U,D,L,R]) (\d)$
-		line := input.Text()
+	motions, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
 
-		θ, n := line[0], atoi(line[2:]) // heading, steps
+	for _, m := range motions {
+		θ, n := m.θ, m.n // heading, steps
 		for n > 0 {
 			// move head
 			knots[0].add(off[θ])
@@ -67,7 +81,30 @@ func main() {
 		}
 	}
 
-	fmt.Println(visits[Part1].len(), visits[Part2].len())
+	return Result{visits[Part1].len(), visits[Part2].len()}, nil
+}
+
+// motion is a head motion, n steps towards θ
+type motion struct {
+	θ byte
+	n int
+}
+
+// parse reads the head motions
+func parse(r io.Reader) ([]motion, error) {
+	motions := make([]motion, 0, 2000)
+
+	input := bufio.NewScanner(r)
+	for input.Scan() {
+		// input text: ^([U,D,L,R]) (\d)$
+		line := input.Text()
+		if len(line) < 3 || !strings.ContainsRune("UDLR", rune(line[0])) || line[1] != ' ' ||
+			strings.Trim(line[2:], "0123456789") != "" {
+			return nil, fmt.Errorf("bad motion %q", line)
+		}
+		motions = append(motions, motion{line[0], num.Atoi(line[2:])})
+	}
+	return motions, input.Err()
 }
 
 func (a *XY) add(b XY) {
@@ -125,12 +162,3 @@ func (s *set) add(x XY) {
 		s.count++
 	}
 }
-
-// strconv.Atoi simplified core loop
-// s is ^\d+$
-func atoi(s string) (n int) {
-	for i := range s {
-		n = 10*n + int(s[i]-'0')
-	}
-	return
-}
@@ -13,19 +13,53 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 )
 
 func main() {
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2) // part 1 & 2
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	lines, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
+
 	sum1, sum2 := 0, 0
-	input := bufio.NewScanner(os.Stdin)
+	for n, line := range lines {
+		n1, ok1 := stoi(line, DigitsOnly)    // part1
+		n2, ok2 := stoi(line, TextAndDigits) // part2
+		if !ok1 || !ok2 {
+			return Result{}, fmt.Errorf("line %d: no digit in %q", n+1, line)
+		}
+		sum1, sum2 = sum1+n1, sum2+n2
+	}
+
+	return Result{sum1, sum2}, nil
+}
+
+// parse reads the calibration document, lines of lowercase letters and
+// digits
+func parse(r io.Reader) ([]string, error) {
+	var lines []string
+
+	input := bufio.NewScanner(r)
 	for input.Scan() {
-		input := input.Text()
-		sum1 += stoi(input, DigitsOnly)    // part1
-		sum2 += stoi(input, TextAndDigits) // part2
+		line := input.Text()
+		if strings.Trim(line, "abcdefghijklmnopqrstuvwxyz0123456789") != "" {
+			return nil, fmt.Errorf("bad calibration %q", line)
+		}
+		lines = append(lines, line)
 	}
-	fmt.Println(sum1, sum2) // part 1 & 2
+	return lines, input.Err()
 }
 
 const (
@@ -33,7 +67,7 @@ const (
 	TextAndDigits = !DigitsOnly
 )
 
-func stoi(s string, mode bool) int {
+func stoi(s string, mode bool) (int, bool) {
 	trie := digits
 	if mode == TextAndDigits {
 		trie = texts
@@ -67,11 +101,11 @@ func stoi(s string, mode bool) int {
 		//  l == r when there is a single number in s
 		//  l != r when there are at least two
 		if l*r > 0 {
-			return 10*l + r
+			return 10*l + r, true
 		}
 	}
 
-	panic("unreachable")
+	return 0, false
 }
 
 // see:
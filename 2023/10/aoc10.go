@@ -2,23 +2,53 @@ package main
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 )
 
 func main() {
-	w := new(world)
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2)
+	}))
+}
 
-	input := bufio.NewScanner(os.Stdin)
-	for j := 0; input.Scan(); j++ {
-		w.load(j, input.Text())
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	w, err := parse(r)
+	if err != nil {
+		return Result{}, err
 	}
 
 	//fmt.Println(w)
 
 	path, area := w.findpath()
-	fmt.Println(len(path)/2, area)
+
+	return Result{len(path) / 2, area}, nil
+}
+
+// parse reads the pipes sketch, it fits in MAXN×MAXN and holds a single S
+func parse(r io.Reader) (*world, error) {
+	w := new(world)
+
+	nstart := 0
+	input := bufio.NewScanner(r)
+	for j := 0; input.Scan(); j++ {
+		line := input.Text()
+		if j == MAXN || len(line) > MAXN || strings.Trim(line, "|-LJ7F.S") != "" {
+			return nil, fmt.Errorf("bad sketch row %q", line)
+		}
+		nstart += strings.Count(line, "S")
+		w.load(j, line)
+	}
+	if nstart != 1 {
+		return nil, errors.New("bad sketch: want a single start")
+	}
+	return w, input.Err()
 }
 
 const MAXN = 140
@@ -35,7 +65,7 @@ func (w *world) load(j int, s string) {
 	i := φ(j, 0)
 	copy(w.maze[i:], s)
 
-	if i := strings.Index(s, "S"); i > 0 {
+	if i := strings.Index(s, "S"); i >= 0 {
 		w.O = φ(j, i)
 	}
 }
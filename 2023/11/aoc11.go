@@ -3,19 +3,43 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"slices"
+	"strings"
 )
 
 func main() {
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2)
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	u, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
+
+	return Result{u.pairdist(2), u.pairdist(1_000_000)}, nil
+}
+
+// parse reads the galaxies positions off the image
+func parse(r io.Reader) (universe, error) {
 	u := make(universe, 0, 128)
 
-	input := bufio.NewScanner(os.Stdin)
+	input := bufio.NewScanner(r)
 	for j := 0; input.Scan(); j++ {
-		u = u.readline(j, input.Text())
+		line := input.Text()
+		if strings.Trim(line, ".#") != "" {
+			return nil, fmt.Errorf("bad image row %q", line)
+		}
+		u = u.readline(j, line)
 	}
-
-	fmt.Println(u.pairdist(2), u.pairdist(1_000_000))
+	return u, input.Err()
 }
 
 type galaxy struct {
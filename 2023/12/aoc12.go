@@ -3,24 +3,30 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
+	"strconv"
 	"strings"
 )
 
 func main() {
-	sum1, sum2 := 0, 0
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2)
+	}))
+}
 
-	input := bufio.NewScanner(os.Stdin)
-	for input.Scan() {
-		input := fields(input.Text())
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
 
-		blocks := split(input[1], ",")
+	records, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
 
-		springs1 := input[0]
-		blocks1 := make([]int, len(blocks))
-		for i := range blocks {
-			blocks1[i] = atoi(blocks[i])
-		}
+	sum1, sum2 := 0, 0
+	for _, rec := range records {
+		springs1, blocks1 := rec.springs, rec.blocks
 
 		springs2 := join([]string{
 			springs1, springs1, springs1, springs1, springs1,
@@ -32,9 +38,41 @@ func main() {
 
 		sum1 += solve(springs1, blocks1)
 		sum2 += solve(springs2, blocks2)
+	}
+
+	return Result{sum1, sum2}, nil
+}
+
+type record struct {
+	springs string
+	blocks  []int
+}
 
+// parse reads the condition records, springs and damaged blocks sizes
+func parse(r io.Reader) ([]record, error) {
+	records := make([]record, 0, 1024)
+
+	input := bufio.NewScanner(r)
+	for input.Scan() {
+		line := input.Text()
+
+		args := fields(line)
+		if len(args) != 2 || strings.Trim(args[0], ".#?") != "" {
+			return nil, fmt.Errorf("bad record %q", line)
+		}
+
+		blocks := split(args[1], ",")
+		rec := record{args[0], make([]int, len(blocks))}
+		for i := range blocks {
+			n, err := strconv.Atoi(blocks[i])
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("bad record %q", line)
+			}
+			rec.blocks[i] = n
+		}
+		records = append(records, rec)
 	}
-	fmt.Println(sum1, sum2)
+	return records, input.Err()
 }
 
 func solve(springs string, blocks []int) int {
@@ -89,12 +127,3 @@ var (
 	split     = strings.Split
 	trimRight = strings.TrimRight
 )
-
-// strconv.Atoi simplified core loop
-// s is ^\d+$
-func atoi(s string) (n int) {
-	for i := range s {
-		n = 10*n + int(s[i]-'0')
-	}
-	return
-}
@@ -3,14 +3,21 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"math/bits"
 	"os"
 	"strings"
 )
 
 func main() {
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2)
+	}))
+}
 
-	fmt.Println('a')
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
 
 	sum1, sum2 := 0, 0
 
@@ -22,16 +29,37 @@ func main() {
 	// 	24, 0, 1, 0, 3, 0, 1, 0, 3, 14, 14,
 	// }))
 
+	terrains, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
+
+	for _, terrain := range terrains {
+		n1, n2 := solve(terrain)
+		sum1 += n1
+		sum2 += n2
+	}
+
+	return Result{sum1, sum2}, nil
+}
+
+// parse reads the blank line separated patterns, each one is at most 32×32
+func parse(r io.Reader) ([]*area, error) {
+	terrains := make([]*area, 0, 100)
+
 	terrain := newArea()
-	input := bufio.NewScanner(os.Stdin)
+	input := bufio.NewScanner(r)
 	for j := 0; input.Scan(); j++ {
 		line := input.Text()
-		switch len(line) {
-		case 0:
-			n1, n2 := solve(terrain)
-			sum1 += n1
-			sum2 += n2
+		switch {
+		case len(line) == 0:
+			if terrain.H > 0 {
+				terrains = append(terrains, terrain)
+			}
 			j, terrain = -1, newArea()
+		case j == 32 || len(line) > 32 || strings.Trim(line, ".#") != "" ||
+			j > 0 && len(line) != terrain.W:
+			return nil, fmt.Errorf("bad pattern row %q", line)
 		default:
 			terrain.H = max(terrain.H, j+1)
 			terrain.W = max(terrain.W, len(line))
@@ -43,11 +71,11 @@ func main() {
 		}
 	}
 	// last input
-	n1, n2 := solve(terrain)
-	sum1 += n1
-	sum2 += n2
+	if terrain.H > 0 {
+		terrains = append(terrains, terrain)
+	}
 
-	fmt.Println(sum1, sum2)
+	return terrains, input.Err()
 }
 
 const (
@@ -357,12 +385,3 @@ func (BA bitarray32) String() string {
 }
 
 var popcnt, trail0 = bits.OnesCount32, bits.TrailingZeros32
-
-// strconv.Atoi simplified core loop
-// s is ^\d+$
-func atoi(s string) (n int) {
-	for i := range s {
-		n = 10*n + int(s[i]-'0')
-	}
-	return
-}
@@ -2,26 +2,57 @@ package main
 
 import (
 	"bufio"
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"slices"
 	"strings"
 )
 
 func main() {
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2)
+	}))
+}
 
-	g := newGrid[byte](0)
-	input := bufio.NewScanner(os.Stdin)
-	for j := 0; input.Scan(); j++ {
-		g.load(j, input.Bytes())
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	g, err := parse(r)
+	if err != nil {
+		return Result{}, err
 	}
 
 	b := newBoard(g.widen())
 
-	fmt.Println(
+	return Result{
 		b.tiltNorth(),
 		b.tiltCycle(1_000_000_000),
-	)
+	}, nil
+}
+
+// parse reads the platform, a square of at most MAXN rows
+func parse(r io.Reader) (*grid[byte], error) {
+	g := newGrid[byte](0)
+
+	h := 0
+	input := bufio.NewScanner(r)
+	for j := 0; input.Scan(); j++ {
+		line := input.Bytes()
+		if j == MAXN || len(line) == 0 || len(line) > MAXN ||
+			j > 0 && len(line) != g.w || len(bytes.Trim(line, "O.#")) > 0 {
+			return nil, fmt.Errorf("bad platform row %q", line)
+		}
+		g.load(j, line)
+		h++
+	}
+	if h == 0 || h != g.w {
+		return nil, errors.New("bad platform: not a square")
+	}
+	return g, input.Err()
 }
 
 const MAXN = 100
@@ -85,7 +116,12 @@ func (g *grid[T]) widen() *grid[T] {
 func (g *grid[T]) String() string {
 	var sb strings.Builder
 	for j := 0; j < g.w; j++ {
-		fmt.Fprintln(&sb, g.d[j*g.w:(j+1)*g.w])
+		switch row := any(g.d[j*g.w : (j+1)*g.w]).(type) {
+		case []byte:
+			fmt.Fprintf(&sb, "%s\n", row) // platform
+		default:
+			fmt.Fprintln(&sb, row)
+		}
 	}
 	return sb.String()
 }
@@ -191,15 +227,8 @@ func (b *board) tiltNorth() (load int) {
 type hashkey []int16
 
 func (h hashkey) hash() string {
-	var n int
-
-	if n = len(h); n > 99 {
-		// /!\ tune cropping if needed
-		n = n * 54 / 100
-	}
-
 	var sb strings.Builder
-	for i := range h[:n] {
+	for i := range h {
 		fmt.Fprintf(&sb, "%x", h[i])
 	}
 	return sb.String()
@@ -3,24 +3,37 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"slices"
 	"strings"
+
+	"github.com/erik-adelbert/aoc/lib/num"
 )
 
 func main() {
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2)
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	steps, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
+
 	boxes := newBoxes()
 	sum := 0
 
-	input := bufio.NewScanner(os.Stdin)
-	for input.Scan() {
-		ops := strings.Split(input.Text(), ",")
-		for i, s := range ops {
-			sum += hash(ops[i])
+	for i, s := range steps {
+		sum += hash(s)
 
-			op := mkop(i, s)
-			boxes[hash(op.name)].enqueue(op)
-		}
+		op := mkop(i, s)
+		boxes[hash(op.name)].enqueue(op)
 	}
 
 	pwr := 0
@@ -30,7 +43,31 @@ func main() {
 		}
 	}
 
-	fmt.Println(sum, pwr)
+	return Result{sum, pwr}, nil
+}
+
+// parse reads the initialization sequence, its steps either remove a label
+// or set its focal length from 1 to 9
+func parse(r io.Reader) ([]string, error) {
+	var steps []string
+
+	input := bufio.NewScanner(r)
+	for input.Scan() {
+		for _, s := range strings.Split(input.Text(), ",") {
+			name, op := s, ""
+			if i := strings.IndexAny(s, "-="); i >= 0 {
+				name, op = s[:i], s[i:]
+			}
+
+			isset := len(op) == 2 && op[0] == '=' && '1' <= op[1] && op[1] <= '9'
+			if name == "" || strings.Trim(name, "abcdefghijklmnopqrstuvwxyz") != "" ||
+				op != "-" && !isset {
+				return nil, fmt.Errorf("bad step %q", s)
+			}
+			steps = append(steps, s)
+		}
+	}
+	return steps, input.Err()
 }
 
 func hash(s string) (h int) {
@@ -67,7 +104,7 @@ func mkop(i int, s string) ops {
 	default:
 		args := strings.Split(s, "=")
 
-		op.name, op.val = args[0], atoi(args[1])
+		op.name, op.val = args[0], num.Atoi(args[1])
 	}
 	return op
 }
@@ -117,12 +154,3 @@ func (q *queue) process() int {
 func index(list []ops, o ops) int {
 	return slices.IndexFunc(list, func(x ops) bool { return x.name == o.name })
 }
-
-// strconv.Atoi simplified core loop
-// s is ^\d+$
-func atoi(s string) (n int) {
-	for i := range s {
-		n = 10*n + int(s[i]-'0')
-	}
-	return
-}
@@ -2,27 +2,60 @@ package main
 
 import (
 	"bufio"
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"sync"
 )
 
 func main() {
-	g := newGrid()
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2)
+	}))
+}
 
-	input := bufio.NewScanner(os.Stdin)
-	var j int
-	for j = 0; input.Scan(); j++ {
-		g.load(j, input.Bytes())
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	g, err := parse(r)
+	if err != nil {
+		return Result{}, err
 	}
-	g.redim(j)
 
 	// part1 trace from first cell heading right
 	part1 := g.traceOne(cell{0, R})
 	part2 := g.traceAll()
 
-	fmt.Println(part1, part2)
+	return Result{part1, part2}, nil
+}
+
+// parse reads the contraption, a square of at most MAX rows
+func parse(r io.Reader) (*grid, error) {
+	g := newGrid()
+
+	input := bufio.NewScanner(r)
+	var j, w int
+	for j = 0; input.Scan(); j++ {
+		line := input.Bytes()
+		if j == 0 {
+			w = len(line)
+		}
+		if j == MAX || len(line) == 0 || len(line) != w ||
+			len(bytes.Trim(line, "./\\|-")) > 0 {
+			return nil, fmt.Errorf("bad contraption row %q", line)
+		}
+		g.load(j, line)
+	}
+	if j == 0 || j != w {
+		return nil, errors.New("bad contraption: not a square")
+	}
+	g.redim(j)
+
+	return g, input.Err()
 }
 
 func (g *grid) traceAll() int {
@@ -12,29 +12,69 @@ package main
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"sync"
 )
 
 func main() {
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2)
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	world, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
+
+	part1, part2 := astar(world, pot{1, 3}, pot{4, 10})
+
+	return Result{part1, part2}, nil
+}
+
+// parse reads the heat loss map, a rectangle of digits fitting in MAXN×MAXN
+func parse(r io.Reader) (*grid, error) {
 	world := newGrid()
 
 	h, w := 0, 0
-	input := bufio.NewScanner(os.Stdin)
+	input := bufio.NewScanner(r)
 	for input.Scan() {
 		bytes := input.Bytes()
+		if h == 0 {
+			w = len(bytes)
+		}
+		if h == MAXN || w == 0 || w > MAXN || len(bytes) != w ||
+			!isdigits(bytes) {
+			return nil, fmt.Errorf("bad map row %q", bytes)
+		}
 
-		w = len(bytes)
 		copy(world.d[h*w:], bytes)
 
 		h++
 	}
+	if h == 0 {
+		return nil, errors.New("bad map: empty")
+	}
 	world.redim(h, w)
 
-	part1, part2 := astar(world, pot{1, 3}, pot{4, 10})
-	fmt.Println(part1, part2)
+	return world, input.Err()
+}
+
+func isdigits(s []byte) bool {
+	for _, c := range s {
+		if c < '0' || '9' < c {
+			return false
+		}
+	}
+	return true
 }
 
 func astar(g *grid, p ...pot) (int, int) {
@@ -3,32 +3,60 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"strings"
+
+	"github.com/erik-adelbert/aoc/lib/num"
 )
 
 func main() {
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2)
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	p1, p2, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
 
-	var p1, p2 lagoon
+	return Result{p1.area(), p2.area()}, nil
+}
 
+// parse reads the dig plan, it digs the part 1 lagoon along the plain
+// instructions and the part 2 one along the hex encoded ones
+func parse(r io.Reader) (p1, p2 lagoon, err error) {
 	decode := func(s string) (byte, int) {
 		θ := "RDLU"[s[len(s)-1]-'0'] // last char encodes R, D, L or U
 		k := htoi(s[:len(s)-1])      // first chars encodes an hex number
 		return θ, k
 	}
 
-	input := bufio.NewScanner(os.Stdin)
+	input := bufio.NewScanner(r)
 	for j := 0; input.Scan(); j++ {
 		args := fields(input.Text())
 
-		θ, k := args[0][0], atoi(args[1])
+		// input is ^[RDLU] \d+ \(#\h{5}[0-3]\)$
+		if len(args) != 3 || len(args[0]) != 1 || index("RDLU", args[0]) < 0 ||
+			!isnum(args[1], "0123456789") ||
+			len(args[2]) != 9 || args[2][:2] != "(#" || args[2][8] != ')' ||
+			!isnum(args[2][2:7], "0123456789abcdef") || !isnum(args[2][7:8], "0123") {
+			return p1, p2, fmt.Errorf("bad instruction %q", input.Text())
+		}
+
+		θ, k := args[0][0], num.Atoi(args[1])
 		p1 = p1.append(θ, k)
 
 		x := args[2][2 : len(args[2])-1] // slice the hex part out of args[2] "^(#\h+)$" with \h hex digit
 		p2 = p2.append(decode(x))
 	}
 
-	fmt.Println(p1.area(), p2.area())
+	return p1, p2, input.Err()
 }
 
 type vec struct {
@@ -69,13 +97,9 @@ func (p lagoon) area() int {
 
 var fields, index = strings.Fields, strings.Index
 
-// strconv.Atoi simplified core loop
-// s is ^\d+$
-func atoi(s string) (n int) {
-	for i := range s {
-		n = 10*n + int(s[i]-'0')
-	}
-	return
+// isnum checks s is a number of at most 9 digits from set
+func isnum(s, set string) bool {
+	return len(s) > 0 && len(s) < 10 && strings.Trim(s, set) == ""
 }
 
 func htoi(s string) (n int) {
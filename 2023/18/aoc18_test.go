@@ -0,0 +1,14 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func FuzzParse(f *testing.F) {
+	seedExamples(f)
+	fuzzParse(f, func(data []byte) (lagoon, error) {
+		p1, _, err := parse(bytes.NewReader(data))
+		return p1, err
+	})
+}
@@ -3,92 +3,145 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"strings"
+
+	"github.com/erik-adelbert/aoc/lib/num"
 )
 
 func main() {
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2)
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	workflows, parts, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
+
+	sum1 := 0
+	for _, p := range workflows.process(parts) {
+		for _, x := range p {
+			sum1 += x.lo
+		}
+	}
+
+	sum2 := 0
+	for _, p := range workflows.process(all()) {
+		Π := 1
+		for _, x := range p {
+			Π *= x.hi - x.lo + 1
+		}
+		sum2 += Π
+	}
+
+	return Result{sum1, sum2}, nil
+}
+
+// parse reads the workflows then the parts ratings
+func parse(r io.Reader) (workflows, []cub4, error) {
 	workflows := make(workflows, 1024)
 	parts := make([]cub4, 0, 256)
 
-	var parse func(string)
+	var scan func(string) error
 
 	// parsing state machine
-	parseCube := func(s string) {
+	parseCube := func(s string) error {
 		// input is "{x=(\d+),m=(\d+),a=(\d+),s=(\d+)}"
+		if len(s) < 2 || s[0] != '{' || s[len(s)-1] != '}' {
+			return fmt.Errorf("bad part %q", s)
+		}
+
 		part := cub4{}
 		// tokenize
-		for i, x := range split(s[1:len(s)-1], ",") {
+		tokens := split(s[1:len(s)-1], ",")
+		if len(tokens) != len(part) {
+			return fmt.Errorf("bad part %q", s)
+		}
+		for i, x := range tokens {
 			// parse token
-			n := atoi(x[2:])
+			if len(x) < 3 || x[0] != "xmas"[i] || x[1] != '=' || !isnum(x[2:]) {
+				return fmt.Errorf("bad part %q", s)
+			}
+			n := num.Atoi(x[2:])
 			part[i] = span{n, n + 1}
 		}
 		parts = append(parts, part)
+		return nil
 	}
 
-	parseWorkflow := func(s string) {
+	parseWorkflow := func(s string) error {
 		if len(s) == 0 { // transition on empty string
-			parse = parseCube
-			return
+			scan = parseCube
+			return nil
 		}
 
 		// ex.
 		// ktc{a<1998:R,m>3286:A,x<1292:R,A}
 		// cb{a>1858:R,s>3028:R,zt}
 		i := index(s, "{") // seek start
+		if i < 1 || s[len(s)-1] != '}' {
+			return fmt.Errorf("bad workflow %q", s)
+		}
 
 		// tokenize
 		id, rules := s[:i], split(s[i+1:len(s)-1], ",")
 		for i := range rules {
 			// parse token
-			workflows[id] = append(workflows[id], parseRule(rules[i]))
+			r, err := parseRule(rules[i], i == len(rules)-1)
+			if err != nil {
+				return fmt.Errorf("bad workflow %q: %w", s, err)
+			}
+			workflows[id] = append(workflows[id], r)
 		}
+		return nil
 	}
 
-	parse = parseWorkflow // start state
-	input := bufio.NewScanner(os.Stdin)
+	scan = parseWorkflow // start state
+	input := bufio.NewScanner(r)
 	for input.Scan() {
-		parse(input.Text()) // self transition
-	}
-
-	sum1 := 0
-	for _, p := range workflows.process(parts) {
-		for _, x := range p {
-			sum1 += x.lo
+		if err := scan(input.Text()); err != nil { // self transition
+			return nil, nil, err
 		}
 	}
 
-	sum2 := 0
-	for _, p := range workflows.process(all()) {
-		Π := 1
-		for _, x := range p {
-			Π *= x.hi - x.lo + 1
-		}
-		sum2 += Π
-	}
-
-	fmt.Println(sum1, sum2)
+	return workflows, parts, input.Err()
 }
 
-func parseRule(s string) rule {
+// parseRule reads a conditional jump, or the unconditional last one
+func parseRule(s string, last bool) (rule, error) {
 	var r rule
 
 	// ex. "m>3286:A", "R", "A"
 	switch index(s, ":") {
 	case -1:
+		if !last || s == "" {
+			return r, fmt.Errorf("bad rule %q", s)
+		}
 		r = rule{jmp: s}
 	default:
 		args := split(s, ":")
+		if last || len(args) != 2 || args[1] == "" || len(args[0]) < 3 ||
+			index("xmas", args[0][:1]) < 0 || index("<>", args[0][1:2]) < 0 ||
+			!isnum(args[0][2:]) {
+			return r, fmt.Errorf("bad rule %q", s)
+		}
 		r = rule{
 			args[1],
 			cut{
 				a: axis(index("xmas", args[0][:1])),
-				x: atoi(args[0][2:]),
+				x: num.Atoi(args[0][2:]),
 			},
 			args[0][1],
 		}
 	}
-	return r
+	return r, nil
 }
 
 type axis int
@@ -246,11 +299,7 @@ func (ws workflows) process(parts []cub4) []cub4 {
 
 var index, split = strings.Index, strings.Split
 
-// strconv.Atoi simplified core loop
-// s is ^\d+$
-func atoi(s string) (n int) {
-	for i := range s {
-		n = 10*n + int(s[i]-'0')
-	}
-	return
+// isnum checks s is a rating, at most 4 digits
+func isnum(s string) bool {
+	return len(s) > 0 && len(s) < 5 && strings.Trim(s, "0123456789") == ""
 }
@@ -0,0 +1,14 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func FuzzParse(f *testing.F) {
+	seedExamples(f)
+	fuzzParse(f, func(data []byte) (workflows, error) {
+		ws, _, err := parse(bytes.NewReader(data))
+		return ws, err
+	})
+}
@@ -13,42 +13,82 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"strings"
+
+	"github.com/erik-adelbert/aoc/lib/num"
 )
 
 func main() {
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2)
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	games, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
+
 	idsum, pwsum := 0, 0
+	for i, power := range games {
+		// check validity, part1
+		valid := true
+		for color, count := range power {
+			valid = valid && count <= 14-color
+		}
+		if valid {
+			idsum += i + 1 // part1
+		}
+		pwsum += power[B] * power[G] * power[R] // part2
+	}
 
-	input := bufio.NewScanner(os.Stdin)
-	for i := 1; input.Scan(); i++ {
-		valid, power := true, [3]int{}
+	return Result{idsum, pwsum}, nil
+}
+
+// parse reads the games and records the max count drawn for each color
+func parse(r io.Reader) ([][3]int, error) {
+	games := make([][3]int, 0, 100)
+
+	input := bufio.NewScanner(r)
+	for input.Scan() {
+		var power [3]int
 
 		// input is ^Game \d+:\s(.*;\s)+(.*)$
 		input := input.Text()
 
-		draws := split(input[index(input, ": ")+2:], "; ") // ditch "^Game X: " prefix, split tail
+		k := index(input, ": ")
+		if !strings.HasPrefix(input, "Game ") || k < 0 {
+			return nil, fmt.Errorf("bad game %q", input)
+		}
+
+		draws := split(input[k+2:], "; ") // ditch "^Game X: " prefix, split tail
 		for j := range draws {
 
 			rgb := split(draws[j], ", ") // split game drawings
 			for i := range rgb {
-				fields := fields(rgb[i])             // split RGB component and count
+				fields := fields(rgb[i]) // split RGB component and count
+				if len(fields) != 2 || !isnum(fields[0]) {
+					return nil, fmt.Errorf("bad draw %q", rgb[i])
+				}
 				color := index("bgr", fields[1][:1]) // single char 'r', 'g' or 'b' -> R, G, B
-				count := atoi(fields[0])
-
-				// check validity, part1
-				valid = valid && count <= 14-color
+				if color < 0 || fields[1] != colors[color] {
+					return nil, fmt.Errorf("bad draw %q", rgb[i])
+				}
+				count := num.Atoi(fields[0])
 
-				// record max RGB power, part2
+				// record max RGB power
 				power[color] = max(power[color], count)
 			}
 		}
-		if valid {
-			idsum += i // part1
-		}
-		pwsum += power[B] * power[G] * power[R] // part2
+		games = append(games, power)
 	}
-	fmt.Println(idsum, pwsum)
+	return games, input.Err()
 }
 
 // package strings wrappers/sugars
@@ -60,13 +100,10 @@ const (
 	R
 )
 
-// strconv.Atoi simplified core loop
-// s is ^\d+$
-func atoi(s string) (n int) {
-	for i := range s {
-		n = 10*n + int(s[i]-'0')
-	}
-	return
+var colors = [...]string{B: "blue", G: "green", R: "red"}
+
+func isnum(s string) bool {
+	return len(s) > 0 && len(s) < 10 && strings.Trim(s, "0123456789") == ""
 }
 
 var DEBUG = true
@@ -2,20 +2,48 @@ package main
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"math/bits"
 	"os"
+	"slices"
 	"strings"
 )
 
 func main() {
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2)
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	mods, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
+
+	c, err := newCircuit(mods)
+	if err != nil {
+		return Result{}, err
+	}
+
+	return Result{c.npulse(), c.rx1()}, nil
+}
 
+// parse reads the modules network
+func parse(r io.Reader) (modules, error) {
 	mods := make(modules, 64)
 
-	// parse network
-	input := bufio.NewScanner(os.Stdin)
-	for input.Scan() {
+	input := bufio.NewScanner(r)
+	for n := 1; input.Scan(); n++ {
 		args := split(input.Text(), " -> ")
+		if len(args) != 2 || args[0] == "" || slices.Contains(split(args[1], ", "), "") {
+			return nil, fmt.Errorf("line %d: expected \"module -> outputs\", got %q", n, input.Text())
+		}
 
 		var and bool
 		switch args[0][0] {
@@ -28,10 +56,7 @@ func main() {
 
 		mods[args[0]] = newModule(and, split(args[1], ", "))
 	}
-
-	c := newCircuit(mods)
-	fmt.Println(c.npulse(), c.rx1())
-
+	return mods, input.Err()
 }
 
 type module struct {
@@ -53,7 +78,9 @@ type modules map[string]*module
 
 type circuit [4]uint32
 
-func newCircuit(mods modules) circuit {
+// newCircuit reduces the network to the counters feeding rx, every input
+// is built that way
+func newCircuit(mods modules) (circuit, error) {
 	type node struct {
 		id   string
 		v, b uint32
@@ -69,7 +96,12 @@ func newCircuit(mods modules) circuit {
 		return top.id, top.v, top.b
 	}
 
-	for _, start := range mods["broadcaster"].outs {
+	bcast, ok := mods["broadcaster"]
+	if !ok {
+		return circuit{}, errors.New("no broadcaster")
+	}
+
+	for _, start := range bcast.outs {
 		push(start, 0, 1)
 	}
 
@@ -91,7 +123,10 @@ JOBS:
 		fflops = append(fflops, v|b)
 	}
 
-	return circuit(fflops)
+	if len(fflops) != len(circuit{}) {
+		return circuit{}, fmt.Errorf("expected %d counters, got %d", len(circuit{}), len(fflops))
+	}
+	return circuit(fflops), nil
 }
 
 func (c circuit) npulse() uint32 {
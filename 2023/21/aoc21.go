@@ -2,21 +2,55 @@ package main
 
 import (
 	"bufio"
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
+
+	"github.com/erik-adelbert/aoc/lib/num"
 )
 
 func main() {
-	plots := newArea()
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2)
+	}))
+}
 
-	input := bufio.NewScanner(os.Stdin)
-	for j := 0; input.Scan(); j++ {
-		plots.load(j, input.Bytes())
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	plots, err := parse(r)
+	if err != nil {
+		return Result{}, err
 	}
 	plots.eyefill()
 
-	fmt.Println(plots.walk(64), plots.solve(26_501_365))
+	return Result{plots.walk(64), plots.solve(26_501_365)}, nil
+}
+
+// parse reads the garden map, a square of at most MAX rows with a single S
+func parse(r io.Reader) (*area, error) {
+	plots := newArea()
+
+	h, nstart := 0, 0
+	input := bufio.NewScanner(r)
+	for j := 0; input.Scan(); j++ {
+		line := input.Bytes()
+		if j == MAX || len(line) == 0 || len(line) > MAX ||
+			j > 0 && len(line) != plots.w || len(bytes.Trim(line, ".#S")) > 0 {
+			return nil, fmt.Errorf("bad map row %q", line)
+		}
+		nstart += bytes.Count(line, []byte{'S'})
+		plots.load(j, line)
+		h++
+	}
+	if h != plots.w || nstart != 1 {
+		return nil, errors.New("bad map: want a square with a single start")
+	}
+	return plots, input.Err()
 }
 
 const MAX, OFF = 131, 300
@@ -118,7 +152,7 @@ func (a *area) solve(nstep int) int {
 func (a *area) isrock(k yx) bool {
 	θ := func(p yx) yx {
 		j, i := Y(p), X(p)
-		return YX(mod(j, MAX), mod(i, MAX))
+		return YX(num.Mod(j, MAX), num.Mod(i, MAX))
 	}
 
 	return a.m[θ(k)]
@@ -151,7 +185,3 @@ func (a *area) String() string {
 
 	return sb.String()
 }
-
-func mod(a, b int) int {
-	return ((a % b) + b) % b
-}
@@ -3,30 +3,66 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"slices"
 	"strings"
+
+	"github.com/erik-adelbert/aoc/lib/num"
 )
 
 func main() {
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2)
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	blocks, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
+
+	p1, p2 := blocks.graph()
+
+	return Result{p1, p2}, nil
+}
+
+// parse reads the bricks snapshot, bricks ends are ordered and lay within
+// a 10×10 ground
+func parse(r io.Reader) (blocks, error) {
 	blocks := make(blocks, 0, 1500)
 
-	input := bufio.NewScanner(os.Stdin)
+	input := bufio.NewScanner(r)
 	for id := 0; input.Scan(); id++ {
 		args := split(input.Text(), "~")
+		if len(args) != 2 {
+			return nil, fmt.Errorf("bad brick %q", input.Text())
+		}
 
 		α := split(args[0], ",")
 		β := split(args[1], ",")
+		if len(α) != 3 || len(β) != 3 {
+			return nil, fmt.Errorf("bad brick %q", input.Text())
+		}
 
 		b := newBlock(id)
 		for i := range α {
-			b.α[i] = atoi(α[i])
-			b.β[i] = atoi(β[i])
+			if !isnum(α[i]) || !isnum(β[i]) {
+				return nil, fmt.Errorf("bad brick %q", input.Text())
+			}
+			b.α[i] = num.Atoi(α[i])
+			b.β[i] = num.Atoi(β[i])
+			if b.α[i] > b.β[i] || i != Z && b.β[i] > 9 {
+				return nil, fmt.Errorf("bad brick %q", input.Text())
+			}
 		}
 		blocks = append(blocks, b)
 	}
-
-	fmt.Println(blocks.graph())
+	return blocks, input.Err()
 }
 
 type blocks []*block
@@ -163,11 +199,6 @@ var split = strings.Split
 
 const MaxInt = int(^uint(0) >> 1)
 
-// strconv.Atoi simplified core loop
-// s is ^\d+$
-func atoi(s string) (n int) {
-	for i := range s {
-		n = 10*n + int(s[i]-'0')
-	}
-	return
+func isnum(s string) bool {
+	return len(s) > 0 && len(s) < 10 && strings.Trim(s, "0123456789") == ""
 }
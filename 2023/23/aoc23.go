@@ -2,7 +2,10 @@ package main
 
 import (
 	"bufio"
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"math/bits"
 	"os"
 	"strings"
@@ -10,15 +13,44 @@ import (
 )
 
 func main() {
-	grid := newGrid()
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2)
+	}))
+}
 
-	input := bufio.NewScanner(os.Stdin)
-	for j := 0; input.Scan(); j++ {
-		grid.load(input.Bytes())
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	grid, err := parse(r)
+	if err != nil {
+		return Result{}, err
 	}
 
 	graph := grid.graph()
-	fmt.Println(graph.walk(), graph.hike())
+
+	return Result{graph.walk(), graph.hike()}, nil
+}
+
+// parse reads the trails map, a square of at least 3 and at most MAXN rows
+func parse(r io.Reader) (*grid, error) {
+	grid := newGrid()
+
+	h := 0
+	input := bufio.NewScanner(r)
+	for j := 0; input.Scan(); j++ {
+		line := input.Bytes()
+		if j == MAXN || len(line) < 3 || len(line) > MAXN ||
+			j > 0 && len(line) != grid.w || len(bytes.Trim(line, "#.<>^v")) > 0 {
+			return nil, fmt.Errorf("bad map row %q", line)
+		}
+		grid.load(line)
+		h++
+	}
+	if h != grid.w {
+		return nil, errors.New("bad map: not a square")
+	}
+	return grid, input.Err()
 }
 
 type grid struct {
@@ -205,7 +237,7 @@ func (g graph) hike() int {
 	const MAXPROCS = 64
 
 	seeds := []seed{{g.s, 0, 1 << g.s}}
-	for len(seeds) < MAXPROCS {
+	for len(seeds) > 0 && len(seeds) < MAXPROCS { // small graphs run out of seeds
 		var x seed
 
 		x, seeds = seeds[0], seeds[1:]
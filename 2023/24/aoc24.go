@@ -3,15 +3,35 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"math/big"
 	"os"
+	"strconv"
 	"strings"
 )
 
 const MAXN = 300
 
 func main() {
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2)
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(rd io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	stones, err := parse(rd)
+	if err != nil {
+		return Result{}, err
+	}
 
+	return Result{intersect(stones), collide(stones)}, nil
+}
+
+// parse reads the hailstones positions and velocities
+func parse(rd io.Reader) ([]stone, error) {
 	r := strings.NewReplacer(
 		",", "",
 		"@", "",
@@ -19,16 +39,24 @@ func main() {
 
 	stones := make([]stone, 0, MAXN)
 
-	input := bufio.NewScanner(os.Stdin)
+	input := bufio.NewScanner(rd)
 	for j := 0; input.Scan(); j++ {
 		var x stone
-		for i, s := range fields(r.Replace(input.Text())) {
-			x[i] = atoi(s)
+
+		args := fields(r.Replace(input.Text()))
+		if len(args) != len(x) {
+			return nil, fmt.Errorf("bad hailstone %q", input.Text())
+		}
+		for i, s := range args {
+			n, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("bad hailstone %q", input.Text())
+			}
+			x[i] = n
 		}
 		stones = append(stones, x)
 	}
-
-	fmt.Println(intersect(stones), collide(stones))
+	return stones, input.Err()
 }
 
 type stone [6]int64
@@ -188,17 +216,3 @@ func div(a, b *big.Int) *big.Int {
 }
 
 var fields = strings.Fields
-
-// strconv.Atoi simplified core loop
-// s is ^-?\d+$
-func atoi(s string) (n int64) {
-	neg := int64(1)
-	if s[0] == '-' {
-		neg, s = -1, s[1:]
-	}
-
-	for i := range s {
-		n = 10*n + int64(s[i]-'0')
-	}
-	return neg * n
-}
@@ -2,20 +2,36 @@ package main
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 )
 
 func main() {
-	g := newGraph(bufio.NewScanner(os.Stdin))
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1)
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	A, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
+
+	g := newGraph(A)
 
 	s := g.furthest(0)
 	e := g.furthest(s)
 
 	size := g.flow(s, e)
 
-	fmt.Println(size * (g.len() - size))
+	return Result{size * (g.len() - size), nil}, nil
 }
 
 type graph struct {
@@ -25,7 +41,9 @@ type graph struct {
 
 type adjmat [][]int
 
-func newGraph(input *bufio.Scanner) *graph {
+// parse reads the wiring diagram into an adjacency matrix, components are
+// named ^[a..z]{3}$
+func parse(r io.Reader) (adjmat, error) {
 
 	// perfect hashtable for ^[a..z]{3}$
 	table := make([]int, 26*26*26)
@@ -40,7 +58,11 @@ func newGraph(input *bufio.Scanner) *graph {
 	}
 	A = A[:0:len(A)] // cover and protect rows
 
-	hash := func(s string) int {
+	hash := func(s string) (int, error) {
+		if len(s) != 3 || strings.Trim(s, "abcdefghijklmnopqrstuvwxyz") != "" {
+			return 0, fmt.Errorf("bad component %q", s)
+		}
+
 		h := 0
 		for i := range s {
 			h = 26*h + int(s[i]-'a')
@@ -48,23 +70,43 @@ func newGraph(input *bufio.Scanner) *graph {
 
 		i := table[h]
 		if i == MaxInt {
+			if len(A) == cap(A) {
+				return 0, fmt.Errorf("too many components at %q", s)
+			}
 			i = len(A)
 			table[h], A = i, A[:i+1] // uncover new row
 		}
 
-		return i
+		return i, nil
 	}
 
+	input := bufio.NewScanner(r)
 	for input.Scan() {
 		args := split(input.Text(), ": ")
-		src := hash(args[0])
+		if len(args) != 2 {
+			return nil, fmt.Errorf("bad wiring %q", input.Text())
+		}
+		src, err := hash(args[0])
+		if err != nil {
+			return nil, err
+		}
 
 		for _, s := range fields(args[1]) {
-			dst := hash(s)
+			dst, err := hash(s)
+			if err != nil {
+				return nil, err
+			}
 			A[src] = append(A[src], dst)
 			A[dst] = append(A[dst], src)
 		}
 	}
+	if len(A) == 0 {
+		return nil, errors.New("bad diagram: no component")
+	}
+	return A, input.Err()
+}
+
+func newGraph(A adjmat) *graph {
 
 	edges := make([]int, 0, 6416) // from previous run
 	nodes := make([][2]int, 0, len(A))
@@ -202,12 +244,3 @@ func hash(table *[]int, A *adjmat, s string) int {
 var split, fields = strings.Split, strings.Fields
 
 const MaxInt = int(^uint(0) >> 1)
-
-// strconv.Atoi simplified core loop
-// s is ^\d+$
-func atoi(s string) (n int) {
-	for i := range s {
-		n = 10*n + int(s[i]-'0')
-	}
-	return
-}
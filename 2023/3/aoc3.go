@@ -12,22 +12,63 @@ package main
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
+	"io"
 	"math/bits"
 	"os"
 	"strings"
+
+	"github.com/erik-adelbert/aoc/lib/num"
 )
 
 const MAXN = 142
 
 func main() {
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2)
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	rows, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
+
 	engine := newSchema()
+	for j, row := range rows {
+		engine.setrow(j+1, row)
+	}
 
-	input := bufio.NewScanner(os.Stdin)
-	for j := 1; input.Scan(); j++ {
-		engine.setrow(j, input.Bytes())
+	p1, p2 := engine.inventory()
+
+	return Result{p1, p2}, nil
+}
+
+// parse reads the engine schematic, a rectangle of printable characters
+// fitting in MAXN-2 rows and columns
+func parse(r io.Reader) ([][]byte, error) {
+	rows := make([][]byte, 0, MAXN)
+
+	input := bufio.NewScanner(r)
+	for input.Scan() {
+		row := input.Bytes()
+		if len(rows) == MAXN-2 || len(row) == 0 || len(row) > MAXN-2 ||
+			len(rows) > 0 && len(row) != len(rows[0]) {
+			return nil, fmt.Errorf("bad schematic row %q", row)
+		}
+		for _, c := range row {
+			if c <= ' ' || c > '~' {
+				return nil, fmt.Errorf("bad schematic row %q", row)
+			}
+		}
+		rows = append(rows, bytes.Clone(row))
 	}
-	fmt.Println(engine.inventory())
+	return rows, input.Err()
 }
 
 type gear struct {
@@ -103,7 +144,7 @@ func (sc *schema) inventory() (sum, ratio int) {
 		gears := nums[j].and(cogs[j])
 
 		getpart := func() {
-			n := atoi(buf)
+			n := num.Atoi(buf)
 
 			sum += n     // part1
 			part = false // unset flag
@@ -359,12 +400,3 @@ func (u uint192) String() string {
 	fmt.Fprintf(&sb, "%016x%016x%016x", u.w2, u.w1, u.w0)
 	return sb.String()
 }
-
-// strconv.Atoi simplified core loop
-// s is ^\d+$
-func atoi(s []byte) (n int) {
-	for i := range s {
-		n = 10*n + int(s[i]-'0')
-	}
-	return
-}
@@ -14,12 +14,27 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
+	"strconv"
 	"strings"
 )
 
 func main() {
-	const MAXMATCH = 16
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2) // parts 1 & 2
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	matches, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
+
 	score, ncard := 0, 0 // part 1 & 2 results
 
 	deck := make([]int, MAXMATCH) // ring buffer, deck[i%MAXMATCH] stores card#(i+1) count
@@ -27,45 +42,81 @@ func main() {
 		return i & (MAXMATCH - 1)
 	}
 
-	input := bufio.NewScanner(os.Stdin)
-	for i := 0; input.Scan(); i++ {
+	for i, nmatch := range matches {
+		// compute part1
+		// 2^(nmatch-1) | 0 if nmatch == 0
+		score += 1 << nmatch >> 1
+
+		// update deck and fwd duplicate cards
+		deck[θ(i)] += 1
+		for ii := i + 1; ii < (i+1)+nmatch; ii++ {
+			deck[θ(ii)] += deck[θ(i)]
+		}
+
+		// compute part2
+		ncard += deck[θ(i)]
+		deck[θ(i)] = 0 // consume deck
+	}
+
+	return Result{score, ncard}, nil
+}
+
+// MAXMATCH bounds the matching numbers count of a card
+const MAXMATCH = 16
+
+// parse reads the cards and counts their matching numbers, all numbers are
+// less than uint128size
+func parse(r io.Reader) ([]int, error) {
+	matches := make([]int, 0, 256)
+
+	input := bufio.NewScanner(r)
+	for input.Scan() {
 
 		input := input.Text()
-		// input is: ^Game\s(\s|\d)\d:\s((\s|\d)\d+\s)+|\s((\s|\d)\d\s)+(\s|\d)\d$
+		// input is: ^Card\s(\s|\d)\d:\s((\s|\d)\d+\s)+|\s((\s|\d)\d\s)+(\s|\d)\d$
 
-		// ditch '^Game \d+:\s' prefix, split winning and cards numbers
+		// ditch '^Card \d+:\s' prefix, split winning and cards numbers
 		raw := split(input[index(input, ":")+1:], " | ")
+		if !strings.HasPrefix(input, "Card ") || len(raw) != 2 {
+			return nil, fmt.Errorf("bad card %q", input)
+		}
 		w, card := fields(raw[0]), fields(raw[1])
 
+		num := func(s string) (int, error) {
+			n, err := strconv.Atoi(s)
+			if err != nil || n < 0 || n >= uint128size {
+				return 0, fmt.Errorf("bad card %q", input)
+			}
+			return n, nil
+		}
+
 		// map winning numbers into a set
 		wins := nullset // fast adhoc int set
 		for i := range w {
-			wins.set(atoi(w[i]))
+			n, err := num(w[i])
+			if err != nil {
+				return nil, err
+			}
+			wins.set(n)
 		}
 
 		// match card numbers against winning ones
 		nmatch := 0
 		for i := range card {
-			if wins.get(atoi(card[i])) > 0 {
+			n, err := num(card[i])
+			if err != nil {
+				return nil, err
+			}
+			if wins.get(n) > 0 {
 				nmatch++
 			}
 		}
-
-		// compute part1
-		// 2^(nmatch-1) | 0 if nmatch == 0
-		score += 1 << nmatch >> 1
-
-		// update deck and fwd duplicate cards
-		deck[θ(i)] += 1
-		for ii := i + 1; ii < (i+1)+nmatch; ii++ {
-			deck[θ(ii)] += deck[θ(i)]
+		if nmatch >= MAXMATCH {
+			return nil, fmt.Errorf("bad card %q: too many matches", input)
 		}
-
-		// compute part2
-		ncard += deck[θ(i)]
-		deck[θ(i)] = 0 // consume deck
+		matches = append(matches, nmatch)
 	}
-	fmt.Println(score, ncard) // parts 1 & 2
+	return matches, input.Err()
 }
 
 // package strings wrappers/sugars
@@ -119,12 +170,3 @@ sh64:
 
 	return u
 }
-
-// strconv.Atoi simplified core loop
-// s is ^\d+$
-func atoi(s string) (n int) {
-	for i := range s {
-		n = 10*n + int(s[i]-'0')
-	}
-	return
-}
@@ -13,40 +13,62 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"strings"
-)
 
-var (
-	seeds1, seeds2 spans // part 1 & 2
+	"github.com/erik-adelbert/aoc/lib/num"
 )
 
 var world [7]spans
 
-func init() {
-	seeds1 = mkSpans() // part1
-	seeds2 = mkSpans() // part2
+func main() {
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2) // parts 1&2
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
 
-	for i := range world {
-		world[i] = mkSpans()
+	var seeds1, seeds2 spans // part 1 & 2
+	if seeds1, seeds2, world, err = parse(r); err != nil {
+		return Result{}, err
 	}
+
+	return Result{locate(seeds1), locate(seeds2)}, nil
 }
 
-func main() {
+// parse reads the seeds and the seven almanac maps, seeds come as single
+// values for part 1 and as ranges for part 2
+func parse(r io.Reader) (seeds1, seeds2 spans, maps [7]spans, err error) {
+	seeds1 = mkSpans() // part1
+	seeds2 = mkSpans() // part2
+	for i := range maps {
+		maps[i] = mkSpans()
+	}
+
 	state := SEED
-	input := bufio.NewScanner(os.Stdin)
+	input := bufio.NewScanner(r)
 	for input.Scan() {
 		input := input.Text()
 		switch {
 		case len(input) == 0:
 			state++
 		case state == SEED:
+			if !strings.HasPrefix(input, "seeds:") {
+				return nil, nil, maps, fmt.Errorf("bad seeds %q", input)
+			}
 			fields := fields(input)[1:]
 			for i := range fields {
+				if !isnum(fields[i]) {
+					return nil, nil, maps, fmt.Errorf("bad seeds %q", input)
+				}
 
 				// build part1 seeds by making right open intervals of 0 length
 				// see https://tinyurl.com/msaewr9b (wikipedia)
-				n := atoi(fields[i])
+				n := num.Atoi(fields[i])
 				seeds1 = append(seeds1,
 					span{n, n + 1, 0}, // right open, len == 0
 				)
@@ -62,20 +84,25 @@ func main() {
 					)
 				}
 			}
+		case state > LOC:
+			return nil, nil, maps, fmt.Errorf("bad almanac: extra map %q", input)
 		case contains(input, ":"): // discard header
 		default:
 			fields := fields(input)
-			world[state] = append(world[state],
+			if len(fields) != 3 || !isnum(fields[0]) || !isnum(fields[1]) || !isnum(fields[2]) {
+				return nil, nil, maps, fmt.Errorf("bad map entry %q", input)
+			}
+			maps[state] = append(maps[state],
 				span{
-					atoi(fields[1]),
-					atoi(fields[1]) + atoi(fields[2]),
-					atoi(fields[0]),
+					num.Atoi(fields[1]),
+					num.Atoi(fields[1]) + num.Atoi(fields[2]),
+					num.Atoi(fields[0]),
 				},
 			)
 		}
 	}
 
-	fmt.Println(locate(seeds1), locate(seeds2)) // parts 1&2
+	return seeds1, seeds2, maps, input.Err()
 }
 
 func locate(seeds spans) (minloc int) {
@@ -174,15 +201,10 @@ const MaxInt = int(^uint(0) >> 1)
 // Go strings package wrappers/sugar
 var contains, fields = strings.Contains, strings.Fields
 
-func isodd(n int) bool {
-	return n&1 > 0
+func isnum(s string) bool {
+	return len(s) > 0 && len(s) < 16 && strings.Trim(s, "0123456789") == ""
 }
 
-// strconv.Atoi simplified core loop
-// s is ^\d+$
-func atoi(s string) (n int) {
-	for i := range s {
-		n = 10*n + int(s[i]-'0')
-	}
-	return
+func isodd(n int) bool {
+	return n&1 > 0
 }
@@ -0,0 +1,14 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func FuzzParse(f *testing.F) {
+	seedExamples(f)
+	fuzzParse(f, func(data []byte) (spans, error) {
+		seeds, _, _, err := parse(bytes.NewReader(data))
+		return seeds, err
+	})
+}
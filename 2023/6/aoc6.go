@@ -12,17 +12,30 @@ package main
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
+
+	"github.com/erik-adelbert/aoc/lib/num"
 )
 
 func main() {
-	input := bufio.NewScanner(os.Stdin)
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2)
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
 
 	// parse multiple (part1) and single (part2) race data
-	times, T := parse(input) // first line
-	dists, D := parse(input) // second line
+	times, dists, T, D, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
 
 	// solve V.x = d with V = (t - x) <=> (x - t) * x - d = 0
 	// this quadratic formula leads to
@@ -51,39 +64,57 @@ func main() {
 		Π *= solve(times[i], dists[i])
 	}
 
-	fmt.Println(Π, solve(T, D))
+	return Result{Π, solve(T, D)}, nil
+}
+
+// parse reads the race times and distances, both as many races (part1) and
+// as a single one (part2)
+func parse(r io.Reader) (times, dists []int, T, D int, err error) {
+	input := bufio.NewScanner(r)
+
+	if times, T, err = record(input, "Time:"); err != nil { // first line
+		return
+	}
+	if dists, D, err = record(input, "Distance:"); err != nil { // second line
+		return
+	}
+	if len(times) != len(dists) {
+		err = errors.New("bad record: races mismatch")
+	}
+	return
 }
 
-func parse(input *bufio.Scanner) ([]int, int) {
+// record reads a header prefixed line of numbers
+func record(input *bufio.Scanner, header string) ([]int, int, error) {
 
 	input.Scan() // advance input reading
 
 	// ditch header, split fields
 	line := input.Text()
+	if !strings.HasPrefix(line, header) {
+		return nil, 0, fmt.Errorf("bad record %q", line)
+	}
 	fields := fields(line[index(line, ":")+1:])
 
 	a := make([]int, 0, len(fields)) // part1
 	var A strings.Builder            // part2
 	for _, s := range fields {
-		a = append(a, atoi(s)) // convert/collect for part1
-		A.WriteString(s)       // concatenate for part2
+		if strings.Trim(s, "0123456789") != "" {
+			return nil, 0, fmt.Errorf("bad record %q", line)
+		}
+		a = append(a, num.Atoi(s)) // convert/collect for part1
+		A.WriteString(s)           // concatenate for part2
+	}
+	if A.Len() == 0 || A.Len() > 18 {
+		return nil, 0, fmt.Errorf("bad record %q", line)
 	}
 
-	return a, atoi(A.String())
+	return a, num.Atoi(A.String()), input.Err()
 }
 
 // Go package strings wrapper/sugar
 var index, fields = strings.Index, strings.Fields
 
-// strconv.Atoi simplified core loop
-// s is ^\d+$
-func atoi(s string) (n int) {
-	for i := range s {
-		n = 10*n + int(s[i]-'0')
-	}
-	return
-}
-
 // isqrt
 var tab64 = [64]uint64{
 	63, 0, 58, 1, 59, 47, 53, 2,
@@ -0,0 +1,14 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func FuzzParse(f *testing.F) {
+	seedExamples(f)
+	fuzzParse(f, func(data []byte) ([]int, error) {
+		times, _, _, _, err := parse(bytes.NewReader(data))
+		return times, err
+	})
+}
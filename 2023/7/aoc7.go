@@ -3,27 +3,33 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"slices"
+	"strconv"
 	"strings"
 )
 
 func main() {
-	const (
-		Hand = iota
-		Bid
-	)
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2)
+	}))
+}
 
-	games1 := make([]game, 0, 1024)
-	games2 := make([]game, 0, 1024)
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
 
-	input := bufio.NewScanner(os.Stdin)
-	for input.Scan() {
-		input := fields(input.Text())
-		h, b := input[Hand], atoi(input[Bid])
+	deals, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
 
-		games1 = append(games1, game{hand: mkHand(h, Jack), bid: b})
-		games2 = append(games2, game{hand: mkHand(h, Wild), bid: b})
+	games1 := make([]game, 0, len(deals))
+	games2 := make([]game, 0, len(deals))
+	for _, d := range deals {
+		games1 = append(games1, game{hand: mkHand(d.cards, Jack), bid: d.bid})
+		games2 = append(games2, game{hand: mkHand(d.cards, Wild), bid: d.bid})
 	}
 
 	slices.SortFunc(games1, cmp)
@@ -34,7 +40,38 @@ func main() {
 		sum1 += (i + 1) * games1[i].bid
 		sum2 += (i + 1) * games2[i].bid
 	}
-	fmt.Println(sum1, sum2)
+
+	return Result{sum1, sum2}, nil
+}
+
+type deal struct {
+	cards string
+	bid   int
+}
+
+// parse reads the hands of five cards and their bids
+func parse(r io.Reader) ([]deal, error) {
+	const (
+		Hand = iota
+		Bid
+	)
+
+	deals := make([]deal, 0, 1024)
+
+	input := bufio.NewScanner(r)
+	for input.Scan() {
+		args := fields(input.Text())
+		if len(args) != 2 || len(args[Hand]) != 5 ||
+			strings.Trim(args[Hand], "23456789TJQKA") != "" {
+			return nil, fmt.Errorf("bad hand %q", input.Text())
+		}
+		b, err := strconv.Atoi(args[Bid])
+		if err != nil {
+			return nil, fmt.Errorf("bad bid %q", input.Text())
+		}
+		deals = append(deals, deal{args[Hand], b})
+	}
+	return deals, input.Err()
 }
 
 type game struct {
@@ -186,12 +223,3 @@ func ctoi(c byte, mode bool) int {
 }
 
 var fields = strings.Fields
-
-// strconv.Atoi simplified core loop
-// s is ^\d+$
-func atoi(s string) (n int) {
-	for i := range s {
-		n = 10*n + int(s[i]-'0')
-	}
-	return
-}
@@ -3,6 +3,7 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"math/bits"
 	"os"
 	"strings"
@@ -15,18 +16,21 @@ var (
 
 func init() {
 	AAA, ZZZ = hash("AAA"), hash("ZZZ")
-	links = make([]node, ZZZ+1)
 }
 
 func main() {
-	var input *bufio.Scanner
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2)
+	}))
+}
 
-	var cmds string // ring buffer
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
 
-	getcmds := func() {
-		input.Scan()        // advance scanner
-		cmds = input.Text() // first line
-		input.Scan()        // consume empty line
+	cmds, roots, err := parse(r) // cmds is a ring buffer
+	if err != nil {
+		return Result{}, err
 	}
 
 	cmdstepper := func() (func() byte, func() int) {
@@ -45,18 +49,6 @@ func main() {
 		return cmd, step
 	}
 
-	roots := make([]int, 0, 8)
-
-	addnode := func() {
-		input := input.Text()
-
-		h, left, right := hash(input[0:3]), hash(input[7:10]), hash(input[12:15])
-		if isroot(h) {
-			roots = append(roots, h)
-		}
-		links[h] = mknode(left, right)
-	}
-
 	browse := func(start int, end func(int) bool) int {
 		cmd, step := cmdstepper()
 
@@ -71,13 +63,6 @@ func main() {
 		return step() - 1
 	}
 
-	// read input
-	input = bufio.NewScanner(os.Stdin)
-	getcmds()
-	for input.Scan() {
-		addnode()
-	}
-
 	// part1
 	browseAAA := func() int {
 		return browse(AAA, func(node int) bool {
@@ -95,7 +80,43 @@ func main() {
 		return lcm(cycles)
 	}
 
-	fmt.Println(browseAAA(), browseAll())
+	return Result{browseAAA(), browseAll()}, nil
+}
+
+// parse reads the L/R instructions and the network links, it returns the
+// part 2 start nodes
+func parse(r io.Reader) (string, []int, error) {
+	links = make([]node, ZZZ+1)
+
+	isname := func(s string) bool {
+		return strings.Trim(s, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") == ""
+	}
+
+	input := bufio.NewScanner(r)
+	input.Scan()         // advance scanner
+	cmds := input.Text() // first line
+	if cmds == "" || strings.Trim(cmds, "LR") != "" {
+		return "", nil, fmt.Errorf("bad instructions %q", cmds)
+	}
+	input.Scan() // consume empty line
+
+	roots := make([]int, 0, 8)
+	for input.Scan() {
+		input := input.Text()
+
+		// input is ^AAA = (BBB, CCC)$
+		if len(input) != 16 || input[3:7] != " = (" || input[10:12] != ", " || input[15] != ')' ||
+			!isname(input[0:3]) || !isname(input[7:10]) || !isname(input[12:15]) {
+			return "", nil, fmt.Errorf("bad node %q", input)
+		}
+
+		h, left, right := hash(input[0:3]), hash(input[7:10]), hash(input[12:15])
+		if isroot(h) {
+			roots = append(roots, h)
+		}
+		links[h] = mknode(left, right)
+	}
+	return cmds, roots, input.Err()
 }
 
 type node int
@@ -172,15 +193,6 @@ func gcd(a, b int) int {
 	return int(u << log2)
 }
 
-// strconv.Atoi simplified core loop
-// s is ^\d+$
-func atoi(s string) (n int) {
-	for i := range s {
-		n = 10*n + int(s[i]-'0')
-	}
-	return
-}
-
 // debug print formatter
 // ex:
 //
@@ -3,30 +3,62 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
+	"strconv"
 	"strings"
 )
 
 const NELEM = 21
 
 func main() {
-	sumL, sumR := 0, 0
-	history := make([]int, NELEM)
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2)
+	}))
+}
 
-	input := bufio.NewScanner(os.Stdin)
-	for input.Scan() {
-		fields := fields(input.Text())
-		for i := range fields {
-			history[i] = atoi(fields[i])
-		}
-		history = history[:len(fields)]
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	histories, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
+
+	sumL, sumR := 0, 0
+	for _, history := range histories {
 		L, R := next(history)
 
 		sumL += L // part2
 		sumR += R // part1
 	}
 
-	fmt.Println(sumR, sumL)
+	return Result{sumR, sumL}, nil
+}
+
+// parse reads the value histories, each holds 1 to NELEM values
+func parse(r io.Reader) ([][]int, error) {
+	histories := make([][]int, 0, 256)
+
+	input := bufio.NewScanner(r)
+	for input.Scan() {
+		fields := fields(input.Text())
+		if len(fields) == 0 || len(fields) > NELEM {
+			return nil, fmt.Errorf("bad history %q", input.Text())
+		}
+
+		history := make([]int, len(fields))
+		for i := range fields {
+			n, err := strconv.Atoi(fields[i])
+			if err != nil {
+				return nil, fmt.Errorf("bad history %q", input.Text())
+			}
+			history[i] = n
+		}
+		histories = append(histories, history)
+	}
+	return histories, input.Err()
 }
 
 func isZero(a []int) bool {
@@ -53,17 +85,3 @@ func next(a []int) (int, int) {
 }
 
 var fields = strings.Fields
-
-// strconv.Atoi simplified core loop
-// s is ^-?\d+$
-func atoi(s string) (n int) {
-	neg := 1
-	if s[0] == '-' {
-		neg, s = -1, s[1:]
-	}
-
-	for i := range s {
-		n = 10*n + int(s[i]-'0')
-	}
-	return neg * n
-}
@@ -13,19 +13,27 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"slices"
 	"strings"
+
+	"github.com/erik-adelbert/aoc/lib/num"
 )
 
 func main() {
-	input := bufio.NewScanner(os.Stdin)
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2) // part 1 & 2
+	}))
+}
 
-	var left, right []int
-	for input.Scan() {
-		words := strings.Fields(input.Text())
-		left = append(left, atoi(words[0]))
-		right = append(right, atoi(words[1]))
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	left, right, err := parse(r)
+	if err != nil {
+		return Result{}, err
 	}
 
 	// presort
@@ -34,10 +42,26 @@ func main() {
 
 	sum, sim := 0, 0
 	for i := range left {
-		sum += abs(left[i] - right[i])          // part 1
+		sum += num.Abs(left[i] - right[i])      // part 1
 		sim += left[i] * popcnt(right, left[i]) // part 2
 	}
-	fmt.Println(sum, sim) // part 1 & 2
+
+	return Result{sum, sim}, nil
+}
+
+// parse reads the two location ids lists side by side
+func parse(r io.Reader) (left, right []int, err error) {
+	input := bufio.NewScanner(r)
+
+	for input.Scan() {
+		words := strings.Fields(input.Text())
+		if len(words) != 2 || !isnum(words[0]) || !isnum(words[1]) {
+			return nil, nil, fmt.Errorf("bad locations %q", input.Text())
+		}
+		left = append(left, num.Atoi(words[0]))
+		right = append(right, num.Atoi(words[1]))
+	}
+	return left, right, input.Err()
 }
 
 func popcnt(slice []int, n int) (count int) {
@@ -55,18 +79,6 @@ func popcnt(slice []int, n int) (count int) {
 	return
 }
 
-// strconv.Atoi simplified core loop
-// s is ^\d+$
-func atoi(s string) (n int) {
-	for i := range s {
-		n = 10*n + int(s[i]-'0')
-	}
-	return
-}
-
-func abs(n int) int {
-	if n < 0 {
-		return -n
-	}
-	return n
+func isnum(s string) bool {
+	return len(s) < 10 && strings.Trim(s, "0123456789") == ""
 }
@@ -0,0 +1,14 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func FuzzParse(f *testing.F) {
+	seedExamples(f)
+	fuzzParse(f, func(data []byte) ([]int, error) {
+		left, _, err := parse(bytes.NewReader(data))
+		return left, err
+	})
+}
@@ -12,7 +12,10 @@ package main
 
 import (
 	"bufio"
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 )
 
@@ -23,19 +26,48 @@ type Cell struct {
 }
 
 func main() {
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2) // part 1 & 2
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	grid, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
+
+	p1, p2 := solve(grid)
+
+	return Result{p1, p2}, nil
+}
+
+// parse reads the topographic map, a rectangle of heights from 0 to 9 and
+// impassable dots
+func parse(r io.Reader) ([][]int, error) {
 	grid := make([][]int, 0, MAXDIM)
 
-	input := bufio.NewScanner(os.Stdin)
+	input := bufio.NewScanner(r)
 	for input.Scan() {
 		line := input.Bytes()
+		if len(line) == 0 || len(bytes.Trim(line, ".0123456789")) > 0 ||
+			len(grid) > 0 && len(line) != len(grid[0]) {
+			return nil, fmt.Errorf("bad map row %q", line)
+		}
+
 		row := make([]int, 0, len(line))
 		for _, c := range line {
 			row = append(row, btoi(c))
 		}
 		grid = append(grid, row)
 	}
-
-	fmt.Println(solve(grid)) // part 1 & 2
+	if len(grid) == 0 {
+		return nil, errors.New("empty map")
+	}
+	return grid, input.Err()
 }
 
 var neighbors = []Cell{{-1, 0}, {1, 0}, {0, -1}, {0, 1}}
@@ -13,20 +13,28 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"strings"
+
+	"github.com/erik-adelbert/aoc/lib/num"
 )
 
 const MAXN = 3799 // arbitrary but educated guess
 
 func main() {
-	stones := NewCounter(0)
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2)
+	}))
+}
 
-	input := bufio.NewScanner(os.Stdin)
-	for input.Scan() {
-		for _, n := range strings.Fields(input.Text()) {
-			stones[atoi(n)] = 1
-		}
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	stones, err := parse(r)
+	if err != nil {
+		return Result{}, err
 	}
 
 	blink := func(n int) {
@@ -41,7 +49,24 @@ func main() {
 	blink(50)
 	count2 := stones.Popcnt()
 
-	fmt.Println(count1, count2)
+	return Result{count1, count2}, nil
+}
+
+// parse reads the stones engravings, numbers of at most 12 digits for pow10
+// to split them
+func parse(r io.Reader) (Counter, error) {
+	stones := NewCounter(0)
+
+	input := bufio.NewScanner(r)
+	for input.Scan() {
+		for _, n := range strings.Fields(input.Text()) {
+			if len(n) > 12 || strings.Trim(n, "0123456789") != "" {
+				return nil, fmt.Errorf("bad stone %q", n)
+			}
+			stones[num.Atoi(n)] = 1
+		}
+	}
+	return stones, input.Err()
 }
 
 type Counter map[int]int
@@ -98,12 +123,3 @@ func pow10(n int) int {
 		1, 10, 100, 1000, 10000, 100000, 1000000,
 	}[n]
 }
-
-// strconv.Atoi simplified core loop
-// s is ^\d+$
-func atoi(s string) (n int) {
-	for i := range s {
-		n = 10*n + int(s[i]-'0')
-	}
-	return
-}
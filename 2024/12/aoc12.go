@@ -12,17 +12,26 @@ package main
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 )
 
 func main() {
-	grid := make([][]rune, 0, 140)
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2)
+	}))
+}
 
-	input := bufio.NewScanner(os.Stdin)
-	for input.Scan() {
-		line := input.Text()
-		grid = append(grid, []rune(line))
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	grid, err := parse(r)
+	if err != nil {
+		return Result{}, err
 	}
 
 	sum1, sum2 := 0, 0
@@ -32,7 +41,26 @@ func main() {
 		sum2 += region.area * region.nside
 	}
 
-	fmt.Println(sum1, sum2)
+	return Result{sum1, sum2}, nil
+}
+
+// parse reads the garden plots, a rectangle of uppercase letters
+func parse(r io.Reader) ([][]rune, error) {
+	grid := make([][]rune, 0, 140)
+
+	input := bufio.NewScanner(r)
+	for input.Scan() {
+		line := input.Text()
+		if line == "" || strings.Trim(line, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" ||
+			len(grid) > 0 && len(line) != len(grid[0]) {
+			return nil, fmt.Errorf("bad garden row %q", line)
+		}
+		grid = append(grid, []rune(line))
+	}
+	if len(grid) == 0 {
+		return nil, errors.New("empty garden")
+	}
+	return grid, input.Err()
 }
 
 type Region struct {
@@ -12,44 +12,103 @@ package main
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
+
+	"github.com/erik-adelbert/aoc/lib/num"
 )
 
 func main() {
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2) // part 1 & 2
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	systems, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
+
 	var sum1, sum2 int
+	for _, system := range systems {
+		// solve for A, B
+		A, B := solve(system, 0)
+		sum1 += A*3 + B // part 1
 
-	system := make([]int, 0, 4)
+		A, B = solve(system, 10_000_000_000_000)
+		sum2 += A*3 + B // part 2
+	}
+
+	return Result{sum1, sum2}, nil
+}
 
-	input := bufio.NewScanner(os.Stdin)
+// parse reads the claw machines as systems of button A, button B and prize
+// coordinates
+func parse(r io.Reader) ([][]int, error) {
+	var systems [][]int
+
+	system := make([]int, 0, 6)
+
+	input := bufio.NewScanner(r)
 	for input.Scan() {
 		line := input.Text()
 		switch {
-		case line == "":
-			// reset system on new line
-			system = system[:0]
+		case line == "" && len(system) == 0:
+			// skip blank lines between systems
 
-		case line[0] == 'B':
+		case strings.HasPrefix(line, "Button A: ") && len(system) == 0,
+			strings.HasPrefix(line, "Button B: ") && len(system) == 2:
 			// read any button
-			args := strings.Split(line[10:], ", ")
-			system = append(system, atoi(args[0][2:]), atoi(args[1][2:]))
+			x, y, ok := coords(line[10:], "+")
+			if !ok {
+				return nil, fmt.Errorf("bad button %q", line)
+			}
+			system = append(system, x, y)
 
-		case line[0] == 'P':
+		case strings.HasPrefix(line, "Prize: ") && len(system) == 4:
 			// read target X, Y
-			args := strings.Split(line[7:], ", ")
-			system = append(system, atoi(args[0][2:]), atoi(args[1][2:]))
+			x, y, ok := coords(line[7:], "=")
+			if !ok {
+				return nil, fmt.Errorf("bad prize %q", line)
+			}
+			systems = append(systems, append(system, x, y))
+			system = make([]int, 0, 6)
+
+		default:
+			return nil, fmt.Errorf("bad line %q", line)
+		}
+	}
 
-			// solve for A, B
-			A, B := solve(system, 0)
-			sum1 += A*3 + B // part 1
+	if len(system) > 0 {
+		return nil, errors.New("truncated machine")
+	}
+	return systems, input.Err()
+}
 
-			A, B = solve(system, 10_000_000_000_000)
-			sum2 += A*3 + B // part 2
-		}
+// coords reads "X<sep>n, Y<sep>n"
+func coords(s, sep string) (x, y int, ok bool) {
+	xs, ys, ok := strings.Cut(s, ", ")
+	if !ok {
+		return
+	}
+	xs, ok1 := strings.CutPrefix(xs, "X"+sep)
+	ys, ok2 := strings.CutPrefix(ys, "Y"+sep)
+	if !ok1 || !ok2 || !isnum(xs) || !isnum(ys) {
+		return 0, 0, false
 	}
+	return num.Atoi(xs), num.Atoi(ys), true
+}
 
-	fmt.Println(sum1, sum2) // part 1 & 2
+// isnum reports whether s is a number of up to 9 digits
+func isnum(s string) bool {
+	return len(s) > 0 && len(s) <= 9 && strings.Trim(s, "0123456789") == ""
 }
 
 // solve for A, B
@@ -81,12 +140,3 @@ func solve(sys []int, off int) (A, B int) {
 	// all done
 	return
 }
-
-// strconv.Atoi simplified core loop
-// s is ^\d+$
-func atoi(s string) (n int) {
-	for i := range s {
-		n = 10*n + int(s[i]-'0')
-	}
-	return
-}
@@ -0,0 +1 @@
+../../hook/hook_test.go
\ No newline at end of file
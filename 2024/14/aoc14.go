@@ -18,10 +18,13 @@ import (
 	"image/color"
 	"image/draw"
 	"image/png"
+	"io"
 	"log"
 	"math"
 	"os"
 	"strings"
+
+	"github.com/erik-adelbert/aoc/lib/num"
 )
 
 const (
@@ -46,18 +49,40 @@ type Robots struct {
 }
 
 func main() {
-	robots := make([]Robots, 0, MAXDIM) // arbitrary but educated guess
+	var robots []Robots // christmas tree, drawn once solved
 
-	input := bufio.NewScanner(os.Stdin)
-	for input.Scan() {
-		fields := strings.Fields(input.Text())
-		argp := strings.Split(fields[0], ",")
-		argv := strings.Split(fields[1], ",")
-
-		robots = append(robots, Robots{
-			pos: Vec{atoi(argp[0][2:]), atoi(argp[1])},
-			mov: Vec{atoi(argv[0][2:]), atoi(argv[1])},
-		})
+	keep := func(r io.Reader) (Result, error) {
+		t, err := solve(r)
+		robots = t.robots
+		return t.Result, err
+	}
+
+	os.Exit(runDay(keep, func(res Result) {
+		output(res.Part2.(int), H, W, robots)
+		fmt.Println(res.Part1, res.Part2)
+	}))
+}
+
+// tree holds the day answers and the robots drawing the christmas tree
+type tree struct {
+	Result
+
+	robots []Robots
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (Result, error) {
+	t, err := solve(r)
+	return t.Result, err
+}
+
+// solve computes the day answers and keeps the robots for output
+func solve(r io.Reader) (_ tree, err error) {
+	defer guard(&err)
+
+	robots, err := parse(r)
+	if err != nil {
+		return tree{}, err
 	}
 
 	var i, tx0, ty0 int
@@ -78,15 +103,48 @@ func main() {
 
 	time2 := easter(tx0, ty0)
 	robots = move(robots, time2-T0)
-	output(time2, H, W, robots)
 
-	fmt.Println(prod1, time2)
+	return tree{Result{prod1, time2}, robots}, nil
+}
+
+// parse reads the robots as "p=x,y v=dx,dy"
+func parse(r io.Reader) ([]Robots, error) {
+	robots := make([]Robots, 0, MAXDIM) // arbitrary but educated guess
+
+	input := bufio.NewScanner(r)
+	for input.Scan() {
+		line := input.Text()
+		p, v, ok := strings.Cut(line, " ")
+		pos, ok1 := vec(p, "p=")
+		mov, ok2 := vec(v, "v=")
+		if !ok || !ok1 || !ok2 {
+			return nil, fmt.Errorf("bad robot %q", line)
+		}
+		robots = append(robots, Robots{pos, mov})
+	}
+	return robots, input.Err()
+}
+
+// vec reads "<prefix>x,y"
+func vec(s, prefix string) (Vec, bool) {
+	s, ok := strings.CutPrefix(s, prefix)
+	x, y, ok1 := strings.Cut(s, ",")
+	if !ok || !ok1 || !isnum(x) || !isnum(y) {
+		return Vec{}, false
+	}
+	return Vec{num.Atoi(x), num.Atoi(y)}, true
+}
+
+// isnum reports whether s is a signed number of up to 4 digits
+func isnum(s string) bool {
+	s = strings.TrimPrefix(s, "-")
+	return len(s) > 0 && len(s) <= 4 && strings.Trim(s, "0123456789") == ""
 }
 
 func move(robots []Robots, t int) []Robots {
 	for i := range robots {
-		robots[i].pos.x = mod(robots[i].pos.x+t*robots[i].mov.x, W)
-		robots[i].pos.y = mod(robots[i].pos.y+t*robots[i].mov.y, H)
+		robots[i].pos.x = num.Mod(robots[i].pos.x+t*robots[i].mov.x, W)
+		robots[i].pos.y = num.Mod(robots[i].pos.y+t*robots[i].mov.y, H)
 	}
 	return robots
 }
@@ -145,25 +203,6 @@ func output(timestamp int, H, W int, robots []Robots) {
 	fmt.Printf("PNG file written to %s\n", fname)
 }
 
-func mod(a, b int) int {
-	return ((a % b) + b) % b
-}
-
-// strconv.Atoi modified loop
-// s is ^-?\d+.*$
-func atoi(s string) int {
-	neg := 1
-	if s[0] == '-' {
-		neg, s = -1, s[1:]
-	}
-
-	n := 0
-	for i := 0; i < len(s); i++ {
-		n = 10*n + int(s[i]-'0')
-	}
-	return n * neg
-}
-
 func sample(robots []Robots) [][]int {
 	binmat := make([][]int, H)
 	for j := range binmat {
@@ -12,7 +12,9 @@ package main
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 )
@@ -25,42 +27,79 @@ const (
 )
 
 func main() {
-	moves := make([]string, 0, MAXMOVE)
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2) // part 1 & 2
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	mat1, rob1, moves, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
 
-	rob1 := Cell{0, 0}
-	mat1 := make(Grid, 0, MAXDIM)
+	rob2 := Cell{rob1.r, 2 * rob1.c}
+	mat2 := mat1.expand()
+
+	for _, dirs := range moves {
+		for _, dir := range dirs {
+			rob1 = mat1.move(rob1, dir)
+			rob2 = mat2.move(rob2, dir)
+		}
+	}
+	sum1 := mat1.score()
+	sum2 := mat2.score()
 
+	return Result{sum1, sum2}, nil
+}
+
+// parse reads the walled warehouse, the robot removed from it, and the moves
+// of the robot
+func parse(r io.Reader) (mat Grid, rob Cell, moves []string, err error) {
+	moves = make([]string, 0, MAXMOVE)
+	mat = make(Grid, 0, MAXDIM)
+
+	nrob := 0
 	state := MATRIX
-	input := bufio.NewScanner(os.Stdin)
+	input := bufio.NewScanner(r)
 	for input.Scan() {
 		line := input.Text()
 		switch {
 		case len(line) == 0:
 			state = MOVES
 		case state == MATRIX:
-			mat1 = append(mat1, []rune(line))
+			if len(mat) > 0 && len(line) != len(mat[0]) ||
+				strings.Trim(line, "#.O@") != "" || !isWall(line[:1]+line[len(line)-1:]) {
+				return nil, rob, nil, fmt.Errorf("bad warehouse row %q", line)
+			}
+			mat = append(mat, []rune(line))
 			if i := strings.Index(line, "@"); i != -1 {
-				rob1.r, rob1.c = len(mat1)-1, i
-				mat1[rob1.r][rob1.c] = '.'
+				rob.r, rob.c = len(mat)-1, i
+				mat[rob.r][rob.c] = '.'
+				nrob += strings.Count(line, "@")
 			}
 		case state == MOVES:
+			if strings.Trim(line, "<>^v") != "" {
+				return nil, rob, nil, fmt.Errorf("bad moves %q", line)
+			}
 			moves = append(moves, line)
 		}
 	}
 
-	rob2 := Cell{rob1.r, 2 * rob1.c}
-	mat2 := mat1.expand()
-
-	for _, dirs := range moves {
-		for _, dir := range dirs {
-			rob1 = mat1.move(rob1, dir)
-			rob2 = mat2.move(rob2, dir)
-		}
+	switch {
+	case len(mat) < 3 || !isWall(string(mat[0])) || !isWall(string(mat[len(mat)-1])):
+		return nil, rob, nil, errors.New("bad warehouse walls")
+	case nrob != 1:
+		return nil, rob, nil, fmt.Errorf("found %d robots, want 1", nrob)
 	}
-	sum1 := mat1.score()
-	sum2 := mat2.score()
+	return mat, rob, moves, input.Err()
+}
 
-	fmt.Println(sum1, sum2) // part 1 & 2
+func isWall(s string) bool {
+	return strings.Trim(s, "#") == ""
 }
 
 type Grid [][]rune
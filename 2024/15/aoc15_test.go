@@ -0,0 +1,14 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func FuzzParse(f *testing.F) {
+	seedExamples(f)
+	fuzzParse(f, func(data []byte) (Grid, error) {
+		mat, _, _, err := parse(bytes.NewReader(data))
+		return mat, err
+	})
+}
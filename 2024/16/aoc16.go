@@ -14,6 +14,7 @@ import (
 	"bufio"
 	"container/heap"
 	"fmt"
+	"io"
 	"os"
 	"slices"
 	"strings"
@@ -40,27 +41,76 @@ type Maze struct {
 }
 
 func main() {
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2) // part 1 & 2
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	maze, err := parseMaze(r)
+	if err != nil {
+		return Result{}, err
+	}
+	score, tiles := maze.solve()
+
+	return Result{score, len(tiles)}, nil
+}
+
+// parseMaze reads a walled rectangular maze holding a single S and E
+func parseMaze(r io.Reader) (*Maze, error) {
 	var start, goal Cell
+	var nstart, ngoal int
 
 	data := make([][]byte, 0, MAXDIM)
 
-	input := bufio.NewScanner(os.Stdin)
+	input := bufio.NewScanner(r)
 	for input.Scan() {
 		line := input.Text()
 
+		switch {
+		case len(data) == MAXDIM:
+			return nil, fmt.Errorf("more than %d rows", MAXDIM)
+		case len(line) > MAXDIM:
+			return nil, fmt.Errorf("line %d: more than %d columns", len(data)+1, MAXDIM)
+		case len(data) > 0 && len(line) != len(data[0]):
+			return nil, fmt.Errorf("line %d: %d columns, expected %d", len(data)+1, len(line), len(data[0]))
+		}
+
+		if i := strings.IndexFunc(line, func(r rune) bool { return !strings.ContainsRune("#.SE", r) }); i >= 0 {
+			return nil, fmt.Errorf("line %d: unexpected %q", len(data)+1, line[i])
+		}
 		if i := strings.Index(line, "S"); i >= 0 {
 			start = Cell{r: len(data), c: i}
+			nstart += strings.Count(line, "S")
 		}
 		if i := strings.Index(line, "E"); i >= 0 {
 			goal = Cell{r: len(data), c: i}
+			ngoal += strings.Count(line, "E")
 		}
 		data = append(data, []byte(line))
 	}
+	if err := input.Err(); err != nil {
+		return nil, err
+	}
 
-	maze := &Maze{data: data, start: start, goal: goal}
-	score, tiles := maze.solve()
+	if nstart != 1 || ngoal != 1 {
+		return nil, fmt.Errorf("%d starts and %d ends, expected one each", nstart, ngoal)
+	}
 
-	fmt.Println(score, len(tiles)) // part 1 & 2
+	// prune looks around every inner cell without bound checks
+	H, W := len(data), len(data[0])
+	for r, line := range data {
+		for c, x := range line {
+			if (r == 0 || r == H-1 || c == 0 || c == W-1) && x != '#' {
+				return nil, fmt.Errorf("line %d: open border at column %d", r+1, c+1)
+			}
+		}
+	}
+
+	return &Maze{data: data, start: start, goal: goal}, nil
 }
 
 var DIRS = [4]Cell{{r: -1, c: 0}, {r: 0, c: 1}, {r: 1, c: 0}, {r: 0, c: -1}}
@@ -275,10 +325,3 @@ func (h *Heap) Pop() interface{} {
 	*h = old[0 : n-1]
 	return x
 }
-
-func abs(x int) int {
-	if x < 0 {
-		return -x
-	}
-	return x
-}
@@ -0,0 +1,13 @@
+package main
+
+import "testing"
+
+func FuzzParse(f *testing.F) {
+	seedExamples(f)
+	fuzzParse(f, reader(parseMaze))
+}
+
+func FuzzMaze(f *testing.F) {
+	seedExamples(f)
+	fuzzRoundTrip(f, reader(parseMaze))
+}
@@ -12,47 +12,95 @@ package main
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"slices"
+	"strconv"
 	"strings"
 )
 
 const DEBUG = false
 
 func main() {
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2) // part 1 & 2
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	mach, prog, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
 
-	var mach Machine
-	var prog []int
-	var reg byte
-	var val int
+	mach = mach.exec(prog)
+
+	a, ok := mach.quine(prog)
+	if !ok {
+		return Result{}, errors.New("no quine")
+	}
 
-	input := bufio.NewScanner(os.Stdin)
-	for input.Scan() {
+	return Result{mach, a}, nil
+}
+
+// parse reads the registers and the program of the machine
+func parse(r io.Reader) (mach Machine, prog []int, err error) {
+	input := bufio.NewScanner(r)
+	for n := 1; input.Scan(); n++ {
 		line := input.Text()
-		switch {
-		case len(line) == 0:
-		case line[0] == 'R':
-			reg, val = line[9], atoi(line[12:])
-			switch reg {
+		if line == "" {
+			continue
+		}
+
+		key, val, ok := strings.Cut(line, ": ")
+		if !ok {
+			return mach, nil, fmt.Errorf("line %d: expected \"Register X: n\" or \"Program: ops\", got %q", n, line)
+		}
+
+		switch key {
+		case "Register A", "Register B", "Register C":
+			x, err := strconv.Atoi(val)
+			if err != nil {
+				return mach, nil, fmt.Errorf("line %d: %w", n, err)
+			}
+
+			switch key[len(key)-1] {
 			case 'A':
-				mach.ra = val
+				mach.ra = x
 			case 'B':
-				mach.rb = val
+				mach.rb = x
 			case 'C':
-				mach.rc = val
+				mach.rc = x
+			}
+		case "Program":
+			text := strings.Split(val, ",")
+			if len(text)%2 != 0 {
+				return mach, nil, fmt.Errorf("line %d: odd program length %d", n, len(text))
 			}
-		case line[0] == 'P':
-			text := strings.Split(line[9:], ",")
+
 			prog = make([]int, len(text))
 			for i, word := range text {
-				prog[i] = atoi(word)
+				if len(word) != 1 || word[0] < '0' || word[0] > '7' {
+					return mach, nil, fmt.Errorf("line %d: expected a 3-bit number, got %q", n, word)
+				}
+				prog[i] = int(word[0] - '0')
 			}
+		default:
+			return mach, nil, fmt.Errorf("line %d: unknown %q", n, key)
 		}
 	}
-
-	mach = mach.exec(prog)
-	fmt.Println(mach, mach.quine(prog)) // part 1 & 2
+	if err := input.Err(); err != nil {
+		return mach, nil, err
+	}
+	if len(prog) == 0 {
+		return mach, nil, errors.New("no program")
+	}
+	return mach, prog, nil
 }
 
 type Machine struct {
@@ -98,7 +146,7 @@ func (m Machine) exec(text []int) Machine {
 	return m
 }
 
-func (m Machine) quine(text []int) int {
+func (m Machine) quine(text []int) (int, bool) {
 	table := make([]int, 0)
 	for a := 0; a < (1 << 10); a++ {
 		table = append(table, m.init(a, 0, 0).exec(text).out[0])
@@ -138,7 +186,7 @@ func (m Machine) quine(text []int) int {
 	for _, x := range cur {
 		a := pack(x)
 		if slices.Equal(text, m.init(a, 0, 0).exec(text).out) {
-			return a
+			return a, true
 			// here we should retain the min and return it at the end
 			// but somehow my input's solution appears here first
 
@@ -148,7 +196,7 @@ func (m Machine) quine(text []int) int {
 
 	// return slices.Min(quines)
 
-	panic("unreachable")
+	return 0, false
 }
 
 const MaxInf = int(^uint(0) >> 1)
@@ -210,12 +258,3 @@ func debug(format string, args ...interface{}) (n int, err error) {
 	}
 	return 0, nil
 }
-
-// strconv.Atoi simplified core loop
-// s is ^\d+$
-func atoi(s string) (n int) {
-	for i := range s {
-		n = 10*n + int(s[i]-'0')
-	}
-	return
-}
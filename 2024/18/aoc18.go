@@ -12,10 +12,14 @@ package main
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"slices"
 	"strings"
+
+	"github.com/erik-adelbert/aoc/lib/num"
 )
 
 const (
@@ -24,28 +28,57 @@ const (
 )
 
 func main() {
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Printf("%d  %s\n", res.Part1, res.Part2)
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	mem, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
+
+	p1 := mem.shortest(T0)
+	p2 := mem.locate(mem.failfast(T0 + 1)) // we know the closing time is at least T0+1
+
+	return Result{p1, fmt.Sprintf("%d,%d", p2.c, p2.r)}, nil
+}
+
+// parse reads the falling bytes "x,y" into the memory grid of their fall
+// times
+func parse(r io.Reader) (*Grid, error) {
 	rows := make([]int, 0, MAXDIM)
 	cols := make([]int, 0, MAXDIM)
 
 	t := 0
-	input := bufio.NewScanner(os.Stdin)
+	input := bufio.NewScanner(r)
 	for input.Scan() {
-		args := strings.Split(input.Text(), ",")
-		rows = append(rows, atoi(args[1]))
-		cols = append(cols, atoi(args[0]))
+		line := input.Text()
+		x, y, ok := strings.Cut(line, ",")
+		if !ok || !isnum(x) || !isnum(y) || num.Atoi(x) >= MAXDIM || num.Atoi(y) >= MAXDIM {
+			return nil, fmt.Errorf("bad byte %q", line)
+		}
+		rows = append(rows, num.Atoi(y))
+		cols = append(cols, num.Atoi(x))
 		t++
 	}
+	if err := input.Err(); err != nil {
+		return nil, err
+	}
+	if t == 0 {
+		return nil, errors.New("no byte")
+	}
 
 	mem := newGrid(slices.Max(rows)+1, slices.Max(cols)+1, t)
 	for i := range rows {
 		x := Cell{rows[i], cols[i]}
 		mem.set(x, i+1)
 	}
-
-	p1 := mem.shortest(T0)
-	p2 := mem.locate(mem.failfast(T0 + 1)) // we know the closing time is at least T0+1
-
-	fmt.Printf("%d  %d,%d\n", p1, p2.c, p2.r)
+	return mem, nil
 }
 
 func (g *Grid) failfast(t0 int) int {
@@ -193,11 +226,7 @@ func (x Cell) add(y Cell) Cell {
 	return Cell{x.r + y.r, x.c + y.c}
 }
 
-// strconv.Atoi simplified core loop
-// s is ^\d+$
-func atoi(s string) (n int) {
-	for i := range s {
-		n = 10*n + int(s[i]-'0')
-	}
-	return
+// isnum reports whether s is a number of up to 2 digits
+func isnum(s string) bool {
+	return len(s) > 0 && len(s) <= 2 && strings.Trim(s, "0123456789") == ""
 }
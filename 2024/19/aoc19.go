@@ -13,6 +13,7 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 )
@@ -23,11 +24,41 @@ const (
 )
 
 func main() {
-	rules := make([]string, 0, 450) // arbitrary size
-	words := make([]string, 0, 400) // arbitrary size
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2) // part 1 & 2
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	rules, words, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
+
+	trie := build(rules)
+
+	count1, count2 := 0, 0
+	for _, w := range words {
+		if n := match(w, trie); n > 0 {
+			count1 += 1
+			count2 += n
+		}
+	}
+
+	return Result{count1, count2}, nil
+}
+
+// parse reads the towel patterns and the designs, both striped with the
+// colors wubrg
+func parse(r io.Reader) (rules, words []string, err error) {
+	rules = make([]string, 0, 450) // arbitrary size
+	words = make([]string, 0, 400) // arbitrary size
 
 	state := RULES
-	input := bufio.NewScanner(os.Stdin)
+	input := bufio.NewScanner(r)
 	for input.Scan() {
 		line := input.Text()
 		switch {
@@ -35,22 +66,23 @@ func main() {
 			state = WORDS
 		case state == RULES:
 			rules = strings.Split(line, ", ")
+			for _, rule := range rules {
+				if !isstripes(rule) {
+					return nil, nil, fmt.Errorf("bad towels %q", line)
+				}
+			}
 		case state == WORDS:
+			if !isstripes(line) {
+				return nil, nil, fmt.Errorf("bad design %q", line)
+			}
 			words = append(words, line)
 		}
 	}
+	return rules, words, input.Err()
+}
 
-	trie := build(rules)
-
-	count1, count2 := 0, 0
-	for _, w := range words {
-		if n := match(w, trie); n > 0 {
-			count1 += 1
-			count2 += n
-		}
-	}
-
-	fmt.Println(count1, count2) // part 1 & 2
+func isstripes(s string) bool {
+	return len(s) > 0 && strings.Trim(s, "wubrg") == ""
 }
 
 type TrieNode struct {
@@ -13,22 +13,26 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"strings"
+
+	"github.com/erik-adelbert/aoc/lib/num"
 )
 
 func main() {
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2) // part 1 & 2
+	}))
+}
 
-	reports := make([][]int, 0, 1000)
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
 
-	input := bufio.NewScanner(os.Stdin)
-	for input.Scan() {
-		words := strings.Fields(input.Text())
-		report := make([]int, len(words))
-		for i, word := range words {
-			report[i] = atoi(word)
-		}
-		reports = append(reports, report)
+	reports, err := parse(r)
+	if err != nil {
+		return Result{}, err
 	}
 
 	var count1, count2 int // safe reports with no or a single error
@@ -43,7 +47,26 @@ func main() {
 		}
 	}
 
-	fmt.Println(count1, count1+count2) // part 1 & 2
+	return Result{count1, count1 + count2}, nil
+}
+
+// parse reads the reports levels
+func parse(r io.Reader) ([][]int, error) {
+	reports := make([][]int, 0, 1000)
+
+	input := bufio.NewScanner(r)
+	for input.Scan() {
+		words := strings.Fields(input.Text())
+		report := make([]int, len(words))
+		for i, word := range words {
+			if len(word) > 9 || strings.Trim(word, "0123456789") != "" {
+				return nil, fmt.Errorf("bad report %q", input.Text())
+			}
+			report[i] = num.Atoi(word)
+		}
+		reports = append(reports, report)
+	}
+	return reports, input.Err()
 }
 
 func safe(report []int, maxerr int) bool {
@@ -60,7 +83,7 @@ func safe(report []int, maxerr int) bool {
 	for i := 1; i < len(report); i++ {
 		diff := report[i] - report[i-1]
 
-		unsafe := abs(diff) < 1 || abs(diff) > 3 || (increasing && diff <= 0) || (!increasing && diff >= 0)
+		unsafe := num.Abs(diff) < 1 || num.Abs(diff) > 3 || (increasing && diff <= 0) || (!increasing && diff >= 0)
 		if unsafe {
 			if maxerr == 0 {
 				return false
@@ -83,19 +106,3 @@ func clone(slice []int) []int {
 func remove(slice []int, i int) []int {
 	return append(slice[:i], slice[i+1:]...)
 }
-
-// strconv.Atoi simplified core loop
-// s is ^\d+$
-func atoi(s string) (n int) {
-	for i := range s {
-		n = 10*n + int(s[i]-'0')
-	}
-	return
-}
-
-func abs(n int) int {
-	if n < 0 {
-		return -n
-	}
-	return n
-}
@@ -12,10 +12,14 @@ package main
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"slices"
 	"strings"
+
+	"github.com/erik-adelbert/aoc/lib/num"
 )
 
 const (
@@ -46,30 +50,115 @@ func (c Cell) String() string {
 }
 
 func main() {
-	var start, goal Cell
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2)
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	data, start, goal, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
+
+	var shorts1, shorts2 []Shortcut
+	maze := newMaze(data, start, goal)
+
+	shorts1 = maze.shortcut(2, 100)
+	shorts2 = maze.shortcut(20, 100)
+
+	return Result{len(shorts1), len(shorts2)}, nil
+}
 
-	data := make([][]byte, 0, MAXDIM)
+// parse reads the racetrack, a single path of '.' walled by '#' from start
+// to end
+func parse(r io.Reader) (data [][]byte, start, goal Cell, err error) {
+	var nstart, ngoal int
 
-	input := bufio.NewScanner(os.Stdin)
+	data = make([][]byte, 0, MAXDIM)
+
+	input := bufio.NewScanner(r)
 	for input.Scan() {
 		line := input.Text()
+		if len(data) == MAXDIM || len(line) > MAXDIM ||
+			len(data) > 0 && len(line) != len(data[0]) || strings.Trim(line, "#.SE") != "" {
+			return nil, start, goal, fmt.Errorf("bad racetrack row %q", line)
+		}
 
 		if i := strings.Index(line, "S"); i >= 0 {
 			start = Cell{r: len(data), c: i}
+			nstart += strings.Count(line, "S")
 		}
 		if i := strings.Index(line, "E"); i >= 0 {
 			goal = Cell{r: len(data), c: i}
+			ngoal += strings.Count(line, "E")
 		}
 		data = append(data, []byte(line))
 	}
+	if err := input.Err(); err != nil {
+		return nil, start, goal, err
+	}
+	if nstart != 1 || ngoal != 1 {
+		return nil, start, goal, fmt.Errorf("%d starts and %d ends, expected one each", nstart, ngoal)
+	}
+	if !issingle(data, start, goal) {
+		return nil, start, goal, errors.New("racetrack is not a single path")
+	}
+	return data, start, goal, nil
+}
 
-	var shorts1, shorts2 []Shortcut
-	maze := newMaze(data, start, goal)
+// issingle reports whether the open cells of data form a single path from
+// start to goal, which mktrack follows without backtracking
+func issingle(data [][]byte, start, goal Cell) bool {
+	H, W := len(data), len(data[0])
+	open := func(r, c int) bool {
+		return r >= 0 && r < H && c >= 0 && c < W && data[r][c] != '#'
+	}
 
-	shorts1 = maze.shortcut(2, 100)
-	shorts2 = maze.shortcut(20, 100)
+	nopen := 0
+	for r := range data {
+		for c := range data[r] {
+			if !open(r, c) {
+				continue
+			}
+			nopen++
+
+			degree := 0
+			for _, δ := range []Cell{{-1, 0}, {1, 0}, {0, -1}, {0, 1}} {
+				if open(r+δ.r, c+δ.c) {
+					degree++
+				}
+			}
+
+			x := Cell{r, c}
+			switch {
+			case x == start || x == goal:
+				if degree != 1 {
+					return false
+				}
+			case degree != 2:
+				return false
+			}
+		}
+	}
 
-	fmt.Println(len(shorts1), len(shorts2))
+	// a path walk rules out extra loops
+	seen, cur, n := map[Cell]bool{}, start, 1
+	for cur != goal {
+		seen[cur] = true
+		for _, δ := range []Cell{{-1, 0}, {1, 0}, {0, -1}, {0, 1}} {
+			nxt := Cell{cur.r + δ.r, cur.c + δ.c}
+			if open(nxt.r, nxt.c) && !seen[nxt] {
+				cur = nxt
+				break
+			}
+		}
+		n++
+	}
+	return n == nopen
 }
 
 func newMaze(data [][]byte, start, goal Cell) *Maze {
@@ -206,14 +295,7 @@ func (m *Maze) mkdist(track Track) []int {
 }
 
 func manh(a, b Cell) int {
-	return abs(a.r-b.r) + abs(a.c-b.c)
-}
-
-func abs(x int) int {
-	if x < 0 {
-		return -x
-	}
-	return x
+	return num.Abs(a.r-b.r) + num.Abs(a.c-b.c)
 }
 
 func (m *Maze) String() string {
@@ -0,0 +1,14 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func FuzzParse(f *testing.F) {
+	seedExamples(f)
+	fuzzParse(f, func(data []byte) ([][]byte, error) {
+		track, _, _, err := parse(bytes.NewReader(data))
+		return track, err
+	})
+}
@@ -14,8 +14,12 @@ import (
 	"bufio"
 	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"slices"
+	"strings"
+
+	"github.com/erik-adelbert/aoc/lib/num"
 )
 
 const (
@@ -25,13 +29,22 @@ const (
 )
 
 func main() {
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2) // part 1 & 2
+	}))
+}
 
-	count1, count2 := 0, 0
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
 
-	input := bufio.NewScanner(os.Stdin)
-	for input.Scan() {
-		code := input.Text()
+	codes, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
 
+	count1, count2 := 0, 0
+	for _, code := range codes {
 		// transtype on the keypad
 		m := Message{code: 1}.keytype()
 
@@ -44,14 +57,28 @@ func main() {
 		}
 
 		recurse(DEPTH1)
-		count1 += atoi(code[:3]) * m.len()
+		count1 += num.Atoi(code[:3]) * m.len()
 
 		recurse(DEPTH2)
-		count2 += atoi(code[:3]) * m.len()
+		count2 += num.Atoi(code[:3]) * m.len()
 	}
 
-	fmt.Println(count1, count2) // part 1 & 2
+	return Result{count1, count2}, nil
+}
+
+// parse reads the door codes, three digits and an 'A'
+func parse(r io.Reader) ([]string, error) {
+	var codes []string
 
+	input := bufio.NewScanner(r)
+	for input.Scan() {
+		code := input.Text()
+		if len(code) != 4 || strings.Trim(code[:3], "0123456789") != "" || code[3] != 'A' {
+			return nil, fmt.Errorf("bad code %q", code)
+		}
+		codes = append(codes, code)
+	}
+	return codes, input.Err()
 }
 
 type Cell struct {
@@ -218,12 +245,3 @@ func (m Message) len() (n int) {
 	}
 	return
 }
-
-// strconv.Atoi simplified core loop
-// s is ^\d+$
-func atoi(s string) (n int) {
-	for i := range s {
-		n = 10*n + int(s[i]-'0')
-	}
-	return
-}
@@ -13,8 +13,12 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"slices"
+	"strings"
+
+	"github.com/erik-adelbert/aoc/lib/num"
 )
 
 const (
@@ -24,18 +28,46 @@ const (
 )
 
 func main() {
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2) // part 1 & 2
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	secrets, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
+
 	seqs := make([]int, MAXDIM)
 
 	sum1 := 0
-	input := bufio.NewScanner(os.Stdin)
-	for input.Scan() {
-		n := atoi(input.Text())
+	for _, n := range secrets {
 		sum1 += rehash(n, NLOOP, seqs)
 	}
 
 	count2 := slices.Max(seqs)
 
-	fmt.Println(sum1, count2) // part 1 & 2
+	return Result{sum1, count2}, nil
+}
+
+// parse reads the initial secret numbers of the buyers, 24-bit wide
+func parse(r io.Reader) ([]int, error) {
+	var secrets []int
+
+	input := bufio.NewScanner(r)
+	for input.Scan() {
+		line := input.Text()
+		if len(line) == 0 || len(line) > 8 || strings.Trim(line, "0123456789") != "" ||
+			num.Atoi(line) > 0xFFFFFF {
+			return nil, fmt.Errorf("bad secret %q", line)
+		}
+		secrets = append(secrets, num.Atoi(line))
+	}
+	return secrets, input.Err()
 }
 
 const MinInt = -1 << 31
@@ -71,12 +103,3 @@ func hash(a int) int {
 	a ^= (a << 11) & 0xFFFFFF
 	return a
 }
-
-// strconv.Atoi simplified core loop
-// s is ^\d+$
-func atoi(s string) (n int) {
-	for i := range s {
-		n = 10*n + int(s[i]-'0')
-	}
-	return
-}
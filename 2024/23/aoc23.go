@@ -13,6 +13,7 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"slices"
 	"strings"
@@ -21,12 +22,37 @@ import (
 type Graph map[string]map[string]bool
 
 func main() {
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2) // part 1 & 2
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	nodes, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
+
+	count1 := nodes.tris()
+	clique2 := nodes.largest()
+
+	return Result{count1, clique2}, nil
+}
+
+// parse reads the network map, links between two-letter computers
+func parse(r io.Reader) (Graph, error) {
 	nodes := make(Graph, 520)
 
-	input := bufio.NewScanner(os.Stdin)
+	input := bufio.NewScanner(r)
 	for input.Scan() {
-		edge := strings.Split(input.Text(), "-")
-		from, to := edge[0], edge[1]
+		line := input.Text()
+		from, to, ok := strings.Cut(line, "-")
+		if !ok || !isname(from) || !isname(to) || from == to {
+			return nil, fmt.Errorf("bad link %q", line)
+		}
 
 		if nodes[from] == nil {
 			nodes[from] = make(map[string]bool, 13)
@@ -38,10 +64,11 @@ func main() {
 		}
 		nodes[to][from] = true
 	}
+	return nodes, input.Err()
+}
 
-	count1 := nodes.tris()
-	clique2 := nodes.largest()
-	fmt.Println(count1, clique2) // part 1 & 2
+func isname(s string) bool {
+	return len(s) == 2 && strings.Trim(s, "abcdefghijklmnopqrstuvwxyz") == ""
 }
 
 // brute force the triangle count
@@ -13,6 +13,7 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"slices"
 	"strings"
@@ -28,62 +29,96 @@ const (
 )
 
 func main() {
-	setup := make(map[string]bool, 2*NBIT)
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2) // part 1 & 2
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	setup, logics, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
+
 	latches := make(map[string]*conn, NLATCH)
 	circuit := make(map[string]LC, NCOMP)
-	logics := make([]Logic, 0, NCOMP)
+
+	for a := range setup {
+		latches[a] = newConn(nil, a, false)
+	}
+
+	for _, l := range logics {
+		a, b, c, op := l.A, l.B, l.C, l.op
+		for _, arg := range []string{a, b, c} {
+			if _, ok := latches[arg]; !ok {
+				latches[arg] = newConn(nil, arg, false)
+			}
+		}
+
+		// connect
+		gate := mkgate(c, op)
+		circuit[c] = &gate
+		latches[a].wire(gate.A)
+		latches[b].wire(gate.B)
+		gate.C.wire(latches[c])
+	}
+
+	// setup & evaluate
+	for reg, value := range setup {
+		latches[reg].set(value, true)
+	}
+
+	z := out('z', latches)
+
+	faults := inspect(logics)
+	slices.Sort(faults)
+
+	return Result{z, strings.Join(faults, ",")}, nil
+}
+
+// parse reads the initial wire values and the gates of the circuit
+func parse(r io.Reader) (setup map[string]bool, logics []Logic, err error) {
+	setup = make(map[string]bool, 2*NBIT)
+	logics = make([]Logic, 0, NCOMP)
 
 	mode := INIT
-	input := bufio.NewScanner(os.Stdin)
-	for input.Scan() {
+	input := bufio.NewScanner(r)
+	for n := 1; input.Scan(); n++ {
 		line := input.Text()
 		switch {
 		case line == "":
 			mode = WIRE
 
 		case mode == INIT:
-			args := strings.Split(line, ": ")
+			a, b, ok := strings.Cut(line, ": ")
+			if !ok || !iswire(a) || (b != "0" && b != "1") {
+				return nil, nil, fmt.Errorf("line %d: expected \"wire: 0|1\", got %q", n, line)
+			}
 
-			a, b := args[0], args[1]
 			setup[a] = b == "1"
-			latches[a] = newConn(nil, a, false)
-
 			trace("Setup %s to %t\n", a, setup[a])
 
 		case mode == WIRE:
 			args := strings.Fields(line)
-
-			a, b, c, op := args[0], args[2], args[4], args[1]
-			for _, arg := range []string{a, b, c} {
-				if _, ok := latches[arg]; !ok {
-					latches[arg] = newConn(nil, arg, false)
-				}
+			if len(args) != 5 || args[3] != "->" || !slices.Contains([]string{"AND", "OR", "XOR"}, args[1]) ||
+				!iswire(args[0]) || !iswire(args[2]) || !iswire(args[4]) {
+				return nil, nil, fmt.Errorf("line %d: expected \"wire AND|OR|XOR wire -> wire\", got %q", n, line)
 			}
 
-			// connect
-			gate := mkgate(c, op)
-			circuit[c] = &gate
-			latches[a].wire(gate.A)
-			latches[b].wire(gate.B)
-			gate.C.wire(latches[c])
-
-			// store
+			a, b, c, op := args[0], args[2], args[4], args[1]
 			logics = append(logics, Logic{op: op, A: a, B: b, C: c})
 			trace("Store %s %s %s -> %s\n", a, op, b, c)
 		}
 	}
+	return setup, logics, input.Err()
+}
 
-	// setup & evaluate
-	for reg, value := range setup {
-		latches[reg].set(value, true)
-	}
-
-	z := out('z', latches)
-
-	faults := inspect(logics)
-	slices.Sort(faults)
-
-	fmt.Println(z, strings.Join(faults, ",")) // part 1 & 2
+// iswire reports whether s names a wire, like x00 or kjc
+func iswire(s string) bool {
+	return len(s) == 3 && strings.Trim(s, "abcdefghijklmnopqrstuvwxyz0123456789") == ""
 }
 
 /* Our 1bit full adder:
@@ -12,41 +12,73 @@ package main
 
 import (
 	"bufio"
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 )
 
 func main() {
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1) // part 1
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	schems, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
+
+	count1 := 0
+	for i, a := range schems[:len(schems)-1] {
+		for _, b := range schems[i+1:] {
+			if a^b == a+b {
+				count1++
+			}
+		}
+	}
+
+	return Result{count1, nil}, nil
+}
+
+// parse reads the lock and key schematics, 7 rows of 5 pins each, as
+// bitmaps
+func parse(r io.Reader) ([]int, error) {
+	const H, W = 7, 5
+
 	schems := make([]int, 0, 500)
 
-	input := bufio.NewScanner(os.Stdin)
-	var hash int
+	input := bufio.NewScanner(r)
+	var hash, h int
 	for input.Scan() {
 		row := input.Bytes()
 		switch {
-		case len(row) == 0:
-			schems, hash = append(schems, hash), 0
-		default:
+		case len(row) == 0 && h == H:
+			schems, hash, h = append(schems, hash), 0, 0
+		case len(row) == W && h < H && len(bytes.Trim(row, ".#")) == 0:
 			for _, c := range row {
 				hash <<= 1
 				if c == '#' {
 					hash |= 1
 				}
 			}
+			h++
+		default:
+			return nil, fmt.Errorf("bad schematic row %q", row)
 		}
 	}
-	schems = append(schems, hash)
-
-	count1 := 0
-	for i, a := range schems[:len(schems)-1] {
-		for _, b := range schems[i+1:] {
-			if a^b == a+b {
-				count1++
-			}
-		}
+	if err := input.Err(); err != nil {
+		return nil, err
 	}
-
-	fmt.Println(count1) // part 1
+	if h != H {
+		return nil, errors.New("truncated schematic")
+	}
+	return append(schems, hash), nil
 }
 
 //         ____  ___            _____      _____    _________ _______________   ________    _____
@@ -12,8 +12,11 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"regexp"
+
+	"github.com/erik-adelbert/aoc/lib/num"
 )
 
 const (
@@ -22,14 +25,20 @@ const (
 )
 
 func main() {
-	data, err := os.ReadFile("input.txt")
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2)
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	matches, err := parse(r)
 	if err != nil {
-		panic(err)
+		return Result{}, err
 	}
 
-	re := regexp.MustCompile(`mul\((\d+),(\d+)\)|do\(\)|don\'t\(\)`)
-	matches := re.FindAllStringSubmatch(string(data), -1)
-
 	sum1, sum2 := 0, 0
 	state := IN
 	for _, match := range matches {
@@ -39,19 +48,24 @@ func main() {
 		case "don't()":
 			state = OUT
 		default:
-			n := atoi(match[1]) * atoi(match[2])
+			n := num.Atoi(match[1]) * num.Atoi(match[2])
 			sum1 += n
 			if state == IN {
 				sum2 += n
 			}
 		}
 	}
-	fmt.Println(sum1, sum2)
+
+	return Result{sum1, sum2}, nil
 }
 
-func atoi(s string) (n int) {
-	for i := range s {
-		n = 10*n + int(s[i]-'0')
+// parse reads the corrupted memory and extracts its instructions
+func parse(r io.Reader) ([][]string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
 	}
-	return
+
+	re := regexp.MustCompile(`mul\((\d+),(\d+)\)|do\(\)|don\'t\(\)`)
+	return re.FindAllStringSubmatch(string(data), -1), nil
 }
@@ -12,18 +12,27 @@ package main
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"sync"
 )
 
 func main() {
-	var matrix RuneMat
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2)
+	}))
+}
 
-	input := bufio.NewScanner(os.Stdin)
-	for input.Scan() {
-		matrix = append(matrix, []rune(input.Text()))
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	matrix, err := parse(r)
+	if err != nil {
+		return Result{}, err
 	}
 
 	var wg sync.WaitGroup
@@ -46,7 +55,26 @@ func main() {
 
 	wg.Wait()
 
-	fmt.Println(count1, count2)
+	return Result{count1, count2}, nil
+}
+
+// parse reads the word search, a rectangle of X, M, A and S
+func parse(r io.Reader) (RuneMat, error) {
+	var matrix RuneMat
+
+	input := bufio.NewScanner(r)
+	for input.Scan() {
+		line := input.Text()
+		if line == "" || strings.Trim(line, "XMAS") != "" ||
+			len(matrix) > 0 && len(line) != len(matrix[0]) {
+			return nil, fmt.Errorf("bad word search row %q", line)
+		}
+		matrix = append(matrix, []rune(line))
+	}
+	if len(matrix) == 0 {
+		return nil, errors.New("empty word search")
+	}
+	return matrix, input.Err()
 }
 
 // "*" (star) is a wildcard character that can match any letter.
@@ -13,9 +13,12 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"slices"
 	"strings"
+
+	"github.com/erik-adelbert/aoc/lib/num"
 )
 
 const (
@@ -24,15 +27,46 @@ const (
 )
 
 func main() {
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2) // part 1 & 2
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	rules, updates, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
+
 	sum1, sum2 := 0, 0
-	rules := [100][]int{}
+	for _, indices := range updates {
+		sum1 += median(indices)
+		if !safe(indices, rules) {
+			sum1 -= median(indices)
+			sum2 += median(sort(indices, rules))
+		}
+	}
+
+	return Result{sum1, sum2}, nil
+}
+
+// parse reads the page ordering rules then the updates, pages are two
+// digits numbers
+func parse(r io.Reader) (rules [100][]int, updates [][]int, err error) {
 	for i := range rules {
 		// preallocate 24 rules per index
 		rules[i] = make([]int, 0, 24)
 	}
 
+	ispage := func(s string) bool {
+		return len(s) == 2 && strings.Trim(s, "0123456789") == ""
+	}
+
 	state := RULE
-	input := bufio.NewScanner(os.Stdin)
+	input := bufio.NewScanner(r)
 	for input.Scan() {
 		line := input.Text()
 		if line == "" {
@@ -43,23 +77,25 @@ func main() {
 		switch state {
 		case RULE:
 			words := strings.Split(line, "|")
-			cur, nxt := atoi(words[0]), atoi(words[1])
+			if len(words) != 2 || !ispage(words[0]) || !ispage(words[1]) {
+				return rules, nil, fmt.Errorf("bad rule %q", line)
+			}
+			cur, nxt := num.Atoi(words[0]), num.Atoi(words[1])
 			rules[cur] = append(rules[cur], nxt)
 		case UPDATE:
 			words := strings.Split(line, ",")
 			indices := make([]int, len(words))
 			for i, w := range words {
-				indices[i] = atoi(w)
-			}
-
-			sum1 += median(indices)
-			if !safe(indices, rules) {
-				sum1 -= median(indices)
-				sum2 += median(sort(indices, rules))
+				if !ispage(w) {
+					return rules, nil, fmt.Errorf("bad update %q", line)
+				}
+				indices[i] = num.Atoi(w)
 			}
+			updates = append(updates, indices)
 		}
 	}
-	fmt.Println(sum1, sum2) // part 1 & 2
+
+	return rules, updates, input.Err()
 }
 
 func safe(indices []int, rules [100][]int) bool {
@@ -87,12 +123,3 @@ func sort(indices []int, rules [100][]int) []int {
 func median(indices []int) int {
 	return indices[len(indices)/2]
 }
-
-// strconv.Atoi simplified core loop
-// s is ^\d+$
-func atoi(s string) (n int) {
-	for i := range s {
-		n = 10*n + int(s[i]-'0')
-	}
-	return
-}
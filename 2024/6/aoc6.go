@@ -12,7 +12,9 @@ package main
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"slices"
 	"strings"
@@ -27,21 +29,18 @@ const (
 )
 
 func main() {
-	var origin Point
-	matrix := make([][]rune, 0, 130)
-
-	j := 0
-	input := bufio.NewScanner(os.Stdin)
-	for input.Scan() {
-		line := input.Text()
-		matrix = append(matrix, []rune(line))
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2)
+	}))
+}
 
-		if i := strings.Index(line, "^"); i != -1 {
-			origin = Point{j, i}
-			matrix[j][i] = '.'
-		}
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
 
-		j++
+	matrix, origin, err := parse(r)
+	if err != nil {
+		return Result{}, err
 	}
 
 	maze := Maze{scan(matrix), origin}
@@ -60,7 +59,37 @@ func main() {
 	// 	maze.del(p)
 	// }
 
-	fmt.Println(count1, count2)
+	return Result{count1, count2}, nil
+}
+
+// parse reads the lab map, a rectangle with a single guard heading north
+func parse(r io.Reader) ([][]rune, Point, error) {
+	var origin Point
+	matrix := make([][]rune, 0, 130)
+
+	nguard := 0
+	j := 0
+	input := bufio.NewScanner(r)
+	for input.Scan() {
+		line := input.Text()
+		if line == "" || strings.Trim(line, ".#^") != "" ||
+			j > 0 && len(line) != len(matrix[0]) {
+			return nil, origin, fmt.Errorf("bad map row %q", line)
+		}
+		matrix = append(matrix, []rune(line))
+
+		if i := strings.Index(line, "^"); i != -1 {
+			origin = Point{j, i}
+			matrix[j][i] = '.'
+			nguard += strings.Count(line, "^")
+		}
+
+		j++
+	}
+	if nguard != 1 {
+		return nil, origin, errors.New("bad map: want a single guard")
+	}
+	return matrix, origin, input.Err()
 }
 
 type Point struct {
@@ -202,13 +231,13 @@ func (m Maze) run() (bool, Path) {
 
 		switch to {
 		case NORTH, SOUTH:
-			fmt.Println("see y:", ymin, ymax, cur.x, to, ymax-ymin)
+			fmt.Fprintln(os.Stderr, "see y:", ymin, ymax, cur.x, to, ymax-ymin)
 			for y := ymin; y < ymax; y++ {
 				i := y*W + cur.x
 				seen[i][to] = stamp
 			}
 		case EAST, WEST:
-			fmt.Println("see x:", xmin, xmax, cur.y, to, xmax-xmin)
+			fmt.Fprintln(os.Stderr, "see x:", xmin, xmax, cur.y, to, xmax-xmin)
 			for x := xmin; x < xmax; x++ {
 				i := cur.y*W + x
 				seen[i][to] = stamp
@@ -245,7 +274,7 @@ func (m Maze) run() (bool, Path) {
 						snxt := seen[nxt.y*W+nxt.x]
 						switch {
 						case 0 < snxt[h2] && snxt[h2] < seen[i][h0]:
-							fmt.Println("from:", h0, "to:", h1, "coo:", i/W, i%W)
+							fmt.Fprintln(os.Stderr, "from:", h0, "to:", h1, "coo:", i/W, i%W)
 							count2++
 						case slices.Max(snxt[:]) == 0:
 							// seen[nxt.y*W+nxt.x][h2] = MaxInt
@@ -257,7 +286,7 @@ func (m Maze) run() (bool, Path) {
 		}
 	}
 	elapsed := time.Since(now)
-	fmt.Println("counts:", count1, count2, count3, elapsed)
+	fmt.Fprintln(os.Stderr, "counts:", count1, count2, count3, elapsed)
 
 	return false, []Point{}
 }
@@ -300,12 +329,3 @@ func (p Path) cells() Path {
 }
 
 const MaxInt = int(^uint(0) >> 1)
-
-// strconv.Atoi simplified core loop
-// s is ^\d+$
-func atoi(s string) (n int) {
-	for i := range s {
-		n = 10*n + int(s[i]-'0')
-	}
-	return
-}
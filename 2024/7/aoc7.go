@@ -13,23 +13,31 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"slices"
 	"strings"
+
+	"github.com/erik-adelbert/aoc/lib/num"
 )
 
 func main() {
-	sum1, sum2 := 0, 0
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2) // part 1 & 2
+	}))
+}
 
-	input := bufio.NewScanner(os.Stdin)
-	for input.Scan() {
-		line := input.Text()
-		words := strings.Fields(strings.Replace(line, ":", " ", 1))
-		nums := make([]int, len(words))
-		for i, word := range words {
-			nums[i] = atoi(word)
-		}
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	equations, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
 
+	sum1, sum2 := 0, 0
+	for _, nums := range equations {
 		switch {
 		case check(nums, false):
 			sum1 += nums[0]
@@ -37,7 +45,37 @@ func main() {
 			sum2 += nums[0]
 		}
 	}
-	fmt.Println(sum1, sum1+sum2) // part 1 & 2
+
+	return Result{sum1, sum1 + sum2}, nil
+}
+
+// parse reads the calibration equations, a test value followed by operands
+// from 1 to 999
+func parse(r io.Reader) ([][]int, error) {
+	var equations [][]int
+
+	input := bufio.NewScanner(r)
+	for input.Scan() {
+		line := input.Text()
+		words := strings.Fields(strings.Replace(line, ":", " ", 1))
+		if len(words) < 2 || !strings.Contains(line, ":") {
+			return nil, fmt.Errorf("bad equation %q", line)
+		}
+
+		nums := make([]int, len(words))
+		for i, word := range words {
+			if len(word) > 18 || i > 0 && len(word) > 3 ||
+				strings.Trim(word, "0123456789") != "" {
+				return nil, fmt.Errorf("bad equation %q", line)
+			}
+			nums[i] = num.Atoi(word)
+			if i > 0 && nums[i] == 0 {
+				return nil, fmt.Errorf("bad equation %q", line)
+			}
+		}
+		equations = append(equations, nums)
+	}
+	return equations, input.Err()
 }
 
 func check(nums []int, hascons bool) bool {
@@ -87,12 +125,3 @@ func pow10(n int) int {
 		1, 10, 100, 1000,
 	}[n]
 }
-
-// strconv.Atoi simplified core loop
-// s is ^\d+$
-func atoi(s string) (n int) {
-	for i := range s {
-		n = 10*n + int(s[i]-'0')
-	}
-	return
-}
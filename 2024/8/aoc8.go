@@ -13,7 +13,9 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 )
 
 const (
@@ -35,12 +37,40 @@ func (c City) inbounds(p Point) bool {
 }
 
 func main() {
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2) // part 1 & 2
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	city, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
+
+	count1 := antinodes(city, 1, 2)
+	count2 := antinodes(city, 0, max(city.H, city.W))
+
+	return Result{count1, count2}, nil
+}
+
+// parse reads the antennas map, a rectangle of dots and alphanumeric
+// frequencies
+func parse(r io.Reader) (City, error) {
+	const freqs = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
 	antennas := make([][]Point, ASCIIMAX)
 
-	input := bufio.NewScanner(os.Stdin)
+	input := bufio.NewScanner(r)
 	h, w := 0, 0
 	for input.Scan() {
 		line := input.Text()
+		if h > 0 && len(line) != w || strings.Trim(line, "."+freqs) != "" {
+			return City{}, fmt.Errorf("bad map row %q", line)
+		}
 		for i, c := range line {
 			if c != '.' {
 				antennas[c] = append(antennas[c], Point{h, i})
@@ -49,11 +79,7 @@ func main() {
 		w = len(line)
 		h++
 	}
-
-	city := City{h, w, antennas}
-	count1 := antinodes(city, 1, 2)
-	count2 := antinodes(city, 0, max(city.H, city.W))
-	fmt.Println(count1, count2) // part 1 & 2
+	return City{h, w, antennas}, input.Err()
 }
 
 func antinodes(city City, dmin, dmax int) int { // min distance, max distance
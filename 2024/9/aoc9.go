@@ -12,7 +12,9 @@ package main
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"slices"
 	"sync"
@@ -21,25 +23,18 @@ import (
 const MAXFILE = 20000
 
 func main() {
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2) // part 1 & 2
+	}))
+}
 
-	fs1 := φInit()
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
 
-	input := bufio.NewScanner(os.Stdin)
-	for input.Scan() {
-		var start int
-
-		line := input.Bytes()
-		for par, c := range line {
-			if n := btoi(c); n > 0 {
-				switch {
-				case par%2 == 0:
-					fs1.Link(File{{start, n}})
-				default:
-					fs1.MarkFree(Block{start, n})
-				}
-				start += n
-			}
-		}
+	fs1, err := parse(r)
+	if err != nil {
+		return Result{}, err
 	}
 
 	fs2 := fs1.Clone()
@@ -55,7 +50,35 @@ func main() {
 
 	wg.Wait()
 
-	fmt.Println(fs1.Checksum(), fs2.Checksum()) // part 1 & 2
+	return Result{fs1.Checksum(), fs2.Checksum()}, nil
+}
+
+// parse reads the disk map, a line of digits alternating files and free
+// space sizes
+func parse(r io.Reader) (*φFS, error) {
+	fs := φInit()
+
+	input := bufio.NewScanner(r)
+	for input.Scan() {
+		var start int
+
+		line := input.Bytes()
+		if len(bytes.Trim(line, "0123456789")) > 0 {
+			return nil, fmt.Errorf("bad disk map %.16q", line)
+		}
+		for par, c := range line {
+			if n := btoi(c); n > 0 {
+				switch {
+				case par%2 == 0:
+					fs.Link(File{{start, n}})
+				default:
+					fs.MarkFree(Block{start, n})
+				}
+				start += n
+			}
+		}
+	}
+	return fs, input.Err()
 }
 
 type Block struct {
@@ -117,7 +140,7 @@ func fsmap(fs *φFS, fun func(int)) {
 	for fid := len(fs.fat) - 1; fid >= 0; fid-- {
 		file := fs.fat[fid]
 
-		if file[0].start < fs.free[0].start {
+		if len(fs.free) == 0 || file[0].start < fs.free[0].start {
 			return // no more free space at the beginning
 		}
 
@@ -202,11 +225,12 @@ func (fs *φFS) Realloc(fid int) {
 	blocks := make([]Block, 0, size)
 	reserved := make([]int, 0, size)
 
-	if len(fs.free) < 10 {
-		fs.Unlink(fid)
-	}
 ALLOC:
 	for i, block := range fs.free {
+		if block.start > file[0].start {
+			// blocks only move to lower addresses
+			break
+		}
 		nalloc += block.size
 
 		blocks = append(blocks, block)
@@ -238,6 +262,11 @@ ALLOC:
 		fs.free = slices.Delete(fs.free, reserved[0], reserved[len(reserved)-1]+1)
 	}
 
+	if nalloc < size {
+		// the file head stays in place
+		blocks = append(blocks, Block{file[0].start, size - nalloc})
+	}
+
 	// link file to new blocks
 	fs.fat[fid] = blocks
 	return
@@ -12,9 +12,13 @@ package main
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"time"
+
+	"github.com/erik-adelbert/aoc/lib/num"
 )
 
 const M = 100
@@ -22,16 +26,25 @@ const M = 100
 func main() {
 	t0 := time.Now()
 
-	var acc1, acc2 int // passwords for parts 1 and 2
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2, time.Since(t0))
+	}))
+}
 
-	// process input lines
-	input := bufio.NewScanner(os.Stdin)
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
 
-	p := 50                            // initial dial position
-	signs := [...]int{'L': -1, 'R': 1} // direction sign map
-	for input.Scan() {
-		buf := input.Bytes()
-		s, n := signs[buf[0]], atoi(buf[1:]) // parse direction and number
+	rots, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
+
+	var acc1, acc2 int // passwords for parts 1 and 2
+
+	p := 50 // initial dial position
+	for _, rot := range rots {
+		s, n := rot.s, rot.n
 
 		// part 2: full wraps ⎣n/M⎦
 		acc2 += n / M
@@ -47,7 +60,7 @@ func main() {
 		//     i ≡ -p·s (mod M)
 		// and, finally:
 		//     i₀ = (-p·s) mod M
-		i0 := mod(-p*s, M)
+		i0 := num.Mod(-p*s, M)
 
 		// if i₀ == 0, that corresponds to “already at 0” and must be ignored
 		// otherwise, the dial crosses 0 once in the remainder iff i₀ ≤ n % M
@@ -57,29 +70,36 @@ func main() {
 		}
 
 		// part 1: advance p, final position is checked for 0
-		if p = mod(p+s*n, M); p == 0 {
+		if p = num.Mod(p+s*n, M); p == 0 {
 			acc1++
 		}
 	}
 
 	// output clear passwords on stdout because why not?
-	fmt.Println(acc1, acc2, time.Since(t0))
+
+	return Result{acc1, acc2}, nil
 }
 
-// mod computes euclidean modulo
-func mod(a, b int) int {
-	if a %= b; a < 0 {
-		a += b
-	}
-	return a
+// rotation turns the dial n clicks left (s = -1) or right (s = 1)
+type rotation struct {
+	s, n int
 }
 
-// strconv.Atoi simplified core loop
-// s is ^\d+$
-func atoi(s []byte) (n int) {
-	for i := range s {
-		n = 10*n + int(s[i]-'0')
-	}
+// parse reads the dial rotations, like L68 or R48
+func parse(r io.Reader) ([]rotation, error) {
+	var rots []rotation
+
+	// process input lines
+	input := bufio.NewScanner(r)
 
-	return
+	signs := [...]int{'L': -1, 'R': 1} // direction sign map
+	for input.Scan() {
+		buf := input.Bytes()
+		if len(buf) < 2 || len(buf) > 10 || (buf[0] != 'L' && buf[0] != 'R') ||
+			len(bytes.Trim(buf[1:], "0123456789")) > 0 {
+			return nil, fmt.Errorf("bad rotation %q", buf)
+		}
+		rots = append(rots, rotation{signs[buf[0]], num.Atoi(buf[1:])}) // parse direction and number
+	}
+	return rots, input.Err()
 }
@@ -14,13 +14,15 @@ import (
 	"bufio"
 	"bytes"
 	"fmt"
-	"iter"
+	"io"
 	"math"
 	"math/bits"
 	"os"
 	"sync"
 	"time"
 	"unsafe"
+
+	"github.com/erik-adelbert/aoc/lib/num"
 )
 
 const MaxSolver = 16 // number of parallel solvers -- sweet spot on M1 instead of 8 (why!?)
@@ -31,6 +33,20 @@ const MaxSolver = 16 // number of parallel solvers -- sweet spot on M1 instead o
 func main() {
 	t0 := time.Now()
 
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2, time.Since(t0))
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	machs, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
+
 	var wg sync.WaitGroup // wait group for solvers
 
 	in := make(chan mach, 4*MaxSolver)   // input machines into solvers
@@ -53,14 +69,14 @@ func main() {
 	}
 
 	// iterate over parsed machines and send them to input channel
-	parser := func() {
-		for m := range parse(bufio.NewScanner(os.Stdin)) {
+	feeder := func() {
+		for _, m := range machs {
 			in <- m
 		}
 		close(in)
 	}
 
-	go parser() // launch feeder
+	go feeder() // launch feeder
 
 	// start worker pool
 	for range MaxSolver {
@@ -77,7 +93,7 @@ func main() {
 		acc2 += r.p2
 	}
 
-	fmt.Println(acc1, acc2, time.Since(t0))
+	return Result{acc1, acc2}, nil
 }
 
 type i32 = int32
@@ -197,60 +213,90 @@ const (
 	On  = '#'
 )
 
-// parse input into machines
-func parse(input *bufio.Scanner) iter.Seq[mach] {
-	return func(yield func(mach) bool) {
-		for input.Scan() {
-			buf := input.Bytes()
-			fields := bytes.Split(buf, []byte(" "))
-
-			// lights
-			lfield := fields[0]
-			lfield = lfield[1 : len(lfield)-1] // trim brackets
+// parse input into machines, up to 16 lights each
+func parse(r io.Reader) ([]mach, error) {
+	var machs []mach
 
-			var light i32
+	input := bufio.NewScanner(r)
+	for input.Scan() {
+		buf := input.Bytes()
+		fields := bytes.Split(buf, []byte(" "))
+		if len(fields) < 3 {
+			return nil, fmt.Errorf("bad machine %q", buf)
+		}
 
-			for i, c := range lfield {
-				if c == On {
-					light |= 1 << i
-				}
-			}
+		// lights
+		lfield, ok := trim(fields[0], '[', ']')
+		if !ok || len(lfield) == 0 || len(lfield) > 16 || len(bytes.Trim(lfield, ".#")) > 0 {
+			return nil, fmt.Errorf("bad lights %q", fields[0])
+		}
+		nlight := i32(len(lfield))
 
-			// flips
-			sfields := fields[1 : len(fields)-1]
+		var light i32
 
-			flips := make([]i32, len(sfields)+1)
+		for i, c := range lfield {
+			if c == On {
+				light |= 1 << i
+			}
+		}
 
-			var w i32 // width of bitmask
+		// flips
+		sfields := fields[1 : len(fields)-1]
 
-			for i, f := range sfields {
-				f = f[1 : len(f)-1] // trim brackets
+		flips := make([]i32, len(sfields)+1)
 
-				for b := range bytes.SplitSeq(f, []byte(",")) {
-					n := atoi(b)
+		var w i32 // width of bitmask
 
-					w = max(w, n)
-					flips[i] |= 1 << n
-				}
+		for i, f := range sfields {
+			f, ok := trim(f, '(', ')') // trim brackets
+			if !ok {
+				return nil, fmt.Errorf("bad button %q", sfields[i])
 			}
-			// add dummy switch for bounding
-			flips[len(sfields)] = w + 1
 
-			// joltages
-			jfield := fields[len(fields)-1]
-			jfield = jfield[1 : len(jfield)-1] // trim brackets
-			jsets := bytes.Split(jfield, []byte(","))
+			for b := range bytes.SplitSeq(f, []byte(",")) {
+				if !isnum(b, 2) || i32(num.Atoi(b)) >= nlight {
+					return nil, fmt.Errorf("bad button %q", sfields[i])
+				}
+				n := i32(num.Atoi(b))
 
-			jolts := make([]i32, len(jsets))
-			for i := range jolts {
-				jolts[i] = atoi(jsets[i])
+				w = max(w, n)
+				flips[i] |= 1 << n
 			}
+		}
+		// add dummy switch for bounding
+		flips[len(sfields)] = w + 1
+
+		// joltages
+		jfield, ok := trim(fields[len(fields)-1], '{', '}') // trim brackets
+		jsets := bytes.Split(jfield, []byte(","))
+		if !ok || i32(len(jsets)) != nlight {
+			return nil, fmt.Errorf("bad joltages %q", fields[len(fields)-1])
+		}
 
-			if !yield(mach{flips, jolts, light}) {
-				return
+		jolts := make([]i32, len(jsets))
+		for i := range jolts {
+			if !isnum(jsets[i], 4) {
+				return nil, fmt.Errorf("bad joltages %q", fields[len(fields)-1])
 			}
+			jolts[i] = i32(num.Atoi(jsets[i]))
 		}
+
+		machs = append(machs, mach{flips, jolts, light})
+	}
+	return machs, input.Err()
+}
+
+// trim returns s stripped of its enclosing brackets
+func trim(s []byte, lb, rb byte) ([]byte, bool) {
+	if len(s) < 2 || s[0] != lb || s[len(s)-1] != rb {
+		return nil, false
 	}
+	return s[1 : len(s)-1], true
+}
+
+// isnum reports whether s is a number of up to n digits
+func isnum(s []byte, n int) bool {
+	return len(s) > 0 && len(s) <= n && len(bytes.Trim(s, "0123456789")) == 0
 }
 
 // utility functions and types
@@ -816,11 +862,3 @@ func egcd(a, b i32) (i32, i32, i32) {
 
 	return a, x0, y0
 }
-
-// atoi: convert byte slice to integer
-func atoi(s []byte) (n i32) {
-	for _, c := range s {
-		n = 10*n + i32(c-'0')
-	}
-	return
-}
@@ -13,13 +13,29 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"time"
 )
 
 func main() {
-	t0 := time.Now()   // start timer
+	t0 := time.Now() // start timer
+
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2, time.Since(t0))
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	devices, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
+
 	var acc1, acc2 int // parts 1 and 2 accumulators
 
 	// mapping from 3-letter tags to unique integer IDs
@@ -45,15 +61,8 @@ func main() {
 		edges[i] = make([]int, 0, 8)
 	}
 
-	// read graph edges as adjacency lists
-	input := bufio.NewScanner(os.Stdin)
-	for input.Scan() {
-		// parse line: "src: dst1 dst2 dst3 ..."
-		parts := strings.Split(input.Text(), ":")
-
-		// src and [dst1 dst2 ...]
-		src, dsts := parts[0], strings.Fields(parts[1]) // car and cons
-
+	// build graph edges as adjacency lists
+	for src, dsts := range devices {
 		// add edges
 		for _, d := range dsts {
 			sk, dk := id(src), id(d)
@@ -139,7 +148,50 @@ func main() {
 
 	acc2 = recount(svr, svr == dac, svr == fft)
 
-	fmt.Println(acc1, acc2, time.Since(t0))
+	return Result{acc1, acc2}, nil
+}
+
+// parse reads the devices and their outputs, "src: dst1 dst2 dst3 ...",
+// no more than MaxID-1 tags in all
+func parse(r io.Reader) (map[string][]string, error) {
+	devices := make(map[string][]string, MaxID)
+	tags := make(map[string]bool, MaxID)
+
+	// read graph edges
+	input := bufio.NewScanner(r)
+	for input.Scan() {
+		// parse line: "src: dst1 dst2 dst3 ..."
+		line := input.Text()
+		lhs, rhs, ok := strings.Cut(line, ":")
+
+		// src and [dst1 dst2 ...]
+		src, dsts := lhs, strings.Fields(rhs) // car and cons
+		if !ok || !istag(src) {
+			return nil, fmt.Errorf("bad device %q", line)
+		}
+		for _, d := range dsts {
+			if !istag(d) {
+				return nil, fmt.Errorf("bad device %q", line)
+			}
+			tags[d] = true
+		}
+		tags[src] = true
+		devices[src] = append(devices[src], dsts...)
+	}
+
+	// the lookups of you, out, svr, dac and fft count in
+	for _, t := range []string{"you", "out", "svr", "dac", "fft"} {
+		tags[t] = true
+	}
+	if len(tags) >= MaxID {
+		return nil, fmt.Errorf("more than %d tags", MaxID-1)
+	}
+	return devices, input.Err()
+}
+
+// istag reports whether s is a 3-letter tag
+func istag(s string) bool {
+	return len(s) == 3 && strings.Trim(s, "abcdefghijklmnopqrstuvwxyz") == ""
 }
 
 const Unknown = -1 // unseen DP states
@@ -14,8 +14,11 @@ import (
 	"bufio"
 	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"time"
+
+	"github.com/erik-adelbert/aoc/lib/num"
 )
 
 const (
@@ -26,10 +29,51 @@ const (
 func main() {
 	t0 := time.Now() // start timer
 
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, time.Since(t0))
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	cells, regions, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
+
 	var acc1 int // part 1 accumulator
 
+	for _, g := range regions {
+		area := g.w * g.h
+
+		// calculate total cell count
+		ncell := 0
+		for j, n := range g.counts {
+			ncell += n * cells[j] // total cells += count * poly cell size
+		}
+
+		// apply empirical 87% heuristic
+		if ncell*23 < area*20 {
+			acc1++
+		}
+	}
+
+	return Result{acc1, nil}, nil
+}
+
+// region is a WxH area under the tree and the count of each polyomino to
+// fit in
+type region struct {
+	w, h   int
+	counts []int
+}
+
+// parse reads the polyomino cell counts and the regions
+func parse(r io.Reader) (cells []int, regions []region, err error) {
 	block := make([]byte, 0, sq(PolyDim)) // current polyomino block (flattened)
-	cells := make([]int, 0, PolyCount)    // polyomino cell counts
+	cells = make([]int, 0, PolyCount)     // polyomino cell counts
 
 	// flushBlock appends current block cell count to polys
 	flushBlock := func() {
@@ -41,13 +85,14 @@ func main() {
 
 	state := Poly // input state
 
-	input := bufio.NewScanner(os.Stdin)
+	input := bufio.NewScanner(r)
 
 	for input.Scan() {
 		buf := input.Bytes()
 
 		// ---- polyomino processing phase ----
 		// ex. buf format with ncells=6:
+		// 0:
 		// .#.
 		// ###
 		// ##.
@@ -64,34 +109,38 @@ func main() {
 				flushBlock()
 				continue // proceed to next line
 
-			default:
-				// polyomino block line
+			case len(block) == 0 && bytes.HasSuffix(buf, []byte(":")) && isnum(buf[:len(buf)-1], 2),
+				len(block) > 0 && len(bytes.Trim(buf, ".#")) == 0:
+				// polyomino index or block line
 				block = append(block, buf...) // accumulate block lines
 				continue                      // proceed to next line
+
+			default:
+				return nil, nil, fmt.Errorf("bad polyomino line %q", buf)
 			}
 		}
 
 		// ---- grid processing phase ----
 		// buf format: "WxH: n0 n1 n2 n3 n4 n5"
-		lhs, rhs, _ := bytes.Cut(buf, []byte(": ")) // [WxH] [n0 n1 n2 n3 n4 n5]
-
-		// parse grid dimensions and calculate area
-		w, h, _ := bytes.Cut(lhs, []byte("x")) // [W] [H]
-		area := atoi(w) * atoi(h)
-
-		// parse polyomino counts and calculate total cell count
-		ncell := 0
-		for j, n := range bytes.Fields(rhs) { // enumerate [n0 n1 n2 n3 n4 n5]
-			ncell += atoi(n) * cells[j] // total cells += count * poly cell size
+		lhs, rhs, ok1 := bytes.Cut(buf, []byte(": ")) // [WxH] [n0 n1 n2 n3 n4 n5]
+		w, h, ok2 := bytes.Cut(lhs, []byte("x"))      // [W] [H]
+		fields := bytes.Fields(rhs)                   // [n0 n1 n2 n3 n4 n5]
+		if !ok1 || !ok2 || !isnum(w, 3) || !isnum(h, 3) || len(fields) > len(cells) {
+			return nil, nil, fmt.Errorf("bad region %q", buf)
 		}
 
-		// apply empirical 87% heuristic
-		if ncell*23 < area*20 {
-			acc1++
+		// parse polyomino counts
+		counts := make([]int, len(fields))
+		for j, n := range fields { // enumerate [n0 n1 n2 n3 n4 n5]
+			if !isnum(n, 3) {
+				return nil, nil, fmt.Errorf("bad region %q", buf)
+			}
+			counts[j] = num.Atoi(n)
 		}
-	}
 
-	fmt.Println(acc1, time.Since(t0))
+		regions = append(regions, region{num.Atoi(w), num.Atoi(h), counts})
+	}
+	return cells, regions, input.Err()
 }
 
 // input states
@@ -105,10 +154,7 @@ func sq(n int) int {
 	return n * n
 }
 
-// atoi converts a byte slice representing a non-negative integer to int
-func atoi(s []byte) (n int) {
-	for _, c := range s {
-		n = 10*n + int(c-'0')
-	}
-	return
+// isnum reports whether s is a number of up to n digits
+func isnum(s []byte, n int) bool {
+	return len(s) > 0 && len(s) <= n && len(bytes.Trim(s, "0123456789")) == 0
 }
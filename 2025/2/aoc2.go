@@ -16,19 +16,32 @@ import (
 	"bufio"
 	"bytes"
 	"fmt"
+	"io"
 	"iter"
 	"os"
 	"time"
+
+	"github.com/erik-adelbert/aoc/lib/num"
 )
 
 func main() {
 	t0 := time.Now() // start timer
 
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2, time.Since(t0))
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
 	var acc1, acc2 int // parts 1 and 2 accumulators
 
-	// read input spans
-	input := bufio.NewReader(os.Stdin)
-	buf, _, _ := input.ReadLine()
+	buf, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
 
 	for a, b := range allSpans(buf) { // split into aligned subranges
 		switch {
@@ -79,7 +92,31 @@ func main() {
 	}
 
 	acc2 += acc1 // part 2 includes part 1
-	fmt.Println(acc1, acc2, time.Since(t0))
+
+	return Result{acc1, acc2}, nil
+}
+
+// parse reads the line of comma separated ID ranges, like 11-22, up to
+// 10 digits wide
+func parse(r io.Reader) ([]byte, error) {
+	// read input spans
+	input := bufio.NewReader(r)
+	buf, _, err := input.ReadLine()
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	for span := range bytes.SplitSeq(buf, []byte(",")) {
+		lhs, rhs, ok := bytes.Cut(span, []byte("-"))
+		if !ok || !isnum(lhs) || !isnum(rhs) || num.Atoi(lhs) > num.Atoi(rhs) {
+			return nil, fmt.Errorf("bad range %q", span)
+		}
+	}
+	return buf, nil
+}
+
+func isnum(s []byte) bool {
+	return len(s) > 0 && len(s) <= 10 && len(bytes.Trim(s, "0123456789")) == 0
 }
 
 // allSpans iterates over subranges [a, b] split at ten powers boundaries
@@ -91,7 +128,7 @@ func allSpans(buf []byte) iter.Seq2[int, int] {
 		for span := range spans {
 			lhs, rhs, _ := bytes.Cut(span, []byte("-")) // parse range
 
-			a, b := atoi(lhs), atoi(rhs)
+			a, b := num.Atoi(lhs), num.Atoi(rhs)
 
 			for x := 10; x <= 1e9; x *= 10 {
 				if x > a && x <= b {
@@ -131,13 +168,3 @@ func sm(l, r, x int) int {
 	// 0 if none (n == 0)
 	return n * (α + ω) / 2
 }
-
-// strconv.Atoi simplified core loop
-// s is ^\d+$
-func atoi(s []byte) (n int) {
-	for i := range s {
-		n = 10*n + int(s[i]-'0')
-	}
-
-	return
-}
@@ -169,3 +169,8 @@ func TestSumMultiples(t *testing.T) {
 		})
 	}
 }
+
+func FuzzParse(f *testing.F) {
+	seedExamples(f)
+	fuzzParse(f, reader(parse))
+}
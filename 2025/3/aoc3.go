@@ -12,7 +12,9 @@ package main
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"slices"
 	"sync"
@@ -25,16 +27,28 @@ const MaxLines = 200
 func main() {
 	t0 := time.Now() // start timer
 
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2, time.Since(t0))
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	banks, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
+
 	var acc1, acc2 int // sums for parts 1 and 2
 
 	lines := make(chan []byte, 200)
 
 	// process input lines
 	go func() {
-		input := bufio.NewScanner(os.Stdin)
-
-		for input.Scan() {
-			lines <- slices.Clone(input.Bytes())
+		for _, buf := range banks {
+			lines <- buf
 		}
 
 		close(lines)
@@ -86,7 +100,22 @@ func main() {
 		acc2 += v.part2
 	}
 
-	fmt.Println(acc1, acc2, time.Since(t0))
+	return Result{acc1, acc2}, nil
+}
+
+// parse reads the battery banks, rows of at least Part2 digits
+func parse(r io.Reader) ([][]byte, error) {
+	banks := make([][]byte, 0, MaxLines)
+
+	input := bufio.NewScanner(r)
+	for input.Scan() {
+		buf := input.Bytes()
+		if len(buf) < Part2 || len(bytes.Trim(buf, "0123456789")) > 0 {
+			return nil, fmt.Errorf("bad bank %q", buf)
+		}
+		banks = append(banks, slices.Clone(buf))
+	}
+	return banks, input.Err()
 }
 
 const (
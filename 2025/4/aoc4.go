@@ -12,7 +12,10 @@ package main
 
 import (
 	"bufio"
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"slices"
 	"time"
@@ -21,25 +24,22 @@ import (
 func main() {
 	t0 := time.Now() // start timer
 
-	var (
-		acc1, acc2 int  // parts 1 and 2 accumulators
-		grid       grid // input grid
-	)
-
-	// read input grid
-	input := bufio.NewScanner(os.Stdin)
-
-	sz := 0
-	for i := 0; input.Scan(); i++ { // enumerate input rows
-		buf := input.Bytes()
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2, time.Since(t0))
+	}))
+}
 
-		if i == 0 {
-			sz = len(buf)
-		}
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
 
-		copy(grid[i*sz:], buf) // flat copy into grid
+	grid, sz, err := parse(r)
+	if err != nil {
+		return Result{}, err
 	}
 
+	var acc1, acc2 int // parts 1 and 2 accumulators
+
 	// scan for roll removal using single buffer + double-buffered queue
 
 	// preallocate double buffer queues
@@ -140,7 +140,38 @@ func main() {
 		queue0, queue1 = queue1, queue0[:0] // swap queues, reset write queue
 	}
 
-	fmt.Println(acc1, acc2, time.Since(t0))
+	return Result{acc1, acc2}, nil
+}
+
+// parse reads the square grid of paper rolls and its size
+func parse(r io.Reader) (g *grid, sz int, err error) {
+	g = new(grid)
+
+	// read input grid
+	input := bufio.NewScanner(r)
+
+	n := 0
+	for i := 0; input.Scan(); i++ { // enumerate input rows
+		buf := input.Bytes()
+
+		if i == 0 {
+			sz = len(buf)
+		}
+
+		if i >= sz || len(buf) != sz || sz > MaxGridSize || len(bytes.Trim(buf, ".@")) > 0 {
+			return nil, 0, fmt.Errorf("bad grid row %q", buf)
+		}
+
+		copy(g[i*sz:], buf) // flat copy into grid
+		n++
+	}
+	if err := input.Err(); err != nil {
+		return nil, 0, err
+	}
+	if n == 0 || n != sz {
+		return nil, 0, errors.New("grid is not square")
+	}
+	return g, sz, nil
 }
 
 const (
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bytes"
 	"slices"
 	"testing"
 )
@@ -170,3 +171,11 @@ KEY TAKEAWAYS:
 This demonstrates why the aoc4.go solution achieves 1.6ms runtime -
 zero allocations in the hot path is crucial for competitive programming performance.
 */
+
+func FuzzParse(f *testing.F) {
+	seedExamples(f)
+	fuzzParse(f, func(data []byte) (*grid, error) {
+		g, _, err := parse(bytes.NewReader(data))
+		return g, err
+	})
+}
@@ -14,44 +14,34 @@ import (
 	"bufio"
 	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"slices"
 	"time"
+
+	"github.com/erik-adelbert/aoc/lib/num"
 )
 
 func main() {
 	t0 := time.Now() // start timer
 
-	var acc1, acc2 int // parts 1 and 2 counts
-
-	// read spans and queries
-	input := bufio.NewScanner(os.Stdin)
-
-	spans := make([]span, 0, SpanCountHint)
-	queries := make([]int, 0, QueryCountHint)
-
-	// state machine parser
-	state := Span
-	for input.Scan() {
-		buf := input.Bytes()
-
-		switch {
-		case len(buf) == 0:
-			// blank line separates spans and queries
-
-			acc2, spans = merge(spans) // merge intervals and calculate total coverage
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2, time.Since(t0))
+	}))
+}
 
-			state = Query
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
 
-		case state == Span:
-			start, end, _ := bytes.Cut(buf, []byte("-")) // parse range
+	spans, queries, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
 
-			spans = append(spans, span{atoi(start), atoi(end)})
+	var acc1, acc2 int // parts 1 and 2 counts
 
-		case state == Query:
-			queries = append(queries, atoi(buf))
-		}
-	}
+	acc2, spans = merge(spans) // merge intervals and calculate total coverage
 
 	// process queries
 	slices.Sort(queries)
@@ -72,7 +62,49 @@ func main() {
 		}
 	}
 
-	fmt.Println(acc1, acc2, time.Since(t0))
+	return Result{acc1, acc2}, nil
+}
+
+// parse reads the fresh ingredient ID ranges and, past a blank line, the
+// available ingredient IDs
+func parse(r io.Reader) (spans []span, queries []int, err error) {
+	// read spans and queries
+	input := bufio.NewScanner(r)
+
+	spans = make([]span, 0, SpanCountHint)
+	queries = make([]int, 0, QueryCountHint)
+
+	// state machine parser
+	state := Span
+	for input.Scan() {
+		buf := input.Bytes()
+
+		switch {
+		case len(buf) == 0 && state == Span:
+			// blank line separates spans and queries
+			state = Query
+
+		case state == Span:
+			start, end, ok := bytes.Cut(buf, []byte("-")) // parse range
+			if !ok || !isnum(start) || !isnum(end) || num.Atoi(start) > num.Atoi(end) {
+				return nil, nil, fmt.Errorf("bad range %q", buf)
+			}
+
+			spans = append(spans, span{num.Atoi(start), num.Atoi(end)})
+
+		case state == Query:
+			if !isnum(buf) {
+				return nil, nil, fmt.Errorf("bad ID %q", buf)
+			}
+			queries = append(queries, num.Atoi(buf))
+		}
+	}
+	return spans, queries, input.Err()
+}
+
+// isnum reports whether s is a number of up to 15 digits
+func isnum(s []byte) bool {
+	return len(s) > 0 && len(s) <= 15 && len(bytes.Trim(s, "0123456789")) == 0
 }
 
 // merge merges overlapping intervals and calculates total coverage
@@ -88,18 +120,18 @@ func merge(spans []span) (int, []span) {
 	// merge overlapping intervals, count cover and populate tree
 	cover, cur := 0, spans[0]
 
-	for i := range spans[1:] {
-		if spans[i].start <= cur.end+1 {
+	for _, s := range spans[1:] {
+		if s.start <= cur.end+1 {
 			// overlapping or adjacent intervals - merge
-			if spans[i].end > cur.end {
-				cur.end = spans[i].end
+			if s.end > cur.end {
+				cur.end = s.end
 			}
 		} else {
 			// non-overlapping interval - add current coverage and start new interval
 			cover += cur.end - cur.start + 1
 			merged = append(merged, cur)
 
-			cur = spans[i]
+			cur = s
 		}
 	}
 
@@ -126,12 +158,3 @@ const (
 	SpanCountHint       = 187
 	MergedSpanCountHint = 78
 )
-
-// strconv.Atoi simplified core loop
-// s is ^\d+$
-func atoi(s []byte) (n int) {
-	for i := range s {
-		n = 10*n + int(s[i]-'0')
-	}
-	return
-}
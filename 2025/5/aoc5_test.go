@@ -0,0 +1,14 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func FuzzParse(f *testing.F) {
+	seedExamples(f)
+	fuzzParse(f, func(data []byte) ([]span, error) {
+		spans, _, err := parse(bytes.NewReader(data))
+		return spans, err
+	})
+}
@@ -13,9 +13,13 @@ package main
 import (
 	"bufio"
 	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"time"
+
+	"github.com/erik-adelbert/aoc/lib/num"
 )
 
 const BufSizeHint = 1 << 10 // 1 K buffer size
@@ -23,17 +27,22 @@ const BufSizeHint = 1 << 10 // 1 K buffer size
 func main() {
 	t0 := time.Now() // start timer
 
-	var acc1, acc2 int // parts 1 and 2 accumulators
-
-	input := bufio.NewScanner(os.Stdin)
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2, time.Since(t0))
+	}))
+}
 
-	var lines [][]byte
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
 
-	// read all input lines
-	for input.Scan() {
-		lines = append(lines, bytes.Clone(input.Bytes()))
+	lines, err := parse(r)
+	if err != nil {
+		return Result{}, err
 	}
 
+	var acc1, acc2 int // parts 1 and 2 accumulators
+
 	// parse layout from the last line
 	layout := lines[len(lines)-1]
 
@@ -93,36 +102,68 @@ func main() {
 		switch ops[c] {
 		case '+':
 			for r := range h {
-				acc1 += atoi(bytes.TrimSpace(token[r][:w]))
+				acc1 += num.Atoi(bytes.TrimSpace(token[r][:w]))
 			}
 
 			for c := range w {
-				acc2 += atoi(bytes.TrimSpace(nekot[c][:h]))
+				acc2 += num.Atoi(bytes.TrimSpace(nekot[c][:h]))
 			}
 		case '*':
 			prod := 1
 			for r := range h {
-				prod *= atoi(bytes.TrimSpace(token[r][:w]))
+				prod *= num.Atoi(bytes.TrimSpace(token[r][:w]))
 			}
 			acc1 += prod
 
 			prod = 1
 			for c := range w {
-				prod *= atoi(bytes.TrimSpace(nekot[c][:h]))
+				prod *= num.Atoi(bytes.TrimSpace(nekot[c][:h]))
 			}
 			acc2 += prod
 		}
 	}
 
-	fmt.Println(acc1, acc2, time.Since(t0))
+	return Result{acc1, acc2}, nil
 }
 
-// strconv.Atoi simplified core loop
-// s is ^\d+$
-func atoi(s []byte) (n int) {
-	for i := range s {
-		n = 10*n + int(s[i]-'0')
+// parse reads the worksheet, rows of numbers aligned in columns above a
+// layout row of operations starting each column
+func parse(r io.Reader) ([][]byte, error) {
+	input := bufio.NewScanner(r)
+
+	var lines [][]byte
+
+	// read all input lines
+	for input.Scan() {
+		buf := input.Bytes()
+		if len(buf) == 0 || len(lines) > 0 && len(buf) != len(lines[0]) {
+			return nil, fmt.Errorf("bad worksheet row %q", buf)
+		}
+		lines = append(lines, bytes.Clone(buf))
+	}
+	if err := input.Err(); err != nil {
+		return nil, err
+	}
+	if len(lines) < 2 {
+		return nil, errors.New("no numbers or no layout")
+	}
+
+	// numbers fill the rows, operations start the columns, a blank
+	// column separates them
+	for _, buf := range lines[:len(lines)-1] {
+		if len(bytes.Trim(buf, " 0123456789")) > 0 {
+			return nil, fmt.Errorf("bad worksheet row %q", buf)
+		}
 	}
 
-	return
+	layout := lines[len(lines)-1]
+	for i, op := range layout {
+		switch {
+		case op != ' ' && op != '+' && op != '*',
+			i == 0 && op == ' ',
+			i > 0 && op != ' ' && layout[i-1] != ' ':
+			return nil, fmt.Errorf("bad layout %q", layout)
+		}
+	}
+	return lines, nil
 }
@@ -13,7 +13,9 @@ package main
 import (
 	"bufio"
 	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"time"
 )
@@ -21,20 +23,30 @@ import (
 func main() {
 	t0 := time.Now() // start timer
 
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2, time.Since(t0))
+	}))
+}
+
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
+
+	rows, err := parse(r)
+	if err != nil {
+		return Result{}, err
+	}
+
 	var acc1, acc2 int // parts 1 and 2 accumulators
 
 	var paths [MaxWidth]int // count of paths at each position
 
-	input := bufio.NewScanner(os.Stdin)
-	input.Scan()
-
 	// get started with first row
-	i := bytes.Index(input.Bytes(), []byte("S")) // find starting position 'S'
-	paths[i] = 1                                 // start with 1 path at S
+	i := bytes.Index(rows[0], []byte("S")) // find starting position 'S'
+	paths[i] = 1                           // start with 1 path at S
 
 	// process all remaining rows
-	for input.Scan() {
-		buf := input.Bytes()
+	for _, buf := range rows[1:] {
 
 		for i := range buf {
 			if buf[i] != Prism {
@@ -63,7 +75,37 @@ func main() {
 		acc2 += paths[i]
 	}
 
-	fmt.Println(acc1, acc2, time.Since(t0))
+	return Result{acc1, acc2}, nil
+}
+
+// parse reads the manifold, a start 'S' on its first row and splitters '^'
+// off its sides below
+func parse(r io.Reader) ([][]byte, error) {
+	var rows [][]byte
+
+	input := bufio.NewScanner(r)
+	for input.Scan() {
+		buf := input.Bytes()
+
+		switch {
+		case len(buf) < 1 || len(buf) > MaxWidth,
+			len(rows) > 0 && len(buf) != len(rows[0]),
+			len(bytes.Trim(buf, ".^S")) > 0:
+			return nil, fmt.Errorf("bad manifold row %q", buf)
+		case len(rows) == 0 && (bytes.Count(buf, []byte("S")) != 1 || bytes.IndexByte(buf, Prism) >= 0):
+			return nil, fmt.Errorf("bad start row %q", buf)
+		case len(rows) > 0 && (bytes.IndexByte(buf, 'S') >= 0 || buf[0] == Prism || buf[len(buf)-1] == Prism):
+			return nil, fmt.Errorf("bad manifold row %q", buf)
+		}
+		rows = append(rows, bytes.Clone(buf))
+	}
+	if err := input.Err(); err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("empty manifold")
+	}
+	return rows, nil
 }
 
 const (
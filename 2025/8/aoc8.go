@@ -13,9 +13,13 @@ package main
 import (
 	"bufio"
 	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"time"
+
+	"github.com/erik-adelbert/aoc/lib/num"
 )
 
 const (
@@ -26,20 +30,20 @@ const (
 func main() {
 	t0 := time.Now()
 
-	var acc1, acc2 int // parts 1 and 2 accumulators
-
-	points := make([]point, 0, N)
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2, time.Since(t0)) // output results
+	}))
+}
 
-	input := bufio.NewScanner(os.Stdin)
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
 
-	for i := 0; input.Scan(); i++ {
-		fields := bytes.SplitN(input.Bytes(), []byte(","), 3)
+	var acc1, acc2 int // parts 1 and 2 accumulators
 
-		points = append(points, point{
-			X: atoi(fields[0]),
-			Y: atoi(fields[1]),
-			Z: atoi(fields[2]),
-		})
+	points, err := parse(r)
+	if err != nil {
+		return Result{}, err
 	}
 
 	n := len(points)
@@ -105,11 +109,36 @@ func main() {
 		case unions == N-1: // part 2: after 1000 unions, spanning tree is complete
 			acc2 = points[e.i].X * points[e.j].X // product of X coords of last edge
 
-			fmt.Println(acc1, acc2, time.Since(t0)) // output results
+			return Result{acc1, acc2}, nil
+		}
+	}
 
-			return
+	return Result{}, errors.New("spanning tree is incomplete")
+}
+
+// parse reads the junction box positions, X,Y,Z up to 6 digits each
+func parse(r io.Reader) ([]point, error) {
+	points := make([]point, 0, N)
+
+	input := bufio.NewScanner(r)
+
+	for i := 0; input.Scan(); i++ {
+		fields := bytes.SplitN(input.Bytes(), []byte(","), 3)
+		if len(fields) != 3 || !isnum(fields[0]) || !isnum(fields[1]) || !isnum(fields[2]) {
+			return nil, fmt.Errorf("bad junction box %q", input.Bytes())
 		}
+
+		points = append(points, point{
+			X: num.Atoi(fields[0]),
+			Y: num.Atoi(fields[1]),
+			Z: num.Atoi(fields[2]),
+		})
 	}
+	return points, input.Err()
+}
+
+func isnum(s []byte) bool {
+	return len(s) > 0 && len(s) <= 6 && len(bytes.Trim(s, "0123456789")) == 0
 }
 
 // qselect3 performs a quickselect using median-of-three partitioning
@@ -231,12 +260,3 @@ func dist2(a, b point) int {
 
 	return dx*dx + dy*dy + dz*dz
 }
-
-// atoi converts a byte slice representing a non-negative integer to int
-func atoi(s []byte) (n int) {
-	for _, c := range s {
-		n = 10*n + int(c-'0')
-	}
-
-	return
-}
@@ -30,3 +30,8 @@ func BenchmarkSplitN(b *testing.B) {
 
 	sink = x[0] // prevent optimization
 }
+
+func FuzzParse(f *testing.F) {
+	seedExamples(f)
+	fuzzParse(f, reader(parse))
+}
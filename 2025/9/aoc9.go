@@ -15,27 +15,32 @@ import (
 	"bufio"
 	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"slices"
 	"sync"
 	"time"
+
+	"github.com/erik-adelbert/aoc/lib/num"
 )
 
 func main() {
 	t0 := time.Now()
 
-	var acc1, acc2 u64 // parts 1 and 2 accumulators
-
-	tiles := make([]tile, 0, SizeHint)
+	os.Exit(runDay(Solve, func(res Result) {
+		fmt.Println(res.Part1, res.Part2, time.Since(t0))
+	}))
+}
 
-	// read input points
-	input := bufio.NewScanner(os.Stdin)
+// Solve computes the day answers from the puzzle input
+func Solve(r io.Reader) (_ Result, err error) {
+	defer guard(&err)
 
-	for input.Scan() {
-		bufX, bufY, _ := bytes.Cut(input.Bytes(), []byte(","))
-		x, y := atoi(bufX), atoi(bufY)
+	var acc1, acc2 u64 // parts 1 and 2 accumulators
 
-		tiles = append(tiles, tile{x, y})
+	tiles, err := parse(r)
+	if err != nil {
+		return Result{}, err
 	}
 
 	// sort tiles by y, then x
@@ -62,7 +67,26 @@ func main() {
 
 	wg.Wait()
 
-	fmt.Println(acc1, acc2, time.Since(t0))
+	return Result{acc1, acc2}, nil
+}
+
+// parse reads the red tiles "x,y"
+func parse(r io.Reader) ([]tile, error) {
+	tiles := make([]tile, 0, SizeHint)
+
+	// read input points
+	input := bufio.NewScanner(r)
+
+	for input.Scan() {
+		bufX, bufY, ok := bytes.Cut(input.Bytes(), []byte(","))
+		if !ok || !isnum(bufX) || !isnum(bufY) {
+			return nil, fmt.Errorf("bad tile %q", input.Bytes())
+		}
+		x, y := u32(num.Atoi(bufX)), u32(num.Atoi(bufY))
+
+		tiles = append(tiles, tile{x, y})
+	}
+	return tiles, input.Err()
 }
 
 // part1 computes the maximum rectangle area using left and right tops and bottoms
@@ -262,13 +286,9 @@ func adiff64(a, b u32) u64 {
 	return u64(b - a)
 }
 
-// atoi converts a byte slice representing a non-negative integer to uint32
-func atoi(s []byte) (n u32) {
-	for _, c := range s {
-		n = 10*n + u32(c-'0')
-	}
-
-	return
+// isnum reports whether s is a number of up to 9 digits
+func isnum(s []byte) bool {
+	return len(s) > 0 && len(s) <= 9 && len(bytes.Trim(s, "0123456789")) == 0
 }
 
 const SizeHint = 497
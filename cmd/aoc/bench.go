@@ -0,0 +1,284 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	runs   int // see -n
+	warmup int // see -warmup
+)
+
+func benchFlags(fs *flag.FlagSet) {
+	fs.IntVar(&runs, "n", 100, "run count")
+	fs.IntVar(&warmup, "warmup", 10, "warmup run count")
+}
+
+// duration matches a solver reported time.Since, eg. 1234.56µs or 1.234ms
+var duration = regexp.MustCompile(`[0-9]+(\.[0-9]+)? *([µμu]s|ms|s)\b`)
+
+// stats sums up the timings of a day
+type stats struct {
+	Year   int    `json:"year"`
+	Day    int    `json:"day"`
+	Runs   int    `json:"runs"`
+	Source string `json:"source"` // self reported or wall clock
+
+	Min    time.Duration `json:"min_ns"`
+	Median time.Duration `json:"median_ns"`
+	Mean   time.Duration `json:"mean_ns"`
+	P95    time.Duration `json:"p95_ns"`
+	Stddev time.Duration `json:"stddev_ns"`
+}
+
+// summarize computes the stats of a sample
+func summarize(year, day int, source string, sample []time.Duration) stats {
+	s := stats{Year: year, Day: day, Runs: len(sample), Source: source}
+	if len(sample) == 0 {
+		return s
+	}
+
+	sorted := slices.Clone(sample)
+	slices.Sort(sorted)
+
+	n := len(sorted)
+
+	s.Min = sorted[0]
+	s.Median = sorted[n/2]
+	if n%2 == 0 {
+		s.Median = (sorted[n/2-1] + sorted[n/2]) / 2
+	}
+	s.P95 = sorted[(95*n+99)/100-1] // nearest rank
+
+	var sum float64
+	for _, δ := range sorted {
+		sum += float64(δ)
+	}
+	mean := sum / float64(n)
+
+	var sq float64
+	for _, δ := range sorted {
+		sq += (float64(δ) - mean) * (float64(δ) - mean)
+	}
+
+	s.Mean = time.Duration(mean)
+	s.Stddev = time.Duration(math.Sqrt(sq / float64(n)))
+
+	return s
+}
+
+func (s stats) String() string {
+	return fmt.Sprintf("%d-%d: min %v median %v mean %v p95 %v stddev %v (%d %s runs)",
+		s.Year, s.Day, s.Min, s.Median, s.Mean, s.P95, s.Stddev, s.Runs, s.Source)
+}
+
+// bench times a day binary, it builds it unless given
+func bench(s *setup, args []string) error {
+	dir, err := s.dayDir()
+	if err != nil {
+		return err
+	}
+
+	var bin string
+	if len(args) > 0 {
+		bin = filepath.Join(dir, args[0])
+	} else {
+		tmp, err := os.MkdirTemp("", "aoc")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(tmp)
+
+		if bin, err = build(dir, s.Day, tmp); err != nil {
+			return err
+		}
+	}
+
+	var base time.Duration
+	if base, err = baseline(dir); err != nil {
+		return err
+	}
+
+	st, err := benchDay(s.Year, s.Day, dir, bin, base)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(st)
+	return nil
+}
+
+// benchDay runs bin on its input, first for warmup and then for timing
+func benchDay(year, day int, dir, bin string, base time.Duration) (stats, error) {
+	var sample []time.Duration
+
+	source := "self"
+	for i := range warmup + runs {
+		δ, self, err := runOnce(dir, bin, INPUT)
+		if err != nil {
+			return stats{}, err
+		}
+
+		if i < warmup {
+			continue
+		}
+
+		if !self {
+			source = "wall"
+			δ = max(δ-base, 0)
+		}
+		sample = append(sample, δ)
+	}
+
+	return summarize(year, day, source, sample), nil
+}
+
+// build compiles a day solver into out and returns the binary path
+func build(dir string, day int, out string) (string, error) {
+	bin := filepath.Join(out, fmt.Sprintf("aoc%d", day))
+
+	args := []string{"build", "-o", bin}
+	if _, err := os.Stat(filepath.Join(dir, "go.mod")); errors.Is(err, os.ErrNotExist) {
+		files, err := sources(dir) // no module, name the day files
+		if err != nil {
+			return "", err
+		}
+		args = append(args, files...)
+	}
+
+	cmd := exec.Command("go", args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("building %s: %w\n%s", dir, err, out)
+	}
+
+	return bin, nil
+}
+
+// sources returns the day files of dir: its solver, its hook.go and
+// helpers, tests excluded
+func sources(dir string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(files, func(name string) bool {
+		return strings.HasSuffix(name, "_test.go")
+	}), nil
+}
+
+// NOOP is the baseline program, it is to the solvers what cat was to
+// hyperfine: the cost of starting a go binary and feeding it its input
+const NOOP = "package main\n\nfunc main() {}\n"
+
+// baseline returns the fastest run of the noop program on the dir input,
+// it is removed from the wall clock timings
+func baseline(dir string) (time.Duration, error) {
+	tmp, err := os.MkdirTemp("", "aoc")
+	if err != nil {
+		return 0, err
+	}
+	defer os.RemoveAll(tmp)
+
+	src := filepath.Join(tmp, "noop.go")
+	if err := os.WriteFile(src, []byte(NOOP), 0o644); err != nil {
+		return 0, err
+	}
+
+	bin := filepath.Join(tmp, "noop")
+	if out, err := exec.Command("go", "build", "-o", bin, src).CombinedOutput(); err != nil {
+		return 0, fmt.Errorf("building baseline: %w\n%s", err, out)
+	}
+
+	best := time.Duration(math.MaxInt64)
+	for range max(runs/10, 10) {
+		δ, _, err := runOnce(dir, bin, INPUT)
+		if err != nil {
+			return 0, err
+		}
+		best = min(best, δ)
+	}
+
+	return best, nil
+}
+
+// runOnce runs bin in dir with input as stdin and returns its duration, the
+// self reported one when the solver reports or prints it, the wall clock
+// otherwise
+func runOnce(dir, bin, input string) (δ time.Duration, self bool, err error) {
+	in, err := os.Open(filepath.Join(dir, input))
+	if err != nil {
+		return 0, false, err
+	}
+	defer in.Close()
+
+	var out, diag bytes.Buffer
+
+	cmd := exec.Command(bin)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), OUTPUT)
+	cmd.Stdin = in
+	cmd.Stdout = &out
+	cmd.Stderr = &diag
+
+	t0 := time.Now()
+	if err := cmd.Run(); err != nil {
+		return 0, false, fmt.Errorf("running %s: %w", bin, err)
+	}
+	wall := time.Since(t0)
+
+	if r, ok := parseReport(out.Bytes()); ok {
+		return time.Duration(r.Elapsed), true, nil
+	}
+
+	out.Write(diag.Bytes())
+	if δ, ok := parseDuration(out.Bytes()); ok {
+		return δ, true, nil
+	}
+	return wall, false, nil
+}
+
+// OUTPUT asks the solvers for a json report, see hook/hook.go
+const OUTPUT = "AOC_OUTPUT=json"
+
+// parseReport decodes the json report of a solver, numbers are kept as
+// printed
+func parseReport(out []byte) (report, bool) {
+	var r report
+
+	dec := json.NewDecoder(bytes.NewReader(out))
+	dec.UseNumber()
+	if err := dec.Decode(&r); err != nil {
+		return r, false
+	}
+	return r, true
+}
+
+// parseDuration returns the last duration printed in out
+func parseDuration(out []byte) (time.Duration, bool) {
+	all := duration.FindAll(out, -1)
+	if len(all) == 0 {
+		return 0, false
+	}
+
+	tok := strings.ReplaceAll(string(all[len(all)-1]), " ", "")
+	tok = strings.NewReplacer("us", "µs", "μs", "µs").Replace(tok)
+
+	δ, err := time.ParseDuration(tok)
+	if err != nil {
+		return 0, false
+	}
+	return δ, true
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		out      string
+		expected string
+		ok       bool
+	}{
+		{"1234 5678 123.5µs\n", "123.5µs", true},
+		{"42 1.234ms\n", "1.234ms", true},
+		{"42 17 800us\n", "800µs", true},
+		{"42 17\n", "0s", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.out, func(t *testing.T) {
+			δ, ok := parseDuration([]byte(tt.out))
+			if ok != tt.ok || δ.String() != tt.expected {
+				t.Errorf("parseDuration(%q) = %v, %v, expected %s, %v", tt.out, δ, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
+
+func TestParseReport(t *testing.T) {
+	tests := []struct {
+		out string
+		ok  bool
+	}{
+		{`{"year":2025,"day":8,"part1":1,"part2":2,"elapsed_ns":1500}` + "\n", true},
+		{"42 17 1.5µs\n", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.out, func(t *testing.T) {
+			r, ok := parseReport([]byte(tt.out))
+			if ok != tt.ok {
+				t.Fatalf("parseReport(%q) ok = %v, expected %v", tt.out, ok, tt.ok)
+			}
+			if ok && (r.Year != 2025 || r.Day != 8 || r.Elapsed != 1500 || fmt.Sprint(r.Part1) != "1" || fmt.Sprint(r.Part2) != "2") {
+				t.Errorf("parseReport(%q) = %+v", tt.out, r)
+			}
+		})
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	var sample []time.Duration
+	for i := 20; i >= 1; i-- { // unsorted
+		sample = append(sample, time.Duration(i)*time.Millisecond)
+	}
+
+	s := summarize(2023, 1, "self", sample)
+
+	expected := stats{
+		Year: 2023, Day: 1, Runs: 20, Source: "self",
+		Min:    1 * time.Millisecond,
+		Median: 10500 * time.Microsecond,
+		Mean:   10500 * time.Microsecond,
+		P95:    19 * time.Millisecond,
+		Stddev: 5766281, // √((20²-1)/12) ms
+	}
+
+	if s != expected {
+		t.Errorf("summarize() =\n%+v, expected\n%+v", s, expected)
+	}
+
+	if s := summarize(2023, 1, "self", nil); s.Runs != 0 || s.Min != 0 {
+		t.Errorf("empty sample: %+v", s)
+	}
+}
+
+// SOLVER is a tiny day solver, it reports its own time when asked to
+const SOLVER = `package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+func main() {
+	t0 := time.Now()
+	data, _ := io.ReadAll(os.Stdin)
+	if %v {
+		fmt.Println(len(data), time.Since(t0))
+		return
+	}
+	fmt.Println(len(data))
+}
+`
+
+func TestRuntime(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds solvers")
+	}
+
+	root := t.TempDir()
+	for day, self := range map[int]bool{1: true, 2: false, 3: true} {
+		dir := filepath.Join(root, "2023", fmt.Sprint(day))
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		src := fmt.Sprintf(SOLVER, self)
+		if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf(SRC, day)), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		if day == 3 {
+			continue // no input
+		}
+		if err := os.WriteFile(filepath.Join(dir, INPUT), []byte("input\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	oruns, owarmup, ostat := runs, warmup, stat
+	defer func() { runs, warmup, stat = oruns, owarmup, ostat }()
+	runs, warmup, stat = 5, 1, "min"
+
+	s := setup{dir: filepath.Join(root, "2023")}
+	if err := s.resolve(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := timings(&s, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	md, err := os.ReadFile(filepath.Join(root, "2023", RUNTIME+".md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(md)), "\n")
+	if len(lines) != 5 || lines[0] != "| day | time |" || !strings.HasPrefix(lines[4], "| total | ") {
+		t.Errorf("unexpected runtime.md:\n%s", md)
+	}
+
+	data, err := os.ReadFile(filepath.Join(root, "2023", RUNTIME+".json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var all []stats
+	if err := json.Unmarshal(data, &all); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(all) != 2 || all[0].Source != "self" || all[1].Source != "wall" || all[0].Runs != 5 {
+		t.Errorf("unexpected runtime.json: %+v", all)
+	}
+
+	csv, err := os.ReadFile(filepath.Join(root, "2023", RUNTIME+".csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(csv), "\n"); n != 3 {
+		t.Errorf("expected 3 csv lines, got %d:\n%s", n, csv)
+	}
+}
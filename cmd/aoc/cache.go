@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// cache is a shared content-addressed input store:
+//
+//	<dir>/objects/<hash[:2]>/<hash[2:]>   input contents
+//	<dir>/index/<user>/<year>/<day>       input hash
+//
+// inputs differ by user so the index is keyed by a digest of the session
+type cache struct {
+	dir string
+}
+
+// openCache opens the cache in $AOC_CACHE, or in the user cache directory
+func openCache() (cache, error) {
+	dir := os.Getenv("AOC_CACHE")
+	if dir == "" {
+		base, err := os.UserCacheDir()
+		if err != nil {
+			return cache{}, fmt.Errorf("error locating cache: %w", err)
+		}
+		dir = filepath.Join(base, "aoc")
+	}
+
+	return cache{dir}, nil
+}
+
+func digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func (c cache) index(year, day int, session string) string {
+	user := digest([]byte(session))[:16]
+	return filepath.Join(c.dir, "index", user, fmt.Sprint(year), fmt.Sprint(day))
+}
+
+func (c cache) object(hash string) string {
+	return filepath.Join(c.dir, "objects", hash[:2], hash[2:])
+}
+
+// has tells if an input is cached
+func (c cache) has(year, day int, session string) bool {
+	_, ok := c.get(year, day, session)
+	return ok
+}
+
+// get returns a cached input, a corrupted object is a miss
+func (c cache) get(year, day int, session string) ([]byte, bool) {
+	hash, err := os.ReadFile(c.index(year, day, session))
+	if err != nil {
+		return nil, false
+	}
+	hash = bytes.TrimSpace(hash)
+	if len(hash) != sha256.Size*2 {
+		return nil, false
+	}
+
+	data, err := os.ReadFile(c.object(string(hash)))
+	if err != nil || digest(data) != string(hash) {
+		return nil, false
+	}
+
+	return data, true
+}
+
+// put stores an input
+func (c cache) put(year, day int, session string, data []byte) error {
+	hash := digest(data)
+
+	for _, f := range []struct {
+		name string
+		data []byte
+	}{
+		{c.object(hash), data},
+		{c.index(year, day, session), []byte(hash + "\n")},
+	} {
+		if err := os.MkdirAll(filepath.Dir(f.name), 0o755); err != nil {
+			return fmt.Errorf("error caching input: %w", err)
+		}
+
+		if err := writeFile(f.name, f.data); err != nil {
+			return fmt.Errorf("error caching input: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,379 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	BARCHART = "barchart" // per year chart, in the year images
+	YEARS    = "years"    // cross-year chart, in the root images
+	IMAGES   = "images"
+)
+
+func chartFlags(fs *flag.FlagSet) {
+	fs.StringVar(&stat, "stat", "min", "charted statistic: min, median, mean or p95")
+}
+
+// bar is a labeled chart value
+type bar struct {
+	label string
+	value int // μs
+}
+
+// chart is a horizontal bar chart, fastest on top as barchart.py drew it
+type chart struct {
+	xlabel, ylabel string
+	bars           []bar
+}
+
+// newChart sorts bars by increasing value
+func newChart(xlabel, ylabel string, bars []bar) chart {
+	bars = slices.Clone(bars)
+	slices.SortStableFunc(bars, func(a, b bar) int {
+		return cmp.Compare(a.value, b.value)
+	})
+	return chart{xlabel, ylabel, bars}
+}
+
+// charts draws the year bar chart from its runtime.json, and from the root
+// every year chart plus the cross-year comparison of the totals
+func charts(s *setup, _ []string) error {
+	if s.Year != 0 {
+		_, err := yearChart(s)
+		return err
+	}
+
+	dirs, err := os.ReadDir(s.root)
+	if err != nil {
+		return err
+	}
+
+	var totals []bar
+	for _, d := range dirs {
+		year, err := strconv.Atoi(d.Name())
+		if err != nil || year < FIRST || !d.IsDir() {
+			continue
+		}
+
+		ys := *s
+		ys.Year = year
+
+		total, err := yearChart(&ys)
+		if errors.Is(err, os.ErrNotExist) {
+			continue // not timed yet
+		}
+		if err != nil {
+			return err
+		}
+
+		totals = append(totals, bar{d.Name(), total})
+	}
+
+	if len(totals) == 0 {
+		return errors.New("no runtime.json found, see aoc runtime")
+	}
+
+	c := newChart("Time (μs)", "Year", totals)
+	return c.save(filepath.Join(s.root, IMAGES, YEARS))
+}
+
+// yearChart draws a year chart and returns the year total in μs
+func yearChart(s *setup) (int, error) {
+	dir, err := s.yearDir()
+	if err != nil {
+		return 0, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, RUNTIME+".json"))
+	if err != nil {
+		return 0, err
+	}
+
+	var all []stats
+	if err := json.Unmarshal(data, &all); err != nil {
+		return 0, fmt.Errorf("%s: %w", RUNTIME+".json", err)
+	}
+
+	var (
+		bars  []bar
+		total int
+	)
+	for _, st := range all {
+		δ, err := st.pick()
+		if err != nil {
+			return 0, err
+		}
+
+		μs := int(δ.Round(time.Microsecond) / time.Microsecond)
+		bars = append(bars, bar{strconv.Itoa(st.Day), μs})
+		total += μs
+	}
+
+	c := newChart("Time (μs)", "Day", bars)
+	return total, c.save(filepath.Join(dir, IMAGES, BARCHART))
+}
+
+// save writes the chart as name.svg and name.png
+func (c chart) save(name string) error {
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+
+	for ext, draw := range map[string]func(io.Writer) error{
+		".svg": c.svg,
+		".png": c.png,
+	} {
+		fd, err := os.Create(name + ext)
+		if err != nil {
+			return err
+		}
+
+		if err := draw(fd); err != nil {
+			fd.Close()
+			return err
+		}
+
+		if err := fd.Close(); err != nil {
+			return err
+		}
+		warnf("wrote %s", name+ext)
+	}
+
+	return nil
+}
+
+// layout, in px
+const (
+	WIDTH = 1000
+	ROW   = 24 // bar pitch
+	BAR   = 18 // bar thickness
+	PADL  = 60 // y axis
+	PADR  = 90 // room for the value labels
+	PADT  = 40 // room for the y label
+	PADB  = 60 // room for the ticks and x label
+	TICK  = 5
+	GAP   = 8 // between a bar and its label
+)
+
+// gray is barchart.py medium gray for bars and text
+var gray = color.NRGBA{0x80, 0x80, 0x80, 0xff}
+
+func (c chart) height() int {
+	return PADT + len(c.bars)*ROW + PADB
+}
+
+// x maps a value to its abscissa
+func (c chart) x(v int) int {
+	return PADL + int(math.Round(float64(v)*float64(WIDTH-PADL-PADR)/float64(c.top())))
+}
+
+// top returns the axis upper bound
+func (c chart) top() int {
+	top := 1
+	for _, b := range c.bars {
+		top = max(top, b.value)
+	}
+	return top
+}
+
+// ticks returns round x axis graduations, 1, 2 or 5 times a power of ten
+func (c chart) ticks() []int {
+	top := c.top()
+
+	step := func() int {
+		for pow := 1; ; pow *= 10 {
+			for _, k := range []int{1, 2, 5} {
+				if top/(k*pow) <= 8 {
+					return k * pow
+				}
+			}
+		}
+	}()
+
+	var all []int
+	for v := 0; v <= top; v += step {
+		all = append(all, v)
+	}
+	return all
+}
+
+// text alignments
+const (
+	START = iota
+	MIDDLE
+	END
+)
+
+// drawer is the common ground of the svg and png renderers
+type drawer interface {
+	rect(x, y, w, h int)
+	line(x0, y0, x1, y1 int)
+	text(x, y int, s string, align int) // y is the text baseline
+}
+
+// draw lays the chart out on d
+func (c chart) draw(d drawer) {
+	bottom := PADT + len(c.bars)*ROW
+
+	// axes
+	d.line(PADL, PADT, PADL, bottom)
+	d.line(PADL, bottom, WIDTH-PADR, bottom)
+
+	for i, b := range c.bars {
+		y := PADT + i*ROW + (ROW-BAR)/2
+		w := c.x(b.value) - PADL
+
+		d.rect(PADL, y, w, BAR)
+		d.text(PADL-GAP, y+BAR/2+5, b.label, END)
+		d.text(PADL+w+GAP, y+BAR/2+5, strconv.Itoa(b.value), START)
+	}
+
+	for _, v := range c.ticks() {
+		x := c.x(v)
+		d.line(x, bottom, x, bottom+TICK)
+		d.text(x, bottom+TICK+17, strconv.Itoa(v), MIDDLE)
+	}
+
+	d.text((PADL+WIDTH-PADR)/2, bottom+PADB-8, c.xlabel, MIDDLE)
+	d.text(PADL, PADT-16, c.ylabel, MIDDLE)
+}
+
+// svg renders the chart on a transparent background
+func (c chart) svg(w io.Writer) error {
+	var d svgDrawer
+
+	fmt.Fprintf(&d.sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n",
+		WIDTH, c.height(), WIDTH, c.height())
+	fmt.Fprintf(&d.sb, `<g fill="%s" stroke="none" font-family="sans-serif" font-size="14">`+"\n", rgb(gray))
+	c.draw(&d)
+	d.sb.WriteString("</g>\n</svg>\n")
+
+	_, err := io.WriteString(w, d.sb.String())
+	return err
+}
+
+type svgDrawer struct {
+	sb strings.Builder
+}
+
+func (d *svgDrawer) rect(x, y, w, h int) {
+	fmt.Fprintf(&d.sb, `<rect x="%d" y="%d" width="%d" height="%d"/>`+"\n", x, y, w, h)
+}
+
+func (d *svgDrawer) line(x0, y0, x1, y1 int) {
+	fmt.Fprintf(&d.sb, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="%s"/>`+"\n", x0, y0, x1, y1, rgb(gray))
+}
+
+func (d *svgDrawer) text(x, y int, s string, align int) {
+	anchor := [...]string{START: "start", MIDDLE: "middle", END: "end"}[align]
+	fmt.Fprintf(&d.sb, `<text x="%d" y="%d" text-anchor="%s">%s</text>`+"\n", x, y, anchor, html.EscapeString(s))
+}
+
+func rgb(c color.NRGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// png renders the chart on a transparent background with the built-in font
+func (c chart) png(w io.Writer) error {
+	d := pngDrawer{image.NewNRGBA(image.Rect(0, 0, WIDTH, c.height()))}
+	c.draw(d)
+	return png.Encode(w, d.img)
+}
+
+type pngDrawer struct {
+	img *image.NRGBA
+}
+
+func (d pngDrawer) rect(x, y, w, h int) {
+	for j := y; j < y+h; j++ {
+		for i := x; i < x+w; i++ {
+			d.img.SetNRGBA(i, j, gray)
+		}
+	}
+}
+
+func (d pngDrawer) line(x0, y0, x1, y1 int) { // axis aligned only
+	d.rect(min(x0, x1), min(y0, y1), max(x1-x0, x0-x1)+1, max(y1-y0, y0-y1)+1)
+}
+
+// font scale, glyphs are 5x7 and advance by 6
+const SCALE = 2
+
+func (d pngDrawer) text(x, y int, s string, align int) {
+	runes := []rune(s)
+	width := (6*len(runes) - 1) * SCALE
+
+	switch align {
+	case MIDDLE:
+		x -= width / 2
+	case END:
+		x -= width
+	}
+	y -= 7 * SCALE // baseline to top
+
+	for _, r := range runes {
+		g, ok := font[r]
+		if !ok {
+			g = font[0]
+		}
+
+		for j, row := range g {
+			for i := range 5 {
+				if row&(0b10000>>i) != 0 {
+					d.rect(x+i*SCALE, y+j*SCALE, SCALE, SCALE)
+				}
+			}
+		}
+		x += 6 * SCALE
+	}
+}
+
+// font is a tiny 5x7 bitmap font covering the chart labels, 0 is the
+// replacement glyph
+var font = map[rune][7]uint8{
+	0:   {0b11111, 0b10001, 0b10001, 0b10001, 0b10001, 0b10001, 0b11111},
+	' ': {},
+	'0': {0b01110, 0b10001, 0b10011, 0b10101, 0b11001, 0b10001, 0b01110},
+	'1': {0b00100, 0b01100, 0b00100, 0b00100, 0b00100, 0b00100, 0b01110},
+	'2': {0b01110, 0b10001, 0b00001, 0b00010, 0b00100, 0b01000, 0b11111},
+	'3': {0b11111, 0b00010, 0b00100, 0b00010, 0b00001, 0b10001, 0b01110},
+	'4': {0b00010, 0b00110, 0b01010, 0b10010, 0b11111, 0b00010, 0b00010},
+	'5': {0b11111, 0b10000, 0b11110, 0b00001, 0b00001, 0b10001, 0b01110},
+	'6': {0b00110, 0b01000, 0b10000, 0b11110, 0b10001, 0b10001, 0b01110},
+	'7': {0b11111, 0b00001, 0b00010, 0b00100, 0b01000, 0b01000, 0b01000},
+	'8': {0b01110, 0b10001, 0b10001, 0b01110, 0b10001, 0b10001, 0b01110},
+	'9': {0b01110, 0b10001, 0b10001, 0b01111, 0b00001, 0b00010, 0b01100},
+	'.': {0, 0, 0, 0, 0, 0b01100, 0b01100},
+	',': {0, 0, 0, 0, 0b01100, 0b00100, 0b01000},
+	'(': {0b00010, 0b00100, 0b01000, 0b01000, 0b01000, 0b00100, 0b00010},
+	')': {0b01000, 0b00100, 0b00010, 0b00010, 0b00010, 0b00100, 0b01000},
+	'-': {0, 0, 0, 0b11111, 0, 0, 0},
+	'%': {0b11000, 0b11001, 0b00010, 0b00100, 0b01000, 0b10011, 0b00011},
+	'μ': {0, 0, 0b10001, 0b10001, 0b10011, 0b11101, 0b10000},
+	'D': {0b11100, 0b10010, 0b10001, 0b10001, 0b10001, 0b10010, 0b11100},
+	'T': {0b11111, 0b00100, 0b00100, 0b00100, 0b00100, 0b00100, 0b00100},
+	'Y': {0b10001, 0b10001, 0b01010, 0b00100, 0b00100, 0b00100, 0b00100},
+	'a': {0, 0, 0b01110, 0b00001, 0b01111, 0b10001, 0b01111},
+	'e': {0, 0, 0b01110, 0b10001, 0b11111, 0b10000, 0b01110},
+	'i': {0b00100, 0, 0b01100, 0b00100, 0b00100, 0b00100, 0b01110},
+	'm': {0, 0, 0b11010, 0b10101, 0b10101, 0b10001, 0b10001},
+	'r': {0, 0, 0b10110, 0b11001, 0b10000, 0b10000, 0b10000},
+	's': {0, 0, 0b01110, 0b10000, 0b01110, 0b00001, 0b11110},
+	'y': {0, 0, 0b10001, 0b10001, 0b01111, 0b00001, 0b01110},
+}
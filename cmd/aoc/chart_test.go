@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"image/png"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestTicks(t *testing.T) {
+	tests := []struct {
+		top      int
+		expected []int
+	}{
+		{8, []int{0, 1, 2, 3, 4, 5, 6, 7, 8}},
+		{9, []int{0, 2, 4, 6, 8}},
+		{863, []int{0, 100, 200, 300, 400, 500, 600, 700, 800}},
+		{11200, []int{0, 2000, 4000, 6000, 8000, 10000}},
+	}
+
+	for _, tt := range tests {
+		c := newChart("", "", []bar{{"1", tt.top}})
+		if ticks := c.ticks(); !slices.Equal(ticks, tt.expected) {
+			t.Errorf("ticks(%d) expected %v, got %v", tt.top, tt.expected, ticks)
+		}
+	}
+}
+
+func TestChart(t *testing.T) {
+	c := newChart("time (μs)", "day", []bar{{"1", 124}, {"2", 8}, {"3", 94}})
+
+	if c.bars[0].label != "2" || c.bars[2].label != "1" {
+		t.Errorf("expected bars by increasing value, got %v", c.bars)
+	}
+
+	var svg, again bytes.Buffer
+	if err := c.svg(&svg); err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(svg.String(), "<rect"); n != len(c.bars) {
+		t.Errorf("expected %d bars, got %d", len(c.bars), n)
+	}
+
+	var img bytes.Buffer
+	if err := c.png(&img); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.png(&again); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(img.Bytes(), again.Bytes()) {
+		t.Error("png renders differ")
+	}
+
+	m, err := png.Decode(&img)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := m.Bounds(); b.Dx() != WIDTH || b.Dy() != c.height() {
+		t.Errorf("expected %dx%d, got %v", WIDTH, c.height(), b)
+	}
+
+	y := PADT + ROW/2 // first bar
+	if r, g, b, a := m.At(c.x(c.bars[0].value)-1, y).RGBA(); r>>8 != 0x80 || g != r || b != r || a>>8 != 0xff {
+		t.Errorf("expected a gray bar pixel, got %v", m.At(PADL, y))
+	}
+}
+
+func TestCharts(t *testing.T) {
+	root := t.TempDir()
+	for year, data := range map[string]string{
+		"2023": `[{"year": 2023, "day": 1, "min_ns": 700000}, {"year": 2023, "day": 17, "min_ns": 11200000}]`,
+		"2025": `[{"year": 2025, "day": 2, "min_ns": 8000}]`,
+	} {
+		dir := filepath.Join(root, year)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, RUNTIME+".json"), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ostat := stat
+	defer func() { stat = ostat }()
+	stat = "min"
+
+	s := setup{dir: root}
+	if err := s.resolve(); err != nil {
+		t.Fatal(err)
+	}
+	if err := charts(&s, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{
+		filepath.Join("2023", IMAGES, BARCHART+".svg"),
+		filepath.Join("2025", IMAGES, BARCHART+".png"),
+		filepath.Join(IMAGES, YEARS+".svg"),
+		filepath.Join(IMAGES, YEARS+".png"),
+	} {
+		if _, err := os.Stat(filepath.Join(root, name)); err != nil {
+			t.Error(err)
+		}
+	}
+}
@@ -0,0 +1,306 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	INPUT = "input.txt"
+	URL   = "%s/%d/day/%d/input"
+	AGENT = LINK + "/tree/main/cmd/aoc by " + MAIL
+
+	BACKOFF  = time.Second     // first retry delay, doubled on each retry
+	GRACE    = 2 * time.Second // after unlock, the server clock may lag
+	THROTTLE = time.Second     // between bulk requests
+)
+
+var (
+	client http.Client
+
+	site = "https://adventofcode.com" // overridden by tests
+
+	// clock, overridden by tests
+	now   = time.Now
+	sleep = time.Sleep
+)
+
+var (
+	ErrLocked  = errors.New("puzzle not unlocked yet")
+	ErrSession = errors.New("session rejected, please log in again")
+)
+
+var (
+	all     bool // see -all
+	wait    bool // see -wait
+	retries int  // see -retries
+)
+
+func downloadFlags(fs *flag.FlagSet) {
+	fs.BoolVar(&all, "all", false, "download all unlocked days of the year")
+	fs.BoolVar(&wait, "wait", false, "wait for the puzzle to unlock")
+	fs.IntVar(&retries, "retries", 3, "retry count on transient failures")
+}
+
+func init() {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		panic(err)
+	}
+	client = http.Client{
+		Jar:     jar,
+		Timeout: 30 * time.Second,
+	}
+}
+
+// download fetches the selected day input into its directory, with -all it
+// fetches every unlocked day of the year, the inputs already in the cache
+// are not requested again so that an interrupted bulk download resumes
+func download(s *setup, _ []string) error {
+	session := os.Getenv("SESSION")
+	if len(session) == 0 {
+		warnf("$SESSION not set")
+		return nil
+	}
+
+	c, err := openCache()
+	if err != nil {
+		return err
+	}
+
+	if !all {
+		dir, err := s.dayDir()
+		if err != nil {
+			return err
+		}
+		return downloadDay(c, s.Year, s.Day, dir, session)
+	}
+
+	ydir, err := s.yearDir()
+	if err != nil {
+		return err
+	}
+
+	for day := 1; day <= days(s.Year); day++ {
+		if now().Before(unlock(s.Year, day)) {
+			warnf("stopping at %d-%d: %v", s.Year, day, ErrLocked)
+			break
+		}
+
+		cached := c.has(s.Year, day, session)
+
+		dir := filepath.Join(ydir, fmt.Sprint(day))
+		if _, err := os.Stat(dir); err != nil {
+			dir = "" // cache only
+		}
+
+		if err := downloadDay(c, s.Year, day, dir, session); err != nil {
+			return err
+		}
+
+		if !cached {
+			sleep(THROTTLE)
+		}
+	}
+
+	return nil
+}
+
+// downloadDay makes sure the day input is in the cache and, if dir is
+// given, in the day directory
+func downloadDay(c cache, year, day int, dir, session string) error {
+	out := filepath.Join(dir, INPUT)
+	if dir != "" {
+		if _, err := os.Stat(out); !errors.Is(err, os.ErrNotExist) {
+			warnf("input file %s already exists", out)
+			return nil
+		}
+	}
+
+	data, ok := c.get(year, day, session)
+	if !ok {
+		var err error
+
+		url := fmt.Sprintf(URL, site, year, day)
+		warnf("downloading from %s in session %s...", url, session[:min(6, len(session))])
+
+		if data, err = fetchInput(year, day, url, session); err != nil {
+			return fmt.Errorf("error downloading %d-%d input: %w", year, day, err)
+		}
+
+		if err := c.put(year, day, session, data); err != nil {
+			return err
+		}
+	}
+
+	if dir == "" {
+		return nil
+	}
+	return writeFile(out, data)
+}
+
+// fetchInput waits for the puzzle to unlock (see -wait) and gets its input,
+// retrying with backoff on transient failures
+func fetchInput(year, day int, url, session string) ([]byte, error) {
+	t := unlock(year, day)
+	if δ := t.Sub(now()); δ > 0 {
+		if !wait {
+			return nil, fmt.Errorf("%w: unlocks at %v, in %v (see -wait)", ErrLocked, t.Local(), δ.Round(time.Second))
+		}
+
+		warnf("waiting %v for %d-%d to unlock...", δ.Round(time.Second), year, day)
+		sleep(δ + GRACE)
+	}
+
+	var (
+		data []byte
+		err  error
+	)
+
+	delay := BACKOFF
+	for try := 0; ; try++ {
+		if data, err = getInput(url, session); err == nil {
+			return data, nil
+		}
+
+		if try >= retries || !transient(err, t) {
+			return nil, err
+		}
+
+		warnf("%v, retrying in %v...", err, delay)
+		sleep(delay)
+		delay *= 2
+	}
+}
+
+// statusError is a failed request, it names the status and the page text
+type statusError struct {
+	code int
+	msg  string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("%d %s: %s", e.code, http.StatusText(e.code), e.msg)
+}
+
+// transient tells if a failure is worth retrying: server errors, rate
+// limiting and a lock shortly after the unlock time, ie. clock skew
+func transient(err error, unlocked time.Time) bool {
+	var se *statusError
+
+	switch {
+	case errors.Is(err, ErrSession):
+		return false
+	case errors.Is(err, ErrLocked):
+		return now().Sub(unlocked) < time.Minute
+	case errors.As(err, &se):
+		return se.code == http.StatusTooManyRequests || se.code >= 500
+	}
+
+	return true // network errors
+}
+
+// getInput requests an input and checks the reply
+func getInput(url, session string) ([]byte, error) {
+	req, err := newRequest("GET", url, session, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading reply: %w", err)
+	}
+
+	return data, checkInput(resp.StatusCode, data)
+}
+
+// checkInput rejects the pages the server sends in place of an input
+func checkInput(code int, data []byte) error {
+	msg := string(bytes.TrimSpace(data))
+	msg = msg[:min(len(msg), 120)]
+
+	switch {
+	case strings.Contains(msg, "Please log in"):
+		return fmt.Errorf("%w: %d %s", ErrSession, code, msg)
+	case strings.Contains(msg, "before it unlocks"), code == http.StatusNotFound:
+		return fmt.Errorf("%w: %d %s", ErrLocked, code, msg)
+	case code != http.StatusOK:
+		return &statusError{code, msg}
+	case len(msg) == 0:
+		return &statusError{code, "empty input"}
+	case strings.HasPrefix(msg, "<!DOCTYPE"), strings.HasPrefix(msg, "<html"):
+		return &statusError{code, "html page in place of input"}
+	}
+
+	return nil
+}
+
+// newRequest builds a request with the session cookie, if any, and the
+// user agent advent of code asks automated tools for
+func newRequest(method, url, session string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return nil, fmt.Errorf("error building %s request: %w", method, err)
+	}
+
+	req.Header.Set("User-Agent", AGENT)
+	if session != "" {
+		req.AddCookie(&http.Cookie{Name: "session", Value: session})
+	}
+
+	return req, nil
+}
+
+// unlock returns the time a puzzle unlocks: midnight EST (UTC-5)
+func unlock(year, day int) time.Time {
+	return time.Date(year, time.December, day, 5, 0, 0, 0, time.UTC)
+}
+
+// days returns the puzzle count of a year
+func days(year int) int {
+	if year >= 2025 {
+		return 12
+	}
+	return 25
+}
+
+// writeFile writes a file atomically so that a failure never leaves a
+// truncated file behind
+func writeFile(name string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(name), filepath.Base(name)+".*")
+	if err != nil {
+		return fmt.Errorf("error creating file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error while copying data: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), name)
+}
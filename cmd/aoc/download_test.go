@@ -0,0 +1,292 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCheckInput(t *testing.T) {
+	tests := []struct {
+		name   string
+		code   int
+		body   string
+		target error
+		ok     bool
+	}{
+		{"input", 200, "1abc2\ntreb7uchet\n", nil, true},
+		{"logged out", 400, "Puzzle inputs differ by user.  Please log in to get your puzzle input.\n", ErrSession, false},
+		{"locked", 404, "Please don't repeatedly request this endpoint before it unlocks! The calendar countdown is synchronized with the server time.\n", ErrLocked, false},
+		{"server error", 500, "Internal Server Error\n", nil, false},
+		{"empty", 200, "\n", nil, false},
+		{"html", 200, "<!DOCTYPE html>\n<html lang=\"en-us\">", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkInput(tt.code, []byte(tt.body))
+			if (err == nil) != tt.ok {
+				t.Fatalf("checkInput(%d, %q) = %v", tt.code, tt.body, err)
+			}
+
+			if tt.target != nil && !errors.Is(err, tt.target) {
+				t.Errorf("expected %v, got %v", tt.target, err)
+			}
+		})
+	}
+}
+
+// inputs is an input.txt stand-in, each day can fail a given number of
+// times with a given status first
+type inputs struct {
+	sync.Mutex
+
+	fails map[int]int // day -> failure count
+	code  int         // failure status
+	hits  map[int]int // day -> request count
+}
+
+func (in *inputs) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	in.Lock()
+	defer in.Unlock()
+
+	if r.UserAgent() != AGENT {
+		http.Error(w, "no user agent", http.StatusForbidden)
+		return
+	}
+
+	if c, err := r.Cookie("session"); err != nil || c.Value != "cafe" {
+		http.Error(w, "Puzzle inputs differ by user.  Please log in to get your puzzle input.", http.StatusBadRequest)
+		return
+	}
+
+	day, _ := strconv.Atoi(r.PathValue("day"))
+	in.hits[day]++
+
+	if in.fails[day] > 0 {
+		in.fails[day]--
+		http.Error(w, http.StatusText(in.code), in.code)
+		return
+	}
+
+	fmt.Fprintf(w, "input of day %d\n", day)
+}
+
+// stub serves in, isolates the cache and records sleeps instead of sleeping
+func stub(t *testing.T, in *inputs) *[]time.Duration {
+	t.Helper()
+
+	if in.hits == nil {
+		in.hits = make(map[int]int)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /{year}/day/{day}/input", in)
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	t.Setenv("SESSION", "cafe")
+	t.Setenv("AOC_CACHE", t.TempDir())
+
+	var slept []time.Duration
+
+	osite, onow, osleep := site, now, sleep
+	oall, owait, oretries := all, wait, retries
+	t.Cleanup(func() {
+		site, now, sleep = osite, onow, osleep
+		all, wait, retries = oall, owait, oretries
+	})
+
+	site = srv.URL
+	sleep = func(δ time.Duration) { slept = append(slept, δ) }
+	all, wait, retries = false, false, 3
+
+	return &slept
+}
+
+// dayTree makes a <root>/<year>/<day> tree and returns a setup for it
+func dayTree(t *testing.T, year int, days ...int) setup {
+	t.Helper()
+
+	root := t.TempDir()
+	for _, d := range days {
+		if err := os.MkdirAll(filepath.Join(root, fmt.Sprint(year), fmt.Sprint(d)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := setup{dir: filepath.Join(root, fmt.Sprint(year))}
+	if err := s.resolve(); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestDownloadRetries(t *testing.T) {
+	in := &inputs{fails: map[int]int{1: 2}, code: http.StatusBadGateway}
+	slept := stub(t, in)
+
+	s := dayTree(t, 2023, 1)
+	s.Day = 1
+
+	if err := download(&s, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(s.root, "2023", "1", INPUT))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "input of day 1\n" {
+		t.Errorf("unexpected input %q", data)
+	}
+
+	if len(*slept) != 2 || (*slept)[0] != BACKOFF || (*slept)[1] != 2*BACKOFF {
+		t.Errorf("expected backoff [%v %v], got %v", BACKOFF, 2*BACKOFF, *slept)
+	}
+}
+
+func TestDownloadFailures(t *testing.T) {
+	tests := []struct {
+		name    string
+		session string
+		code    int
+		target  error
+		hits    int
+	}{
+		{"logged out", "dead", 0, ErrSession, 0},
+		{"locked", "cafe", http.StatusNotFound, ErrLocked, 1},
+		{"server down", "cafe", http.StatusInternalServerError, nil, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := &inputs{fails: map[int]int{1: 100}, code: tt.code}
+			stub(t, in)
+			t.Setenv("SESSION", tt.session)
+
+			s := dayTree(t, 2023, 1)
+			s.Day = 1
+
+			err := download(&s, nil)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if tt.target != nil && !errors.Is(err, tt.target) {
+				t.Errorf("expected %v, got %v", tt.target, err)
+			}
+
+			if in.hits[1] != tt.hits {
+				t.Errorf("expected %d requests, got %d", tt.hits, in.hits[1])
+			}
+
+			if _, err := os.Stat(filepath.Join(s.root, "2023", "1", INPUT)); !errors.Is(err, os.ErrNotExist) {
+				t.Errorf("a failed download left %s behind", INPUT)
+			}
+		})
+	}
+}
+
+func TestDownloadUnlock(t *testing.T) {
+	in := &inputs{}
+	slept := stub(t, in)
+
+	t0 := unlock(2030, 1).Add(-time.Hour)
+	now = func() time.Time { return t0 }
+
+	s := dayTree(t, 2030, 1)
+	s.Day = 1
+
+	if err := download(&s, nil); !errors.Is(err, ErrLocked) {
+		t.Fatalf("expected %v, got %v", ErrLocked, err)
+	}
+	if in.hits[1] != 0 {
+		t.Errorf("locked puzzle was requested %d times", in.hits[1])
+	}
+
+	wait = true
+	if err := download(&s, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(*slept) != 1 || (*slept)[0] != time.Hour+GRACE {
+		t.Errorf("expected to wait %v, waited %v", time.Hour+GRACE, *slept)
+	}
+}
+
+func TestDownloadAllResumes(t *testing.T) {
+	in := &inputs{fails: map[int]int{5: 1}, code: http.StatusServiceUnavailable}
+	stub(t, in)
+	retries = 0
+
+	s := dayTree(t, 2023, 1, 2, 3)
+	all = true
+
+	if err := download(&s, nil); err == nil {
+		t.Fatal("expected the bulk download to stop on day 5")
+	}
+
+	if err := download(&s, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	for day := 1; day <= 25; day++ {
+		expected := 1
+		if day == 5 {
+			expected = 2
+		}
+
+		if in.hits[day] != expected {
+			t.Errorf("day %d: expected %d requests, got %d", day, expected, in.hits[day])
+		}
+	}
+
+	for day := 1; day <= 3; day++ {
+		data, err := os.ReadFile(filepath.Join(s.root, "2023", fmt.Sprint(day), INPUT))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := fmt.Sprintf("input of day %d\n", day); string(data) != expected {
+			t.Errorf("day %d: expected %q, got %q", day, expected, data)
+		}
+	}
+}
+
+func TestCache(t *testing.T) {
+	c := cache{t.TempDir()}
+
+	if c.has(2023, 1, "cafe") {
+		t.Fatal("empty cache hit")
+	}
+
+	if err := c.put(2023, 1, "cafe", []byte("input\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.put(2023, 2, "cafe", []byte("input\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	if data, ok := c.get(2023, 2, "cafe"); !ok || string(data) != "input\n" {
+		t.Errorf("expected a hit, got %q, %v", data, ok)
+	}
+
+	if c.has(2023, 1, "babe") {
+		t.Error("inputs must be keyed by session")
+	}
+
+	// corrupt the shared object
+	if err := os.WriteFile(c.object(digest([]byte("input\n"))), []byte("tampered\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if c.has(2023, 1, "cafe") {
+		t.Error("corrupted object must be a miss")
+	}
+}
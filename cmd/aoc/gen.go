@@ -0,0 +1,699 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/erik-adelbert/aoc/lib/num"
+)
+
+const (
+	GENINPUT   = "gen.txt"     // generated input, see -write
+	GENANSWERS = "gen.answers" // its oracle answers, one "part: answer" per line
+)
+
+var (
+	genSeed  uint64 // see -seed
+	genCount int    // see -n
+	genWrite bool   // see -write
+)
+
+func genFlags(fs *flag.FlagSet) {
+	fs.Uint64Var(&genSeed, "seed", 1, "first input seed")
+	fs.IntVar(&genCount, "n", 20, "number of seeded inputs to cross-check")
+	fs.BoolVar(&genWrite, "write", false, "write the -seed input and its answers as gen.txt and gen.answers")
+}
+
+// generator makes structurally valid inputs of a day from a seed and
+// answers them the slow, obviously right way
+type generator struct {
+	year, day int
+
+	input  func(*rand.Rand) string
+	oracle func(string) (part1, part2 int)
+}
+
+var generators = []generator{
+	{2021, 22, genReboot, bruteReboot},
+	{2022, 1, genCalories, bruteCalories},
+	{2022, 4, genSections, bruteSections},
+	{2022, 14, genRockPaths, bruteRockPaths},
+	{2023, 14, genPlatform, brutePlatform},
+	{2024, 1, genLists, bruteLists},
+	{2024, 2, genReports, bruteReports},
+	{2024, 9, genDiskMap, bruteDiskMap},
+	{2025, 5, genIDRanges, bruteIDRanges},
+}
+
+// newRand returns the generator source of a seed
+func newRand(seed uint64) *rand.Rand {
+	return rand.New(rand.NewPCG(seed, 2015))
+}
+
+// generate cross-checks the selected day, year or every generated day
+// solver against its oracle on -n seeded inputs, or writes the -seed input
+// and answers of the selected day next to its real ones
+func generate(s *setup, _ []string) error {
+	years, err := s.years()
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.MkdirTemp("", "aoc")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	var count [3]int
+	for _, g := range generators {
+		if !slices.Contains(years, g.year) || s.Day != 0 && g.day != s.Day {
+			continue
+		}
+
+		dir := filepath.Join(s.root, strconv.Itoa(g.year), strconv.Itoa(g.day))
+		if _, err := os.Stat(filepath.Join(dir, fmt.Sprintf(SRC, g.day))); errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+
+		if genWrite {
+			if err := writeGenerated(dir, g); err != nil {
+				return err
+			}
+			fmt.Printf("%d-%d: wrote seed %d %s and %s\n", g.year, g.day, genSeed, GENINPUT, GENANSWERS)
+			continue
+		}
+
+		o, msg := crossCheck(dir, g, tmp)
+		count[o]++
+
+		if msg != "" {
+			msg = ": " + msg
+		}
+		fmt.Printf("%d-%d: %v%s\n", g.year, g.day, o, msg)
+	}
+
+	if genWrite {
+		return nil
+	}
+
+	fmt.Printf("\n%d passed, %d failed, %d seeds each\n", count[PASS], count[FAIL], genCount)
+
+	if count[FAIL] > 0 {
+		return fmt.Errorf("%d days failed", count[FAIL])
+	}
+	return nil
+}
+
+// crossCheck builds a day solver and compares its answers with the oracle
+// ones on every seeded input, it stops at the first mismatch
+func crossCheck(dir string, g generator, tmp string) (outcome, string) {
+	bin, err := build(dir, g.day, tmp)
+	if err != nil {
+		return FAIL, err.Error()
+	}
+
+	input := filepath.Join(tmp, fmt.Sprintf("gen%d-%d.txt", g.year, g.day))
+	for seed := genSeed; seed < genSeed+uint64(genCount); seed++ {
+		text := g.input(newRand(seed))
+		if err := os.WriteFile(input, []byte(text), 0o644); err != nil {
+			return FAIL, err.Error()
+		}
+
+		out, err := solve(dir, bin, input)
+		if err != nil {
+			return FAIL, fmt.Sprintf("seed %d: %v", seed, err)
+		}
+
+		p1, p2 := g.oracle(text)
+		known := [3]string{"", strconv.Itoa(p1), strconv.Itoa(p2)}
+		if got := normalize(out); !match(got, known) {
+			return FAIL, fmt.Sprintf("seed %d: expected \"%d %d\", got %q, see aoc gen -write -seed %d", seed, p1, p2, strings.Join(got, " "), seed)
+		}
+	}
+	return PASS, ""
+}
+
+// writeGenerated writes a day seeded input and its answers as gen.txt and
+// gen.answers, away from its real input and answers
+func writeGenerated(dir string, g generator) error {
+	text := g.input(newRand(genSeed))
+	p1, p2 := g.oracle(text)
+
+	if err := os.WriteFile(filepath.Join(dir, GENINPUT), []byte(text), 0o644); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, GENANSWERS), fmt.Appendf(nil, "1: %d\n2: %d\n", p1, p2), 0o644)
+}
+
+// 2021 day 22: reboot steps small enough to light cube by cube
+func genReboot(r *rand.Rand) string {
+	var sb strings.Builder
+	for i := range 10 + r.IntN(30) {
+		state := "on"
+		if i > 0 && r.IntN(3) == 0 {
+			state = "off"
+		}
+		sb.WriteString(state)
+
+		for j, axis := range "xyz" {
+			lo := -60 + r.IntN(100)
+			sep := ','
+			if j == 0 {
+				sep = ' '
+			}
+			fmt.Fprintf(&sb, "%c%c=%d..%d", sep, axis, lo, lo+r.IntN(30))
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+func bruteReboot(text string) (int, int) {
+	const OFF, SIZE = 60, 130
+
+	on := make([]bool, SIZE*SIZE*SIZE)
+	for _, line := range splitLines(text) {
+		var state string
+		var x0, x1, y0, y1, z0, z1 int
+		fmt.Sscanf(line, "%s x=%d..%d,y=%d..%d,z=%d..%d", &state, &x0, &x1, &y0, &y1, &z0, &z1)
+
+		for x := x0; x <= x1; x++ {
+			for y := y0; y <= y1; y++ {
+				for z := z0; z <= z1; z++ {
+					on[((x+OFF)*SIZE+y+OFF)*SIZE+z+OFF] = state == "on"
+				}
+			}
+		}
+	}
+
+	var n1, n2 int
+	for i, lit := range on {
+		if !lit {
+			continue
+		}
+		n2++
+
+		x, y, z := i/(SIZE*SIZE)-OFF, i/SIZE%SIZE-OFF, i%SIZE-OFF
+		if max(num.Abs(x), num.Abs(y), num.Abs(z)) <= 50 {
+			n1++
+		}
+	}
+	return n1, n2
+}
+
+// 2022 day 1: elves calories
+func genCalories(r *rand.Rand) string {
+	var sb strings.Builder
+	for i := range 5 + r.IntN(250) {
+		if i > 0 {
+			sb.WriteByte('\n')
+		}
+		for range 1 + r.IntN(14) {
+			fmt.Fprintf(&sb, "%d\n", 1000+r.IntN(59000))
+		}
+	}
+	return sb.String()
+}
+
+func bruteCalories(text string) (int, int) {
+	var sums []int
+	for _, elf := range strings.Split(text, "\n\n") {
+		sum := 0
+		for _, line := range splitLines(elf) {
+			n, _ := strconv.Atoi(line)
+			sum += n
+		}
+		sums = append(sums, sum)
+	}
+
+	slices.Sort(sums)
+	slices.Reverse(sums)
+	return sums[0], sums[0] + sums[1] + sums[2]
+}
+
+// 2022 day 4: section assignment pairs
+func genSections(r *rand.Rand) string {
+	var sb strings.Builder
+	for range 50 + r.IntN(950) {
+		a, c := 1+r.IntN(99), 1+r.IntN(99)
+		fmt.Fprintf(&sb, "%d-%d,%d-%d\n", a, a+r.IntN(100-a), c, c+r.IntN(100-c))
+	}
+	return sb.String()
+}
+
+func bruteSections(text string) (int, int) {
+	var n1, n2 int
+	for _, line := range splitLines(text) {
+		var a, b, c, d int
+		fmt.Sscanf(line, "%d-%d,%d-%d", &a, &b, &c, &d)
+
+		var both, first, second int
+		for i := 1; i < 100; i++ {
+			x, y := a <= i && i <= b, c <= i && i <= d
+			if x {
+				first++
+			}
+			if y {
+				second++
+			}
+			if x && y {
+				both++
+			}
+		}
+
+		if both == first || both == second {
+			n1++
+		}
+		if both > 0 {
+			n2++
+		}
+	}
+	return n1, n2
+}
+
+// 2022 day 14: rock paths under the sand source, sand always ends up
+// flowing into the abyss without a floor
+func genRockPaths(r *rand.Rand) string {
+	for {
+		var sb strings.Builder
+		for range 5 + r.IntN(30) {
+			p := [2]int{460 + r.IntN(81), 3 + r.IntN(68)}
+			fmt.Fprintf(&sb, "%d,%d", p[0], p[1])
+
+			axis := r.IntN(2)
+			for range 1 + r.IntN(4) {
+				lo, hi := [2]int{460, 3}[axis], [2]int{540, 70}[axis]
+
+				δ := 1 + r.IntN(8)
+				if r.IntN(2) == 0 {
+					δ = -δ
+				}
+				if p[axis]+δ < lo || p[axis]+δ > hi {
+					δ = -δ
+				}
+				p[axis] += δ
+
+				fmt.Fprintf(&sb, " -> %d,%d", p[0], p[1])
+				axis = 1 - axis
+			}
+			sb.WriteByte('\n')
+		}
+
+		text := sb.String()
+		if rock, depth := scanRocks(text); pour(rock, depth, false) >= 0 {
+			return text
+		}
+	}
+}
+
+func bruteRockPaths(text string) (int, int) {
+	rock, depth := scanRocks(text)
+	return pour(rock, depth, false), pour(rock, depth, true)
+}
+
+// scanRocks draws rock paths and returns their depth
+func scanRocks(text string) (rock map[[2]int]bool, depth int) {
+	rock = make(map[[2]int]bool)
+	for _, line := range splitLines(text) {
+		var path [][2]int
+		for _, xy := range strings.Split(line, " -> ") {
+			var p [2]int
+			fmt.Sscanf(xy, "%d,%d", &p[0], &p[1])
+			path = append(path, p)
+			depth = max(depth, p[1])
+		}
+
+		for i := 1; i < len(path); i++ {
+			a, b := path[i-1], path[i]
+			for x := min(a[0], b[0]); x <= max(a[0], b[0]); x++ {
+				for y := min(a[1], b[1]); y <= max(a[1], b[1]); y++ {
+					rock[[2]int{x, y}] = true
+				}
+			}
+		}
+	}
+	return
+}
+
+// pour counts the grains at rest when one falls into the abyss or, with a
+// floor, when the source is blocked, it is -1 when the source is blocked
+// without a floor
+func pour(rock map[[2]int]bool, depth int, floor bool) int {
+	full := maps.Clone(rock)
+	for n := 0; ; n++ {
+		x, y := 500, 0
+		switch {
+		case full[[2]int{x, y}] && floor:
+			return n
+		case full[[2]int{x, y}]:
+			return -1
+		}
+
+	FALL:
+		for {
+			switch {
+			case !floor && y > depth:
+				return n
+			case floor && y+1 == depth+2:
+				break FALL
+			}
+
+			for _, δ := range []int{0, -1, 1} {
+				if !full[[2]int{x + δ, y + 1}] {
+					x, y = x+δ, y+1
+					continue FALL
+				}
+			}
+			break
+		}
+		full[[2]int{x, y}] = true
+	}
+}
+
+// 2023 day 14: square rock platforms
+func genPlatform(r *rand.Rand) string {
+	n := 5 + r.IntN(96)
+
+	var sb strings.Builder
+	for range n {
+		for range n {
+			switch p := r.IntN(100); {
+			case p < 15:
+				sb.WriteByte('#')
+			case p < 35:
+				sb.WriteByte('O')
+			default:
+				sb.WriteByte('.')
+			}
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+func brutePlatform(text string) (int, int) {
+	var grid [][]byte
+	for _, line := range splitLines(text) {
+		grid = append(grid, []byte(line))
+	}
+	n := len(grid)
+
+	north := func() {
+		for c := range n {
+			free := 0
+			for r := range n {
+				switch grid[r][c] {
+				case '#':
+					free = r + 1
+				case 'O':
+					grid[r][c], grid[free][c] = '.', 'O'
+					free++
+				}
+			}
+		}
+	}
+
+	// clockwise makes west the new north
+	clockwise := func() {
+		rot := make([][]byte, n)
+		for r := range rot {
+			rot[r] = make([]byte, n)
+			for c := range rot[r] {
+				rot[r][c] = grid[n-1-c][r]
+			}
+		}
+		grid = rot
+	}
+
+	load := func() int {
+		sum := 0
+		for r, row := range grid {
+			sum += bytes.Count(row, []byte{'O'}) * (n - r)
+		}
+		return sum
+	}
+
+	cycle := func() {
+		for range 4 {
+			north()
+			clockwise()
+		}
+	}
+
+	north()
+	part1 := load()
+
+	const CYCLES = 1_000_000_000
+
+	seen := make(map[string]int)
+	for i := 0; i < CYCLES; i++ {
+		key := string(slices.Concat(grid...))
+		if j, ok := seen[key]; ok {
+			for range (CYCLES - i) % (i - j) {
+				cycle()
+			}
+			break
+		}
+		seen[key] = i
+		cycle()
+	}
+
+	return part1, load()
+}
+
+// 2024 day 1: location id lists, some right ids repeat left ones
+func genLists(r *rand.Rand) string {
+	left := make([]int, 100+r.IntN(900))
+	for i := range left {
+		left[i] = 10000 + r.IntN(90000)
+	}
+
+	var sb strings.Builder
+	for _, l := range left {
+		right := 10000 + r.IntN(90000)
+		if r.IntN(3) == 0 {
+			right = left[r.IntN(len(left))]
+		}
+		fmt.Fprintf(&sb, "%d   %d\n", l, right)
+	}
+	return sb.String()
+}
+
+func bruteLists(text string) (int, int) {
+	var left, right []int
+	for _, line := range splitLines(text) {
+		var l, r int
+		fmt.Sscanf(line, "%d %d", &l, &r)
+		left, right = append(left, l), append(right, r)
+	}
+
+	var dist, sim int
+	for _, l := range left {
+		for _, r := range right {
+			if l == r {
+				sim += l
+			}
+		}
+	}
+
+	slices.Sort(left)
+	slices.Sort(right)
+	for i := range left {
+		dist += num.Abs(left[i] - right[i])
+	}
+	return dist, sim
+}
+
+// 2024 day 2: level reports, mostly safe, some off by a step
+func genReports(r *rand.Rand) string {
+	var sb strings.Builder
+	for range 100 + r.IntN(900) {
+		v, sign := 10+r.IntN(80), 1-2*r.IntN(2)
+
+		fmt.Fprint(&sb, v)
+		for range 4 + r.IntN(4) {
+			δ := 1 + r.IntN(3)
+			if r.IntN(8) == 0 {
+				δ = []int{0, 4 + r.IntN(3), -1 - r.IntN(3)}[r.IntN(3)]
+			}
+			v = min(max(v+sign*δ, 1), 99)
+			fmt.Fprintf(&sb, " %d", v)
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+func bruteReports(text string) (int, int) {
+	safe := func(levels []int) bool {
+		up, down := true, true
+		for i := 1; i < len(levels); i++ {
+			δ := levels[i] - levels[i-1]
+			up = up && 1 <= δ && δ <= 3
+			down = down && -3 <= δ && δ <= -1
+		}
+		return up || down
+	}
+
+	var n1, n2 int
+	for _, line := range splitLines(text) {
+		var levels []int
+		for _, f := range strings.Fields(line) {
+			n, _ := strconv.Atoi(f)
+			levels = append(levels, n)
+		}
+
+		if safe(levels) {
+			n1++
+			n2++
+			continue
+		}
+		for i := range levels {
+			if safe(slices.Delete(slices.Clone(levels), i, i+1)) {
+				n2++
+				break
+			}
+		}
+	}
+	return n1, n2
+}
+
+// 2024 day 9: disk maps of non empty files with some free space
+func genDiskMap(r *rand.Rand) string {
+	n := 100 + r.IntN(1900)
+
+	gaps := make([]int, n-1)
+	for i := range gaps {
+		gaps[i] = r.IntN(10)
+	}
+	if slices.Max(gaps) == 0 {
+		gaps[0] = 1
+	}
+
+	var sb strings.Builder
+	for i := range n {
+		sb.WriteByte(byte('1' + r.IntN(9)))
+		if i < len(gaps) {
+			sb.WriteByte(byte('0' + gaps[i]))
+		}
+	}
+	sb.WriteByte('\n')
+	return sb.String()
+}
+
+func bruteDiskMap(text string) (int, int) {
+	var disk []int // file ids by block, -1 is free
+	for i, c := range strings.TrimSpace(text) {
+		id := -1
+		if i%2 == 0 {
+			id = i / 2
+		}
+		for range int(c - '0') {
+			disk = append(disk, id)
+		}
+	}
+
+	checksum := func(disk []int) int {
+		sum := 0
+		for i, id := range disk {
+			if id >= 0 {
+				sum += i * id
+			}
+		}
+		return sum
+	}
+
+	// part 1: move blocks one at a time to the leftmost free block
+	blocks := slices.Clone(disk)
+	for l, r := 0, len(blocks)-1; ; {
+		for l < r && blocks[l] >= 0 {
+			l++
+		}
+		for l < r && blocks[r] < 0 {
+			r--
+		}
+		if l >= r {
+			break
+		}
+		blocks[l], blocks[r] = blocks[r], blocks[l]
+	}
+
+	// part 2: move whole files, highest id first, to the leftmost span
+	// that holds them
+	files := slices.Clone(disk)
+	for id := slices.Max(files); id >= 0; id-- {
+		start := slices.Index(files, id)
+		size := 0
+		for start+size < len(files) && files[start+size] == id {
+			size++
+		}
+
+		for i, run := 0, 0; i < start; i++ {
+			if run = run + 1; files[i] >= 0 {
+				run = 0
+			}
+			if run == size {
+				for j := range size {
+					files[i-size+1+j], files[start+j] = id, -1
+				}
+				break
+			}
+		}
+	}
+
+	return checksum(blocks), checksum(files)
+}
+
+// 2025 day 5: fresh id ranges and available ids
+func genIDRanges(r *rand.Rand) string {
+	var sb strings.Builder
+	for range 10 + r.IntN(190) {
+		lo := 1 + r.IntN(5000)
+		fmt.Fprintf(&sb, "%d-%d\n", lo, lo+r.IntN(300))
+	}
+	sb.WriteByte('\n')
+	for range 10 + r.IntN(990) {
+		fmt.Fprintf(&sb, "%d\n", 1+r.IntN(5500))
+	}
+	return sb.String()
+}
+
+func bruteIDRanges(text string) (int, int) {
+	head, tail, _ := strings.Cut(text, "\n\n")
+
+	var fresh []bool
+	for _, line := range splitLines(head) {
+		var lo, hi int
+		fmt.Sscanf(line, "%d-%d", &lo, &hi)
+		if hi >= len(fresh) {
+			fresh = append(fresh, make([]bool, hi+1-len(fresh))...)
+		}
+		for id := lo; id <= hi; id++ {
+			fresh[id] = true
+		}
+	}
+
+	var n1, n2 int
+	for _, line := range splitLines(tail) {
+		id, _ := strconv.Atoi(line)
+		if id < len(fresh) && fresh[id] {
+			n1++
+		}
+	}
+	for _, ok := range fresh {
+		if ok {
+			n2++
+		}
+	}
+	return n1, n2
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+// oracleFor returns the generator of a day
+func oracleFor(t *testing.T, year, day int) generator {
+	i := slices.IndexFunc(generators, func(g generator) bool {
+		return g.year == year && g.day == day
+	})
+	if i < 0 {
+		t.Fatalf("no %d-%d generator", year, day)
+	}
+	return generators[i]
+}
+
+func TestOracles(t *testing.T) {
+	tests := []struct {
+		year, day    int
+		input        string
+		part1, part2 int
+	}{
+		{2021, 22, "on x=10..12,y=10..12,z=10..12\non x=11..13,y=11..13,z=11..13\noff x=9..11,y=9..11,z=9..11\non x=10..10,y=10..10,z=10..10\n", 39, 39},
+		{2022, 1, "1000\n2000\n3000\n\n4000\n\n5000\n6000\n\n7000\n8000\n9000\n\n10000\n", 24000, 45000},
+		{2022, 4, "2-4,6-8\n2-3,4-5\n5-7,7-9\n2-8,3-7\n6-6,4-6\n2-6,4-8\n", 2, 4},
+		{2022, 14, "498,4 -> 498,6 -> 496,6\n503,4 -> 502,4 -> 502,9 -> 494,9\n", 24, 93},
+		{2023, 14, "O....#....\nO.OO#....#\n.....##...\nOO.#O....O\n.O.....O#.\nO.#..O.#.#\n..O..#O..O\n.......O..\n#....###..\n#OO..#....\n", 136, 64},
+		{2024, 1, "3   4\n4   3\n2   5\n1   3\n3   9\n3   3\n", 11, 31},
+		{2024, 2, "7 6 4 2 1\n1 2 7 8 9\n9 7 6 2 1\n1 3 2 4 5\n8 6 4 4 1\n1 3 6 7 9\n", 2, 4},
+		{2024, 9, "2333133121414131402\n", 1928, 2858},
+		{2025, 5, "3-5\n10-14\n16-20\n12-18\n\n1\n5\n8\n11\n17\n32\n", 3, 14},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d-%d", tt.year, tt.day), func(t *testing.T) {
+			g := oracleFor(t, tt.year, tt.day)
+			if p1, p2 := g.oracle(tt.input); p1 != tt.part1 || p2 != tt.part2 {
+				t.Errorf("oracle() = %d, %d, expected %d, %d", p1, p2, tt.part1, tt.part2)
+			}
+		})
+	}
+}
+
+// TestGenerators checks the generated inputs are reproducible and follow
+// the day input.grammar
+func TestGenerators(t *testing.T) {
+	root := filepath.Join("..", "..")
+
+	for _, g := range generators {
+		t.Run(fmt.Sprintf("%d-%d", g.year, g.day), func(t *testing.T) {
+			grammar, err := readGrammar(filepath.Join(root, strconv.Itoa(g.year), strconv.Itoa(g.day), GRAMMAR))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for seed := uint64(1); seed <= 5; seed++ {
+				text := g.input(newRand(seed))
+				if again := g.input(newRand(seed)); again != text {
+					t.Fatalf("seed %d generates different inputs", seed)
+				}
+				if err := grammar.check([]byte(text)); err != nil {
+					t.Errorf("seed %d: %v", seed, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,382 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const (
+	SRC = "aoc%d.go"
+	OUT = "aoc%d_header.go"
+
+	AUTH = "Erik Adelbert"
+	MAIL = "erik_AT_adelbert_DOT_fr"
+	LINK = "https://github.com/erik-adelbert/aoc"
+)
+
+const HEADER = `// aoc{{.Day}}.go --
+// advent of code {{.Year}} day {{.Day}}
+//
+// https://adventofcode.com/{{.Year}}/day/{{.Day}}
+// {{.Link}}
+//
+// (ɔ) {{.Author}} - {{.Mail}}
+// -------------------------------------------
+// {{.Year}}-12-{{.Day}}: initial commit
+
+`
+
+type header struct {
+	Author, Mail, Link string
+	Year, Day          int
+}
+
+// addHeader prepends the house header to the selected day source
+func addHeader(s *setup, _ []string) error {
+	dir, err := s.dayDir()
+	if err != nil {
+		return err
+	}
+
+	h := header{Year: s.Year, Day: s.Day}
+	h.getenv()
+
+	name := fmt.Sprintf(SRC, h.Day)
+	src := filepath.Join(dir, name)
+
+	var info fs.FileInfo
+	if info, err = os.Stat(src); err != nil {
+		return err
+	}
+
+	var data []byte
+	if data, err = os.ReadFile(src); err != nil {
+		return err
+	}
+
+	if bytes.HasPrefix(data, []byte("// "+name)) {
+		warnf("aborting: %s has header", src)
+		return nil
+	}
+
+	var tmpl *template.Template
+	if tmpl, err = template.New("header").Parse(HEADER); err != nil {
+		panic(err)
+	}
+
+	buf := bytes.NewBuffer(make([]byte, 0, len(HEADER)+len(data)))
+
+	if err = tmpl.Execute(buf, h); err != nil { // write header
+		panic(err)
+	}
+
+	if _, err = buf.Write(data); err != nil {
+		panic(err)
+	}
+
+	out := filepath.Join(dir, fmt.Sprintf(OUT, h.Day))
+	data = append(bytes.TrimSpace(buf.Bytes()), '\n')
+	if err = os.WriteFile(out, data, info.Mode()); err != nil {
+		return err
+	}
+
+	return os.Rename(out, src)
+}
+
+func (h *header) getenv() {
+	const (
+		MAXAUTH = 127
+		MAXMAIL = 254
+		MAXLINK = 254
+
+		ENVAUTH = "AUTHOR"
+		ENVMAIL = "MAIL"
+		ENVLINK = "LINK"
+	)
+
+	valids := []struct {
+		s   *string
+		env string
+		max int
+		def string
+	}{
+		{&(h.Author), ENVAUTH, MAXAUTH, AUTH},
+		{&(h.Mail), ENVMAIL, MAXMAIL, MAIL},
+		{&(h.Link), ENVLINK, MAXLINK, LINK},
+	}
+
+	for _, field := range valids {
+		*field.s = os.Getenv(field.env)
+		if len(*field.s) == 0 || len(*field.s) >= field.max {
+			*field.s = field.def
+		}
+	}
+}
+
+var (
+	update string // see -update
+	check  bool   // see -check
+)
+
+func headerFlags(fs *flag.FlagSet) {
+	fs.StringVar(&update, "update", "", "append a dated changelog `message` and repair the header")
+	fs.BoolVar(&check, "check", false, "report missing or inconsistent headers of the year, or of all years")
+}
+
+// stamp adds, updates or checks headers, see -update and -check
+func stamp(s *setup, args []string) error {
+	switch {
+	case check:
+		return checkHeaders(s)
+	case update != "":
+		return updateHeader(s, update)
+	}
+	return addHeader(s, args)
+}
+
+// heading is a parsed header block
+type heading struct {
+	top  []string // lines above the changelog, separator included
+	log  []entry  // changelog
+	body []byte   // source below the header
+}
+
+// entry is a dated changelog entry
+type entry struct {
+	date  time.Time
+	msg   string
+	lines []string // continuation lines
+}
+
+func (e entry) String() string {
+	var sb strings.Builder
+
+	y, m, d := e.date.Date()
+	fmt.Fprintf(&sb, "// %d-%d-%d: %s", y, int(m), d, e.msg)
+	for _, l := range e.lines {
+		sb.WriteString("\n" + l)
+	}
+
+	return sb.String()
+}
+
+const (
+	TOP = 8 // header lines above the changelog
+	SEP = "// ---"
+)
+
+var dated = regexp.MustCompile(`^// (\d{4})-(\d{1,2})-(\d{1,2}): ?(.*)$`)
+
+// parseHeading splits a source into its header and body, it fails when the
+// source has no house header
+func parseHeading(data []byte) (heading, error) {
+	var h heading
+
+	if !bytes.HasPrefix(data, []byte("// aoc")) {
+		return h, errors.New("missing header")
+	}
+
+	rest := data
+	for len(rest) > 0 && bytes.HasPrefix(rest, []byte("//")) {
+		line, tail, _ := bytes.Cut(rest, []byte("\n"))
+		rest = tail
+
+		switch {
+		case len(h.top) < TOP:
+			h.top = append(h.top, string(line))
+		case dated.Match(line):
+			m := dated.FindSubmatch(line)
+			y, _ := strconv.Atoi(string(m[1]))
+			mo, _ := strconv.Atoi(string(m[2]))
+			d, _ := strconv.Atoi(string(m[3]))
+
+			date := time.Date(y, time.Month(mo), d, 0, 0, 0, 0, time.UTC)
+			if date.Month() != time.Month(mo) || date.Day() != d {
+				return h, fmt.Errorf("changelog line %q: bad date", line)
+			}
+
+			h.log = append(h.log, entry{date: date, msg: string(m[4])})
+		case len(h.log) > 0: // continuation
+			last := &h.log[len(h.log)-1]
+			last.lines = append(last.lines, string(line))
+		default:
+			return h, fmt.Errorf("changelog line %q: missing date", line)
+		}
+	}
+	h.body = rest
+
+	if len(h.top) < TOP || !strings.HasPrefix(h.top[TOP-1], SEP) {
+		return h, errors.New("malformed header")
+	}
+
+	return h, nil
+}
+
+// bytes renders the header back above the body
+func (h heading) bytes() []byte {
+	var buf bytes.Buffer
+
+	for _, l := range h.top {
+		buf.WriteString(l + "\n")
+	}
+	for _, e := range h.log {
+		buf.WriteString(e.String() + "\n")
+	}
+	buf.Write(h.body)
+
+	return buf.Bytes()
+}
+
+// expected returns the header lines set by year and day, indexed by line
+func expected(year, day int) map[int]string {
+	return map[int]string{
+		0: fmt.Sprintf("// aoc%d.go --", day),
+		1: fmt.Sprintf("// advent of code %d day %d", year, day),
+		3: fmt.Sprintf("// https://adventofcode.com/%d/day/%d", year, day),
+	}
+}
+
+// issues lists the header inconsistencies with year and day
+func (h heading) issues(year, day int) []string {
+	var all []string
+
+	for i, l := range expected(year, day) {
+		if h.top[i] != l {
+			all = append(all, fmt.Sprintf("line %d is %q, expected %q", i+1, h.top[i], l))
+		}
+	}
+
+	if len(h.log) == 0 {
+		all = append(all, "empty changelog")
+	}
+
+	t := unlock(year, day).Truncate(24 * time.Hour)
+	for i, e := range h.log {
+		if e.date.Before(t) {
+			all = append(all, fmt.Sprintf("changelog %q predates the puzzle", e.String()))
+		}
+
+		if i > 0 && e.date.Before(h.log[i-1].date) {
+			all = append(all, fmt.Sprintf("changelog %q is out of order", e.String()))
+		}
+	}
+
+	return all
+}
+
+// repair rewrites the header lines set by year and day, moves the initial
+// commit back to the puzzle day when it predates it and sorts the changelog
+func (h *heading) repair(year, day int) {
+	for i, l := range expected(year, day) {
+		h.top[i] = l
+	}
+
+	t := unlock(year, day).Truncate(24 * time.Hour)
+	for i := range h.log {
+		if h.log[i].date.Before(t) && h.log[i].msg == "initial commit" {
+			h.log[i].date = t
+		}
+	}
+
+	slices.SortStableFunc(h.log, func(a, b entry) int {
+		return a.date.Compare(b.date)
+	})
+}
+
+// updateHeader repairs the selected day header and appends a dated
+// changelog entry to it
+func updateHeader(s *setup, msg string) error {
+	dir, err := s.dayDir()
+	if err != nil {
+		return err
+	}
+
+	src := filepath.Join(dir, fmt.Sprintf(SRC, s.Day))
+
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+
+	h, err := parseHeading(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", src, err)
+	}
+
+	for _, issue := range h.issues(s.Year, s.Day) {
+		warnf("%s: repairing %s", src, issue)
+	}
+	h.repair(s.Year, s.Day)
+
+	y, m, d := now().Date()
+	h.log = append(h.log, entry{
+		date: time.Date(y, m, d, 0, 0, 0, 0, time.UTC),
+		msg:  strings.TrimSpace(msg),
+	})
+	h.repair(s.Year, s.Day) // keep order
+
+	if err := writeFile(src, h.bytes()); err != nil {
+		return err
+	}
+	return os.Chmod(src, info.Mode())
+}
+
+// checkHeaders reports the missing or inconsistent headers of the selected
+// year, or of every year, and fails when there is any
+func checkHeaders(s *setup) error {
+	years, err := s.years()
+	if err != nil {
+		return err
+	}
+
+	count, files := 0, 0
+	for _, year := range years {
+		for day := 1; day <= 25; day++ {
+			src := filepath.Join(s.root, strconv.Itoa(year), strconv.Itoa(day), fmt.Sprintf(SRC, day))
+
+			data, err := os.ReadFile(src)
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			files++
+
+			rel, _ := filepath.Rel(s.root, src)
+
+			h, err := parseHeading(data)
+			if err != nil {
+				fmt.Printf("%s: %v\n", rel, err)
+				count++
+				continue
+			}
+
+			for _, issue := range h.issues(year, day) {
+				fmt.Printf("%s: %s\n", rel, issue)
+				count++
+			}
+		}
+	}
+
+	if count > 0 {
+		return fmt.Errorf("%d header issues in %d files", count, files)
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const HEADED = `// aoc17.go --
+// advent of code 2022 day 17
+//
+// https://adventofcode.com/2022/day/17
+// https://github.com/erik-adelbert/aoc
+//
+// (ɔ) Erik Adelbert - erik_AT_adelbert_DOT_fr
+// -------------------------------------------
+// 2022-12-17: initial commit
+// 2024-1-5: adapt from:
+//   https://github.com/maneatingape/advent-of-code-rust
+// 2023-12-20: improve readability
+
+package main
+
+func main() {}
+`
+
+func TestParseHeading(t *testing.T) {
+	h, err := parseHeading([]byte(HEADED))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(h.top) != TOP || len(h.log) != 3 {
+		t.Fatalf("expected %d top lines and 3 entries, got %d and %d", TOP, len(h.top), len(h.log))
+	}
+
+	if e := h.log[1]; e.msg != "adapt from:" || len(e.lines) != 1 {
+		t.Errorf("continuation line not attached: %+v", e)
+	}
+
+	if string(h.bytes()) != HEADED {
+		t.Errorf("round trip mismatch:\n%s", h.bytes())
+	}
+
+	for _, bad := range []string{
+		"package main\n",
+		"// aoc1.go --\n// advent of code 2023 day 1\n\npackage main\n",
+		strings.Replace(HEADED, "2023-12-20", "2023-12-32", 1),
+	} {
+		if _, err := parseHeading([]byte(bad)); err == nil {
+			t.Errorf("expected an error for %q", bad[:20])
+		}
+	}
+}
+
+func TestIssuesAndRepair(t *testing.T) {
+	h, err := parseHeading([]byte(HEADED))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// title, url, predating initial commit and disordered entry
+	if issues := h.issues(2023, 17); len(issues) != 4 {
+		t.Errorf("expected 4 issues, got %d: %q", len(issues), issues)
+	}
+
+	h.repair(2023, 17)
+	if issues := h.issues(2023, 17); len(issues) != 0 {
+		t.Errorf("repair left issues: %q", issues)
+	}
+
+	expected := []string{
+		"// 2023-12-17: initial commit",
+		"// 2023-12-20: improve readability",
+		"// 2024-1-5: adapt from:\n//   https://github.com/maneatingape/advent-of-code-rust",
+	}
+	for i, e := range h.log {
+		if e.String() != expected[i] {
+			t.Errorf("entry %d: expected %q, got %q", i, expected[i], e.String())
+		}
+	}
+}
+
+func TestUpdateHeader(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "2023", "17")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	src := filepath.Join(dir, "aoc17.go")
+	if err := os.WriteFile(src, []byte(HEADED), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	onow := now
+	defer func() { now = onow }()
+	now = func() time.Time { return time.Date(2024, 1, 2, 12, 0, 0, 0, time.Local) }
+
+	s := setup{dir: dir}
+	if err := s.resolve(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := updateHeader(&s, "sweep line"); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `// aoc17.go --
+// advent of code 2023 day 17
+//
+// https://adventofcode.com/2023/day/17
+// https://github.com/erik-adelbert/aoc
+//
+// (ɔ) Erik Adelbert - erik_AT_adelbert_DOT_fr
+// -------------------------------------------
+// 2023-12-17: initial commit
+// 2023-12-20: improve readability
+// 2024-1-2: sweep line
+// 2024-1-5: adapt from:
+//   https://github.com/maneatingape/advent-of-code-rust
+
+package main
+
+func main() {}
+`
+	if string(data) != expected {
+		t.Errorf("unexpected update:\n%s", data)
+	}
+}
+
+func TestCheckHeaders(t *testing.T) {
+	root := t.TempDir()
+
+	files := map[string]string{
+		"2023/17/aoc17.go": HEADED,
+		"2023/3/aoc3.go":   "package main\n",
+		"2021/1/aoc1.go":   "// aoc1.go --\n// advent of code 2021 day 1\n//\n// https://adventofcode.com/2021/day/1\n// link\n//\n// (ɔ) me\n// ---\n// 2021-12-1: initial commit\n\npackage main\n",
+	}
+	for name, data := range files {
+		name = filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := setup{dir: filepath.Join(root, "2021")}
+	if err := s.resolve(); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkHeaders(&s); err != nil {
+		t.Errorf("2021 is consistent: %v", err)
+	}
+
+	s = setup{dir: root}
+	if err := s.resolve(); err != nil {
+		t.Fatal(err)
+	}
+	err := checkHeaders(&s)
+	if err == nil || !strings.HasPrefix(err.Error(), "5 header issues in 3 files") {
+		t.Errorf("expected 5 issues in 3 files, got %v", err)
+	}
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+const HISTORY = "history.jsonl" // one run per line, oldest first
+
+var (
+	threshold float64       // see -threshold
+	floor     time.Duration // see -floor
+	baseRef   string        // see -base
+	headRef   string        // see -head
+)
+
+func compareFlags(fs *flag.FlagSet) {
+	fs.Float64Var(&threshold, "threshold", 0.10, "median regression ratio to flag, eg. 0.10 for +10%")
+	fs.DurationVar(&floor, "floor", 10*time.Microsecond, "ignore median changes below this duration")
+	fs.StringVar(&baseRef, "base", "", "baseline commit prefix (default: previous comparable commit)")
+	fs.StringVar(&headRef, "head", "", "compared commit prefix (default: latest run)")
+}
+
+// run is a year benchmark run, it is keyed by commit, go version and
+// GOMAXPROCS
+type run struct {
+	Commit string    `json:"commit"`
+	Dirty  bool      `json:"dirty,omitempty"` // uncommitted changes
+	Go     string    `json:"go"`
+	Procs  int       `json:"gomaxprocs"`
+	Date   time.Time `json:"date"`
+	Stats  []stats   `json:"stats"`
+}
+
+func (r run) String() string {
+	commit := r.Commit[:min(len(r.Commit), 12)]
+	if r.Dirty {
+		commit += "+"
+	}
+	return fmt.Sprintf("%s/%s/%d", commit, r.Go, r.Procs)
+}
+
+// comparable tells if two runs share their environment
+func (r run) comparable(o run) bool {
+	return r.Go == o.Go && r.Procs == o.Procs
+}
+
+// newRun tags year stats with the dir commit and toolchain
+func newRun(dir string, all []stats) run {
+	r := run{
+		Commit: "unknown",
+		Go:     "unknown",
+		Procs:  runtime.GOMAXPROCS(0),
+		Date:   now().UTC().Truncate(time.Second),
+		Stats:  all,
+	}
+
+	output := func(name string, args ...string) (string, error) {
+		cmd := exec.Command(name, args...)
+		cmd.Dir = dir
+		out, err := cmd.Output()
+		return strings.TrimSpace(string(out)), err
+	}
+
+	if out, err := output("git", "rev-parse", "HEAD"); err == nil {
+		r.Commit = out
+	}
+	if out, err := output("git", "status", "--porcelain", "--untracked-files=no", "."); err == nil {
+		r.Dirty = out != ""
+	}
+	if out, err := output("go", "env", "GOVERSION"); err == nil {
+		r.Go = out
+	}
+
+	return r
+}
+
+// appendHistory appends a run to a history file
+func appendHistory(name string, r run) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	fd, err := os.OpenFile(name, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+
+	if _, err := fd.Write(append(data, '\n')); err != nil {
+		fd.Close()
+		return err
+	}
+	return fd.Close()
+}
+
+// readHistory reads a history file
+func readHistory(name string) ([]run, error) {
+	fd, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+
+	var hist []run
+
+	input := bufio.NewScanner(fd)
+	input.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for n := 1; input.Scan(); n++ {
+		if len(strings.TrimSpace(input.Text())) == 0 {
+			continue
+		}
+
+		var r run
+		if err := json.Unmarshal(input.Bytes(), &r); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", name, n, err)
+		}
+		hist = append(hist, r)
+	}
+
+	return hist, input.Err()
+}
+
+// pickRuns selects the compared runs, see -head and -base
+func pickRuns(hist []run, head, base string) (h, b run, err error) {
+	latest := func(upto int, ok func(run) bool) (int, bool) {
+		for i := upto - 1; i >= 0; i-- {
+			if ok(hist[i]) {
+				return i, true
+			}
+		}
+		return -1, false
+	}
+
+	prefix := func(ref string) func(run) bool {
+		return func(r run) bool { return strings.HasPrefix(r.Commit, ref) }
+	}
+
+	i, ok := latest(len(hist), prefix(head))
+	if !ok {
+		return h, b, fmt.Errorf("no run for commit %q", head)
+	}
+	h = hist[i]
+
+	var j int
+	if base != "" {
+		j, ok = latest(len(hist), prefix(base))
+	} else {
+		j, ok = latest(i, func(r run) bool {
+			return r.Commit != h.Commit && r.comparable(h)
+		})
+		if !ok {
+			j, ok = latest(i, func(r run) bool { return r.Commit != h.Commit })
+		}
+	}
+	if !ok {
+		return h, b, errors.New("no baseline run to compare with")
+	}
+	b = hist[j]
+
+	return h, b, nil
+}
+
+// regression is a day median change
+type regression struct {
+	day        int
+	base, head time.Duration
+	flagged    bool
+}
+
+// regressions compares the day medians of two runs
+func regressions(h, b run) []regression {
+	medians := make(map[int]time.Duration)
+	for _, s := range b.Stats {
+		medians[s.Day] = s.Median
+	}
+
+	var all []regression
+	for _, s := range h.Stats {
+		old, ok := medians[s.Day]
+		if !ok {
+			continue
+		}
+
+		δ := s.Median - old
+		all = append(all, regression{
+			day:     s.Day,
+			base:    old,
+			head:    s.Median,
+			flagged: δ > floor && float64(δ) > threshold*float64(old),
+		})
+	}
+
+	return all
+}
+
+// compare flags the days of the year whose median regressed between two
+// runs of the history, it fails when any did so that it can gate commits
+func compare(s *setup, _ []string) error {
+	dir, err := s.yearDir()
+	if err != nil {
+		return err
+	}
+
+	hist, err := readHistory(filepath.Join(dir, HISTORY))
+	if err != nil {
+		return err
+	}
+
+	h, b, err := pickRuns(hist, headRef, baseRef)
+	if err != nil {
+		return err
+	}
+
+	if !h.comparable(b) {
+		warnf("comparing runs from different environments: %v vs %v", h, b)
+	}
+
+	fmt.Printf("%d: %v vs %v\n\n", s.Year, h, b)
+	fmt.Println("| day | base | head | Δ |  |")
+	fmt.Println("|-----|-----:|-----:|--:|--|")
+
+	count := 0
+	for _, r := range regressions(h, b) {
+		mark := ""
+		if r.flagged {
+			mark = "regressed"
+			count++
+		}
+
+		Δ := 100 * (float64(r.head) - float64(r.base)) / float64(max(r.base, 1))
+		fmt.Printf("| %d | %v | %v | %+.1f%% | %s |\n", r.day, r.base.Round(time.Microsecond), r.head.Round(time.Microsecond), Δ, mark)
+	}
+
+	if count > 0 {
+		return fmt.Errorf("%d days regressed by more than %.0f%%", count, 100*threshold)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// medians makes a run whose day i+1 median is ms[i] ms
+func medians(commit, gover string, ms ...float64) run {
+	r := run{Commit: commit, Go: gover, Procs: 8}
+	for i, m := range ms {
+		δ := time.Duration(m * float64(time.Millisecond))
+		r.Stats = append(r.Stats, stats{Year: 2023, Day: i + 1, Median: δ})
+	}
+	return r
+}
+
+func TestHistory(t *testing.T) {
+	name := filepath.Join(t.TempDir(), HISTORY)
+
+	hist := []run{
+		medians("aaaa", "go1.24", 1, 2, 3),
+		medians("bbbb", "go1.25", 1, 2, 3),
+		medians("cccc", "go1.24", 1.05, 2.5, 0.5),
+	}
+	for _, r := range hist {
+		if err := appendHistory(name, r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	read, err := readHistory(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(read) != len(hist) || read[2].Stats[1].Median != 2500*time.Microsecond {
+		t.Fatalf("history round trip: %+v", read)
+	}
+
+	tests := []struct {
+		name, head, base string
+		h, b             string
+	}{
+		{"latest vs comparable", "", "", "cccc", "aaaa"},
+		{"explicit base", "", "bb", "cccc", "bbbb"},
+		{"explicit head", "bbbb", "", "bbbb", "aaaa"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, b, err := pickRuns(read, tt.head, tt.base)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if h.Commit != tt.h || b.Commit != tt.b {
+				t.Errorf("picked %s vs %s, expected %s vs %s", h.Commit, b.Commit, tt.h, tt.b)
+			}
+		})
+	}
+
+	if _, _, err := pickRuns(read[:1], "", ""); err == nil {
+		t.Error("a single run has no baseline")
+	}
+}
+
+func TestRegressions(t *testing.T) {
+	othreshold, ofloor := threshold, floor
+	defer func() { threshold, floor = othreshold, ofloor }()
+	threshold, floor = 0.10, 10*time.Microsecond
+
+	b := medians("aaaa", "go1.24", 1, 2, 3, 0.001)
+	h := medians("cccc", "go1.24", 1.05, 2.5, 0.5, 0.005, 9)
+
+	var flagged []int
+	for _, r := range regressions(h, b) {
+		if r.flagged {
+			flagged = append(flagged, r.day)
+		}
+	}
+
+	// day 1 is within threshold, day 4 within the noise floor and day 5 new
+	if len(flagged) != 1 || flagged[0] != 2 {
+		t.Errorf("expected day 2 flagged, got %v", flagged)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "2023")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	name := filepath.Join(dir, HISTORY)
+	for _, r := range []run{
+		medians("aaaa", "go1.24", 1, 2),
+		medians("bbbb", "go1.24", 1, 1.9),
+	} {
+		if err := appendHistory(name, r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := setup{dir: dir}
+	if err := s.resolve(); err != nil {
+		t.Fatal(err)
+	}
+
+	othreshold, ofloor, obase := threshold, floor, baseRef
+	defer func() { threshold, floor, baseRef = othreshold, ofloor, obase }()
+	threshold, floor = 0.10, 0
+
+	if err := compare(&s, nil); err != nil {
+		t.Errorf("no regression expected: %v", err)
+	}
+
+	if err := appendHistory(name, medians("cccc", "go1.24", 3, 1.9)); err != nil {
+		t.Fatal(err)
+	}
+
+	err := compare(&s, nil)
+	if err == nil || !strings.HasPrefix(err.Error(), "1 days regressed") {
+		t.Errorf("expected 1 regression, got %v", err)
+	}
+}
@@ -0,0 +1,208 @@
+// aoc is the cross-year companion tool of the daily solvers.
+//
+// usage:
+//
+//	aoc <command> [-year YYYY] [-day DD] [-dir path] [args...]
+//
+// Year and day default to the layout of -dir (the current directory by
+// default), ie. <root>/<year>/<day>, just like the former per-year tools.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+)
+
+const FIRST = 2015 // first advent of code edition
+
+// command is an aoc subcommand
+type command struct {
+	name, help string
+
+	flags func(*flag.FlagSet) // optional command flags
+	run   func(*setup, []string) error
+}
+
+var commands = []command{
+	{"bench", "time a day solver over many runs", benchFlags, bench},
+	{"chart", "draw the runtime bar charts in svg and png", chartFlags, charts},
+	{"compare", "flag the year days whose median regressed", compareFlags, compare},
+	{"download", "download a day input", downloadFlags, download},
+	{"gen", "cross-check day solvers against slow oracles on generated inputs", genFlags, generate},
+	{"header", "add, update or check the house header of day sources", headerFlags, stamp},
+	{"metrics", "report code lines, complexity, nesting and imports by day", metricsFlags, metrics},
+	{"migrate", "build days as packages of the root module so they can import lib", migrateFlags, migrate},
+	{"new", "scaffold a new day", nil, scaffold},
+	{"run", "run days in a single process with per day timing", runFlags, execute},
+	{"runtime", "time a whole year into runtime.md, .json and .csv", runtimeFlags, timings},
+	{"sample", "extract a day examples and their answers", sampleFlags, sample},
+	{"serve", "serve the day solvers over http", serveFlags, serveDays},
+	{"submit", "submit a day answer", submitFlags, submit},
+	{"validate", "check day inputs and examples against their input.grammar", nil, validate},
+	{"variants", "check a day variants agree and compare their timings and allocations", variantsFlags, compareVariants},
+	{"verify", "check day outputs against their answers.txt", verifyFlags, verify},
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("aoc: ")
+
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	i := slices.IndexFunc(commands, func(c command) bool {
+		return c.name == os.Args[1]
+	})
+	if i < 0 {
+		usage()
+		os.Exit(2)
+	}
+	cmd := commands[i]
+
+	var s setup
+
+	flags := flag.NewFlagSet(cmd.name, flag.ExitOnError)
+	s.flags(flags)
+	if cmd.flags != nil {
+		cmd.flags(flags)
+	}
+	flags.Parse(os.Args[2:])
+
+	if err := s.resolve(); err != nil {
+		fatal(err)
+	}
+
+	if err := cmd.run(&s, flags.Args()); err != nil {
+		fatal(err)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: aoc <command> [-year YYYY] [-day DD] [-dir path] [args...]")
+	fmt.Fprintln(os.Stderr, "commands:")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", c.name, c.help)
+	}
+}
+
+// setup locates the year and day a command works on
+type setup struct {
+	Year, Day int
+
+	dir  string // working directory, see -dir
+	root string // repository root, ie. where years live
+}
+
+func (s *setup) flags(fs *flag.FlagSet) {
+	fs.IntVar(&s.Year, "year", 0, "puzzle year (default: inferred from dir)")
+	fs.IntVar(&s.Day, "day", 0, "puzzle day (default: inferred from dir)")
+	fs.StringVar(&s.dir, "dir", ".", "working directory")
+}
+
+// resolve fills in year, day and root from the working directory: explicit
+// flags always win over the <root>/<year>/<day> layout inference
+func (s *setup) resolve() error {
+	dir, err := filepath.Abs(s.dir)
+	if err != nil {
+		return err
+	}
+	s.dir = filepath.Clean(dir)
+
+	year, day := getpwd(s.dir)
+	switch {
+	case day > 0: // day dir
+		s.root = filepath.Dir(filepath.Dir(s.dir))
+	case year > 0: // year dir
+		s.root = filepath.Dir(s.dir)
+	default:
+		s.root = s.dir
+	}
+
+	if s.Year == 0 {
+		s.Year = year
+	}
+	if s.Day == 0 {
+		s.Day = day
+	}
+
+	return nil
+}
+
+// yearDir returns the directory of the selected year
+func (s *setup) yearDir() (string, error) {
+	if s.Year < FIRST {
+		return "", fmt.Errorf("no year: use -year or run from a year directory")
+	}
+	return filepath.Join(s.root, strconv.Itoa(s.Year)), nil
+}
+
+// dayDir returns the directory of the selected day
+func (s *setup) dayDir() (string, error) {
+	dir, err := s.yearDir()
+	if err != nil {
+		return "", err
+	}
+
+	if s.Day < 1 || s.Day > 25 {
+		return "", fmt.Errorf("no day: use -day or run from a day directory")
+	}
+	return filepath.Join(dir, strconv.Itoa(s.Day)), nil
+}
+
+// years returns the selected year, or every year of the root when none is
+func (s *setup) years() ([]int, error) {
+	if s.Year != 0 {
+		return []int{s.Year}, nil
+	}
+
+	dirs, err := os.ReadDir(s.root)
+	if err != nil {
+		return nil, err
+	}
+
+	var all []int
+	for _, d := range dirs {
+		if y, err := strconv.Atoi(d.Name()); err == nil && y >= FIRST && d.IsDir() {
+			all = append(all, y)
+		}
+	}
+	return all, nil
+}
+
+// getpwd infers year and day from a <year>/<day> or <year> directory,
+// either is 0 when it can't be inferred
+func getpwd(dir string) (year, day int) {
+	base := func(p string) int {
+		n, err := strconv.Atoi(filepath.Base(p))
+		if err != nil {
+			return 0
+		}
+		return n
+	}
+
+	switch n := base(dir); {
+	case n >= FIRST:
+		return n, 0
+	case n >= 1 && n <= 25:
+		if y := base(filepath.Dir(dir)); y >= FIRST {
+			return y, n
+		}
+	}
+
+	return 0, 0
+}
+
+func fatal(err error) {
+	log.Fatal(err.Error())
+}
+
+func warnf(format string, v ...any) {
+	log.Printf(format, v...)
+}
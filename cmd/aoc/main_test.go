@@ -0,0 +1,64 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestGetpwd(t *testing.T) {
+	tests := []struct {
+		name      string
+		dir       string
+		year, day int
+	}{
+		{"day dir", "/src/aoc/2023/14", 2023, 14},
+		{"year dir", "/src/aoc/2019", 2019, 0},
+		{"root dir", "/src/aoc", 0, 0},
+		{"day out of range", "/src/aoc/2023/26", 0, 0},
+		{"day without year", "/src/aoc/cmd/7", 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			year, day := getpwd(tt.dir)
+			if year != tt.year || day != tt.day {
+				t.Errorf("getpwd(%q) = %d, %d, expected %d, %d",
+					tt.dir, year, day, tt.year, tt.day)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name      string
+		s         setup
+		year, day int
+		daydir    string
+	}{
+		{"inferred", setup{dir: "/src/aoc/2023/14"}, 2023, 14, "/src/aoc/2023/14"},
+		{"explicit day", setup{dir: "/src/aoc/2023", Day: 3}, 2023, 3, "/src/aoc/2023/3"},
+		{"explicit both", setup{dir: "/src/aoc", Year: 2019, Day: 9}, 2019, 9, "/src/aoc/2019/9"},
+		{"flags win", setup{dir: "/src/aoc/2023/14", Year: 2021, Day: 1}, 2021, 1, "/src/aoc/2021/1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.s.resolve(); err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.s.Year != tt.year || tt.s.Day != tt.day {
+				t.Errorf("resolved %d, %d, expected %d, %d", tt.s.Year, tt.s.Day, tt.year, tt.day)
+			}
+
+			dir, err := tt.s.dayDir()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if dir != filepath.FromSlash(tt.daydir) {
+				t.Errorf("dayDir() = %s, expected %s", dir, tt.daydir)
+			}
+		})
+	}
+}
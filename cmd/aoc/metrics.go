@@ -0,0 +1,362 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var (
+	asJSON bool // see -json
+	top    int  // see -top
+)
+
+func metricsFlags(fs *flag.FlagSet) {
+	fs.BoolVar(&asJSON, "json", false, "write json instead of markdown")
+	fs.IntVar(&top, "top", 10, "most complex functions listed")
+}
+
+// funcMetrics is the cyclomatic complexity of a function
+type funcMetrics struct {
+	Name  string `json:"name"`
+	Cyclo int    `json:"cyclo"`
+}
+
+// dayMetrics sums up the solver sources of a day, tests and hook excluded
+type dayMetrics struct {
+	Year    int           `json:"year"`
+	Day     int           `json:"day"`
+	Lines   int           `json:"lines"` // code lines, comments and blanks excluded
+	Funcs   []funcMetrics `json:"funcs"`
+	Depth   int           `json:"max_depth"`
+	Imports []string      `json:"imports"`
+}
+
+// cyclo returns the day highest function complexity
+func (m dayMetrics) cyclo() funcMetrics {
+	if len(m.Funcs) == 0 {
+		return funcMetrics{}
+	}
+	return slices.MaxFunc(m.Funcs, func(a, b funcMetrics) int {
+		return a.Cyclo - b.Cyclo
+	})
+}
+
+// yearMetrics sums up the days of a year
+type yearMetrics struct {
+	Year  int     `json:"year"`
+	Days  int     `json:"days"`
+	Lines int     `json:"lines"`
+	Funcs int     `json:"funcs"`
+	Avg   float64 `json:"avg_cyclo"`
+	Max   int     `json:"max_cyclo"`
+	Depth int     `json:"max_depth"`
+}
+
+// metrics reports code lines, function complexities, nesting depth and
+// imports of the selected day, year or of every year, along with a
+// cross-year summary
+func metrics(s *setup, _ []string) error {
+	years, err := s.years()
+	if err != nil {
+		return err
+	}
+
+	var days []dayMetrics
+	for _, year := range years {
+		for day := 1; day <= 25; day++ {
+			if s.Day != 0 && day != s.Day {
+				continue
+			}
+
+			dir := filepath.Join(s.root, strconv.Itoa(year), strconv.Itoa(day))
+			if _, err := os.Stat(filepath.Join(dir, fmt.Sprintf(SRC, day))); err != nil {
+				continue
+			}
+
+			m, err := measure(dir)
+			if err != nil {
+				return err
+			}
+			m.Year, m.Day = year, day
+
+			days = append(days, m)
+		}
+	}
+
+	summary := summarizeYears(days)
+
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			Days  []dayMetrics  `json:"days"`
+			Years []yearMetrics `json:"years"`
+		}{days, summary})
+	}
+
+	return writeMetrics(os.Stdout, days, summary)
+}
+
+// measure parses the day sources of dir
+func measure(dir string) (dayMetrics, error) {
+	var m dayMetrics
+
+	files, err := sources(dir)
+	if err != nil {
+		return m, err
+	}
+
+	fset := token.NewFileSet()
+	for _, name := range files {
+		if filepath.Base(name) == HOOK { // shared
+			continue
+		}
+
+		src, err := os.ReadFile(name)
+		if err != nil {
+			return m, err
+		}
+
+		f, err := parser.ParseFile(fset, name, src, parser.SkipObjectResolution)
+		if err != nil {
+			return m, err
+		}
+
+		m.Lines += codeLines(src)
+
+		for _, imp := range f.Imports {
+			path, _ := strconv.Unquote(imp.Path.Value)
+			m.Imports = append(m.Imports, path)
+		}
+
+		for _, decl := range f.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Body == nil {
+				continue
+			}
+
+			m.Funcs = append(m.Funcs, funcMetrics{funcName(fn), complexity(fn.Body)})
+			m.Depth = max(m.Depth, depth(fn.Body))
+		}
+	}
+
+	slices.Sort(m.Imports)
+	m.Imports = slices.Compact(m.Imports)
+
+	return m, nil
+}
+
+// codeLines counts the lines holding a token, comments aren't tokens
+func codeLines(src []byte) int {
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(src))
+
+	var s scanner.Scanner
+	s.Init(file, src, nil, 0)
+
+	lines := make(map[int]bool)
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		if tok == token.SEMICOLON && lit == "\n" { // automatic
+			continue
+		}
+
+		// a raw string spans many lines
+		first := file.Line(pos)
+		last := first + strings.Count(lit, "\n")
+		for l := first; l <= last; l++ {
+			lines[l] = true
+		}
+	}
+
+	return len(lines)
+}
+
+// funcName qualifies methods with their receiver type, eg. Grid.set
+func funcName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+
+	typ := fn.Recv.List[0].Type
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+	if idx, ok := typ.(*ast.IndexExpr); ok { // generic receiver
+		typ = idx.X
+	}
+	if id, ok := typ.(*ast.Ident); ok {
+		return id.Name + "." + fn.Name.Name
+	}
+	return fn.Name.Name
+}
+
+// complexity is the cyclomatic complexity of a function body the way
+// gocyclo counts it: one plus one per branch and boolean operator, function
+// literals included
+func complexity(body *ast.BlockStmt) int {
+	n := 1
+	ast.Inspect(body, func(node ast.Node) bool {
+		switch x := node.(type) {
+		case *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt:
+			n++
+		case *ast.CaseClause:
+			if x.List != nil { // default
+				n++
+			}
+		case *ast.CommClause:
+			if x.Comm != nil { // default
+				n++
+			}
+		case *ast.BinaryExpr:
+			if x.Op == token.LAND || x.Op == token.LOR {
+				n++
+			}
+		}
+		return true
+	})
+	return n
+}
+
+// nesting is an ast.Visitor measuring how deep control statements nest
+type nesting struct {
+	depth int
+	max   *int
+}
+
+func (v nesting) Visit(node ast.Node) ast.Visitor {
+	switch x := node.(type) {
+	case *ast.IfStmt:
+		in := v.enter()
+		walkAll(in, x.Init, x.Cond, x.Body)
+		if x.Else != nil {
+			if _, ok := x.Else.(*ast.IfStmt); ok { // else if isn't deeper
+				ast.Walk(v, x.Else)
+			} else {
+				ast.Walk(in, x.Else)
+			}
+		}
+		return nil
+	case *ast.ForStmt, *ast.RangeStmt, *ast.SwitchStmt, *ast.TypeSwitchStmt, *ast.SelectStmt, *ast.FuncLit:
+		return v.enter()
+	}
+	return v
+}
+
+func (v nesting) enter() nesting {
+	v.depth++
+	*v.max = max(*v.max, v.depth)
+	return v
+}
+
+func walkAll(v ast.Visitor, nodes ...ast.Node) {
+	for _, n := range nodes {
+		if n != nil {
+			ast.Walk(v, n)
+		}
+	}
+}
+
+// depth returns the maximum nesting depth of control statements in a body
+func depth(body *ast.BlockStmt) int {
+	var n int
+	ast.Walk(nesting{max: &n}, body)
+	return n
+}
+
+// summarizeYears sums the days by year
+func summarizeYears(days []dayMetrics) []yearMetrics {
+	var all []yearMetrics
+	for _, d := range days {
+		if len(all) == 0 || all[len(all)-1].Year != d.Year {
+			all = append(all, yearMetrics{Year: d.Year})
+		}
+		y := &all[len(all)-1]
+
+		y.Days++
+		y.Lines += d.Lines
+		y.Depth = max(y.Depth, d.Depth)
+		for _, f := range d.Funcs {
+			y.Avg += float64(f.Cyclo)
+			y.Max = max(y.Max, f.Cyclo)
+		}
+		y.Funcs += len(d.Funcs)
+	}
+
+	for i := range all {
+		if all[i].Funcs > 0 {
+			all[i].Avg /= float64(all[i].Funcs)
+		}
+	}
+	return all
+}
+
+// writeMetrics writes the day tables by year, the most complex functions and
+// the cross-year summary in markdown
+func writeMetrics(w io.Writer, days []dayMetrics, summary []yearMetrics) error {
+	year := 0
+	for _, d := range days {
+		if d.Year != year {
+			if year != 0 {
+				fmt.Fprintln(w)
+			}
+			year = d.Year
+
+			fmt.Fprintf(w, "## %d\n\n", year)
+			fmt.Fprintln(w, "| day | lines | funcs | max cyclo | depth | imports |")
+			fmt.Fprintln(w, "|----:|------:|------:|:----------|------:|:--------|")
+		}
+
+		c := d.cyclo()
+		fmt.Fprintf(w, "| %d | %d | %d | %d %s | %d | %s |\n",
+			d.Day, d.Lines, len(d.Funcs), c.Cyclo, c.Name, d.Depth, strings.Join(d.Imports, " "))
+	}
+
+	type ranked struct {
+		year, day int
+		funcMetrics
+	}
+
+	var funcs []ranked
+	for _, d := range days {
+		for _, f := range d.Funcs {
+			funcs = append(funcs, ranked{d.Year, d.Day, f})
+		}
+	}
+	slices.SortStableFunc(funcs, func(a, b ranked) int {
+		return cmp.Compare(b.Cyclo, a.Cyclo)
+	})
+
+	fmt.Fprintf(w, "\n## top %d\n\n", top)
+	fmt.Fprintln(w, "| cyclo | function | day |")
+	fmt.Fprintln(w, "|------:|:---------|:----|")
+	for _, f := range funcs[:min(top, len(funcs))] {
+		fmt.Fprintf(w, "| %d | %s | %d-%d |\n", f.Cyclo, f.Name, f.year, f.day)
+	}
+
+	fmt.Fprint(w, "\n## summary\n\n")
+	fmt.Fprintln(w, "| year | days | lines | funcs | avg cyclo | max cyclo | max depth |")
+	fmt.Fprintln(w, "|-----:|-----:|------:|------:|----------:|----------:|----------:|")
+	for _, y := range summary {
+		if _, err := fmt.Fprintf(w, "| %d | %d | %d | %d | %.1f | %d | %d |\n", y.Year, y.Days, y.Lines, y.Funcs, y.Avg, y.Max, y.Depth); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
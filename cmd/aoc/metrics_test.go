@@ -0,0 +1,91 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// MEASURED is a day source with known metrics
+const MEASURED = `// aoc1.go --
+// header
+
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// main is the entry point
+func main() {
+	s := ` + "`a\nb`" + `
+
+	for i := range 3 { // 2
+		if i > 0 && s != "" { // 3, 4
+			fmt.Println(i)
+		} else if i < 0 { // 5, else if isn't deeper
+			fmt.Println(-i)
+		} else {
+			switch { // depth 3
+			case true: // 6
+			default:
+			}
+		}
+	}
+}
+
+func (g *grid) get() string {
+	return strings.ToUpper("x")
+}
+`
+
+func TestMeasure(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "aoc1.go"), []byte(MEASURED), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "aoc1_test.go"), []byte("package main\n\nimport \"testing\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := measure(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if m.Lines != 24 {
+		t.Errorf("lines = %d, expected 24", m.Lines)
+	}
+
+	expected := []funcMetrics{{"main", 6}, {"grid.get", 1}}
+	if !slices.Equal(m.Funcs, expected) {
+		t.Errorf("funcs = %v, expected %v", m.Funcs, expected)
+	}
+
+	if m.Depth != 3 {
+		t.Errorf("depth = %d, expected 3", m.Depth)
+	}
+
+	if !slices.Equal(m.Imports, []string{"fmt", "strings"}) {
+		t.Errorf("imports = %v, expected fmt strings", m.Imports)
+	}
+}
+
+func TestSummarizeYears(t *testing.T) {
+	days := []dayMetrics{
+		{Year: 2023, Day: 1, Lines: 10, Funcs: []funcMetrics{{"main", 1}, {"f", 5}}, Depth: 2},
+		{Year: 2023, Day: 2, Lines: 20, Funcs: []funcMetrics{{"main", 3}}, Depth: 4},
+		{Year: 2024, Day: 1, Lines: 5, Funcs: []funcMetrics{{"main", 2}}, Depth: 1},
+	}
+
+	expected := []yearMetrics{
+		{Year: 2023, Days: 2, Lines: 30, Funcs: 3, Avg: 3, Max: 5, Depth: 4},
+		{Year: 2024, Days: 1, Lines: 5, Funcs: 1, Avg: 2, Max: 2, Depth: 1},
+	}
+
+	if got := summarizeYears(days); !slices.Equal(got, expected) {
+		t.Errorf("summarizeYears() = %+v, expected %+v", got, expected)
+	}
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// MIGRATED is the Makefile line of a day built as a package of the root
+// module, see day.mk
+const MIGRATED = "SRCS = ."
+
+// INCLUDE is the Makefile line every day shares its targets with
+const INCLUDE = "include ../day.mk"
+
+var dryRun bool // see -n
+
+func migrateFlags(fs *flag.FlagSet) {
+	fs.BoolVar(&dryRun, "n", false, "check the days and print what would change, without writing")
+}
+
+// errOwnModule tells a day with a go.mod of its own, it stays out of the
+// root module
+var errOwnModule = errors.New("own module, left as is")
+
+// migrate moves the selected day, every day of the selected year or of
+// every year to the root module: it checks a day builds as a package of it
+// and has its Makefile build it so, instead of listing its files, which
+// lets the day import the shared packages of lib/
+func migrate(s *setup, _ []string) error {
+	gomod, err := os.ReadFile(filepath.Join(s.root, "go.mod"))
+	if err != nil {
+		return fmt.Errorf("no root module: %w", err)
+	}
+	path := modulePath(gomod)
+
+	years, err := s.years()
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.MkdirTemp("", "aoc")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	var migrated, done, own, failed int
+	for _, year := range years {
+		for day := 1; day <= 25; day++ {
+			if s.Day != 0 && day != s.Day {
+				continue
+			}
+
+			dir := filepath.Join(s.root, strconv.Itoa(year), strconv.Itoa(day))
+			if _, err := os.Stat(filepath.Join(dir, fmt.Sprintf(SRC, day))); err != nil {
+				continue
+			}
+
+			changed, err := migrateDay(dir, tmp)
+			switch {
+			case errors.Is(err, errOwnModule):
+				own++
+				fmt.Printf("%d-%d: %v\n", year, day, err)
+			case err != nil:
+				failed++
+				fmt.Printf("%d-%d: %v\n", year, day, err)
+			case changed:
+				migrated++
+				fmt.Printf("%d-%d: %s/%d/%d\n", year, day, path, year, day)
+			default:
+				done++
+			}
+		}
+	}
+
+	verb := "migrated"
+	if dryRun {
+		verb = "to migrate"
+	}
+	fmt.Printf("\n%d %s, %d already, %d with their own module, %d failed\n", migrated, verb, done, own, failed)
+
+	if failed > 0 {
+		return fmt.Errorf("%d days failed", failed)
+	}
+	return nil
+}
+
+// migrateDay checks a day builds as a package and sets MIGRATED in its
+// Makefile, it tells if the Makefile changed or, with -n, would
+func migrateDay(dir, tmp string) (bool, error) {
+	if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+		return false, errOwnModule
+	}
+
+	name := filepath.Join(dir, "Makefile")
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return false, err
+	}
+
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if slices.ContainsFunc(lines, func(l string) bool { return strings.Join(strings.Fields(l), " ") == MIGRATED }) {
+		return false, nil
+	}
+
+	at := slices.Index(lines, INCLUDE)
+	if at < 0 {
+		return false, fmt.Errorf("Makefile doesn't %s", INCLUDE)
+	}
+
+	cmd := exec.Command("go", "build", "-o", filepath.Join(tmp, "pkg"), ".")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return false, fmt.Errorf("doesn't build as a package: %w\n%s", err, out)
+	}
+
+	if dryRun {
+		return true, nil
+	}
+
+	// MIGRATED goes after the day settings, a blank line before the include
+	i := at
+	for i > 0 && strings.TrimSpace(lines[i-1]) == "" {
+		i--
+	}
+	lines = slices.Concat(lines[:i], []string{MIGRATED, ""}, lines[at:])
+
+	return true, os.WriteFile(name, []byte(strings.Join(lines, "\n")+"\n"), 0o644)
+}
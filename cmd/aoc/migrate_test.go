@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMigrate(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds solvers")
+	}
+
+	root := t.TempDir()
+	write := func(day int, name, data string) {
+		dir := filepath.Join(root, "2025", fmt.Sprint(day))
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	read := func(day int) string {
+		data, err := os.ReadFile(filepath.Join(root, "2025", fmt.Sprint(day), "Makefile"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	if err := os.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.com/aoc\n\ngo 1.25\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	makefiles := []string{
+		1: "include ../day.mk",                                    // plain, no trailing newline
+		2: "FUZZTESTS = ../../lib/intcode\n\ninclude ../day.mk\n", // settings
+		3: "SRCS = .\n\ninclude ../day.mk\n",                      // already
+		4: "include ../day.mk\n",                                  // own module
+		5: "include ../day.mk\n",                                  // not a package
+		6: "all:\n\tgo run aoc6.go\n",                             // own rules
+	}
+	for day := 1; day <= 6; day++ {
+		write(day, fmt.Sprintf(SRC, day), "package main\n\nfunc main() {}\n")
+		write(day, "Makefile", makefiles[day])
+	}
+	write(4, "go.mod", "module example.com/d4\n\ngo 1.25\n")
+	write(5, "other.go", "package main\n\nfunc main() {}\n")
+
+	s := setup{dir: root, Year: 2025}
+	if err := s.resolve(); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() { dryRun = false }()
+
+	dryRun = true
+	if err := migrate(&s, nil); err == nil {
+		t.Error("migrate() expected to fail on days 5 and 6")
+	}
+	if got := read(1); got != makefiles[1] {
+		t.Errorf("dry run rewrote day 1 Makefile to %q", got)
+	}
+
+	dryRun = false
+	if err := migrate(&s, nil); err == nil {
+		t.Error("migrate() expected to fail on days 5 and 6")
+	}
+
+	expected := []string{
+		1: "SRCS = .\n\ninclude ../day.mk\n",
+		2: "FUZZTESTS = ../../lib/intcode\nSRCS = .\n\ninclude ../day.mk\n",
+		3: makefiles[3],
+		4: makefiles[4],
+		5: makefiles[5],
+		6: makefiles[6],
+	}
+	for day := 1; day <= 6; day++ {
+		if got := read(day); got != expected[day] {
+			t.Errorf("day %d Makefile = %q, expected %q", day, got, expected[day])
+		}
+	}
+
+	s.Day = 1 // now a no-op
+	if err := migrate(&s, nil); err != nil {
+		t.Errorf("migrate() day 1 again: %v", err)
+	}
+}
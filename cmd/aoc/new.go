@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// skeletons are the day templates by year:
+//
+//	skel/<year>/<name>.tmpl   year specific
+//	skel/default/<name>.tmpl  anything else
+//
+// a year uses the closest skeleton year not after it, file by file. Only
+// 2025 has one so far, a solver printing its time
+//
+//go:embed skel
+var skeletons embed.FS
+
+// HOOK and HARNESS are the day links to the SHARED run hook and its fuzz
+// harness
+const (
+	HOOK    = "hook.go"
+	HARNESS = "hook_test.go"
+	SHARED  = "../../hook/hook.go"
+)
+
+// YEARMK is the day.mk of a year, its day Makefiles include it. A new year
+// gets one holding ROOTMK
+const (
+	YEARMK = "day.mk"
+	ROOTMK = "include ../../day.mk\n"
+)
+
+// SKELETON maps the day files to their templates
+var SKELETON = []struct{ tmpl, name string }{
+	{"aoc.go.tmpl", SRC},
+	{"aoc_test.go.tmpl", "aoc%d_test.go"},
+	{"Makefile.tmpl", "Makefile"},
+	{"input.grammar.tmpl", GRAMMAR},
+}
+
+// skeleton returns the template path of a file for year
+func skeleton(year int, tmpl string) (string, error) {
+	dirs, err := fs.ReadDir(skeletons, "skel")
+	if err != nil {
+		return "", err
+	}
+
+	best := 0
+	for _, d := range dirs {
+		y, err := strconv.Atoi(d.Name())
+		if err != nil || y > year || y < best {
+			continue
+		}
+		if _, err := fs.Stat(skeletons, path.Join("skel", d.Name(), tmpl)); err == nil {
+			best = y
+		}
+	}
+
+	if best == 0 {
+		return path.Join("skel", "default", tmpl), nil
+	}
+	return path.Join("skel", strconv.Itoa(best), tmpl), nil
+}
+
+// scaffold creates the selected day solver, test and Makefile from the
+// skeletons, links the run hook and heads the solver. The first day of a
+// year also creates its day.mk. It never overwrites
+// a file but completes a directory that only holds an input or samples
+func scaffold(s *setup, _ []string) error {
+	dir, err := s.dayDir()
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, len(SKELETON))
+	files := make([][]byte, len(SKELETON))
+	for i, f := range SKELETON {
+		names[i] = filepath.Join(dir, strings.ReplaceAll(f.name, "%d", strconv.Itoa(s.Day)))
+		if _, err := os.Stat(names[i]); !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("%s already exists", names[i])
+		}
+
+		src, err := skeleton(s.Year, f.tmpl)
+		if err != nil {
+			return err
+		}
+
+		tmpl, err := template.ParseFS(skeletons, src)
+		if err != nil {
+			return err
+		}
+
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, s); err != nil {
+			return err
+		}
+		files[i] = buf.Bytes()
+	}
+
+	links := []string{filepath.Join(dir, HOOK), filepath.Join(dir, HARNESS)}
+	for _, link := range links {
+		if _, err := os.Lstat(link); !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("%s already exists", link)
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	for i, name := range names {
+		if err := os.WriteFile(name, files[i], 0o644); err != nil {
+			return err
+		}
+	}
+
+	for _, link := range links {
+		if err := os.Symlink(path.Join(path.Dir(SHARED), filepath.Base(link)), link); err != nil {
+			return err
+		}
+	}
+	names = append(names, links...)
+
+	mk := filepath.Join(filepath.Dir(dir), YEARMK)
+	if _, err := os.Stat(mk); errors.Is(err, os.ErrNotExist) { // new year
+		if err := os.WriteFile(mk, []byte(ROOTMK), 0o644); err != nil {
+			return err
+		}
+		names = append(names, mk)
+	}
+
+	if err := addHeader(s, nil); err != nil {
+		return err
+	}
+
+	fmt.Printf("Created day %d structure:\n", s.Day)
+	fmt.Printf("  - Directory: %s/\n", dir)
+	for _, name := range names {
+		fmt.Printf("  - File: %s\n", name)
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSkeleton(t *testing.T) {
+	tests := []struct {
+		year     int
+		tmpl     string
+		expected string
+	}{
+		{2025, "aoc.go.tmpl", "skel/2025/aoc.go.tmpl"},
+		{2031, "aoc.go.tmpl", "skel/2025/aoc.go.tmpl"}, // closest before
+		{2019, "aoc.go.tmpl", "skel/default/aoc.go.tmpl"},
+		{2025, "Makefile.tmpl", "skel/default/Makefile.tmpl"}, // file by file
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			got, err := skeleton(tt.year, tt.tmpl)
+			if err != nil || got != tt.expected {
+				t.Errorf("skeleton(%d, %q) = %q, %v, expected %q", tt.year, tt.tmpl, got, err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestScaffold(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "2025", "7")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, INPUT), []byte("input\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	s := setup{dir: root, Year: 2025, Day: 7}
+	if err := s.resolve(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := scaffold(&s, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	src, err := os.ReadFile(filepath.Join(dir, "aoc7.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"// aoc7.go --", "os.Exit(runDay(Solve", "defer guard(&err)", "t0 := time.Now()", "func Solve(r io.Reader) (_ Result, err error)"} {
+		if !strings.Contains(string(src), expected) {
+			t.Errorf("aoc7.go lacks %q", expected)
+		}
+	}
+
+	for _, name := range []string{"aoc7_test.go", "Makefile", GRAMMAR} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Error(err)
+		}
+	}
+
+	if mk, err := os.ReadFile(filepath.Join(root, "2025", YEARMK)); err != nil || string(mk) != ROOTMK {
+		t.Errorf("%s = %q, %v, expected %q", YEARMK, mk, err, ROOTMK)
+	}
+
+	if link, err := os.Readlink(filepath.Join(dir, HOOK)); err != nil || link != SHARED {
+		t.Errorf("%s links to %q, %v, expected %q", HOOK, link, err, SHARED)
+	}
+	if link, err := os.Readlink(filepath.Join(dir, HARNESS)); err != nil || link != "../../hook/hook_test.go" {
+		t.Errorf("%s links to %q, %v, expected %q", HARNESS, link, err, "../../hook/hook_test.go")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "aoc7.go"), []byte("work"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := scaffold(&s, nil); err == nil {
+		t.Error("scaffold() overwrote an existing day")
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "aoc7.go")); string(data) != "work" {
+		t.Errorf("aoc7.go = %q, expected untouched", data)
+	}
+}
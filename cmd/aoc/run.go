@@ -0,0 +1,589 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+func runFlags(fs *flag.FlagSet) {
+	fs.BoolVar(&samples, "sample", false, "run on sample.txt instead of input.txt")
+}
+
+// solver is a day registered into the runner
+type solver struct {
+	Year, Day int
+
+	Dir   string // absolute day directory
+	Input string // absolute input path, aoc run only
+	files []string
+}
+
+// Pkg is the day package path in the runner module
+func (e solver) Pkg() string {
+	return fmt.Sprintf("y%d/d%d", e.Year, e.Day)
+}
+
+// Alias is the day package name in the runner main
+func (e solver) Alias() string {
+	return fmt.Sprintf("y%dd%d", e.Year, e.Day)
+}
+
+// execute runs days in a single process: every day exposing a Solve entry
+// point is compiled as a package of a generated runner that times them
+// one after another. The selection is either flags and directory, or:
+//
+//	aoc run 2023 14
+//	aoc run 2023 all
+//	aoc run all
+func execute(s *setup, args []string) error {
+	if err := selectDays(s, args); err != nil {
+		return err
+	}
+
+	years, err := s.years()
+	if err != nil {
+		return err
+	}
+
+	input := INPUT
+	if samples {
+		input = SAMPLE
+	}
+
+	var solvers []solver
+	for _, year := range years {
+		days := []int{s.Day}
+		if s.Day == 0 {
+			days = days[:0]
+			for day := 1; day <= 25; day++ {
+				days = append(days, day)
+			}
+		}
+
+		for _, day := range days {
+			dir := filepath.Join(s.root, strconv.Itoa(year), strconv.Itoa(day))
+			if _, err := os.Stat(filepath.Join(dir, fmt.Sprintf(SRC, day))); errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+
+			e, err := register(dir, year, day, input)
+			if err != nil {
+				warnf("skipping %d-%d: %v", year, day, err)
+				continue
+			}
+			solvers = append(solvers, e)
+		}
+	}
+
+	if len(solvers) == 0 {
+		return fmt.Errorf("no day to run")
+	}
+
+	tmp, err := os.MkdirTemp("", "aoc")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	bin, err := buildRunner(tmp, solvers)
+	if err != nil {
+		return err
+	}
+
+	return runDays(os.Stdout, bin, solvers)
+}
+
+// dayReport is a day report of the runner, its answers are printed and
+// a missing part is nil
+type dayReport struct {
+	Year, Day    int
+	Part1, Part2 *string
+	Elapsed      int64 // ns
+	Error        string
+}
+
+// runDays runs the runner bin of solvers and writes their answers, times
+// and year totals to w. A day that crashes the runner, eg. a deadlock or a
+// panic in one of its goroutines, fails alone: the runner is started
+// again from the next day
+func runDays(w io.Writer, bin string, solvers []solver) error {
+	var year, failed int
+	var total, all time.Duration
+
+	sum := func() {
+		if year > 0 {
+			fmt.Fprintf(w, "%d total %v\n\n", year, total)
+		}
+	}
+
+	add := func(r dayReport) {
+		if r.Year != year {
+			sum()
+			year, total = r.Year, 0
+		}
+
+		if r.Error != "" {
+			fmt.Fprintf(w, "%d-%d: %s\n", r.Year, r.Day, r.Error)
+			failed++
+			return
+		}
+
+		δ := time.Duration(r.Elapsed)
+		total, all = total+δ, all+δ
+		fmt.Fprintf(w, "%d-%d %s%s%v\n", r.Year, r.Day, show(r.Part1), show(r.Part2), δ)
+	}
+
+	for next := 0; next < len(solvers); {
+		n, crash, err := runFrom(bin, next, add)
+		if err != nil {
+			return err
+		}
+		next += n
+
+		if crash != "" && next < len(solvers) {
+			e := solvers[next]
+			add(dayReport{Year: e.Year, Day: e.Day, Error: crash})
+			next++
+		}
+	}
+	sum()
+
+	fmt.Fprintf(w, "%d days in %v\n", len(solvers)-failed, all)
+	if failed > 0 {
+		return fmt.Errorf("%d days failed", failed)
+	}
+	return nil
+}
+
+// runFrom runs bin from its day from on and hands each report to add. It
+// returns the count of reports and, when the runner died, the line of its
+// stderr telling why
+func runFrom(bin string, from int, add func(dayReport)) (int, string, error) {
+	pr, pw, err := os.Pipe()
+	if err != nil {
+		return 0, "", err
+	}
+	defer pr.Close()
+
+	var errs bytes.Buffer
+
+	cmd := exec.Command(bin, strconv.Itoa(from))
+	cmd.Stdout = os.Stdout // solver chatter
+	cmd.Stderr = &errs
+	cmd.ExtraFiles = []*os.File{pw}
+
+	err = cmd.Start()
+	pw.Close()
+	if err != nil {
+		return 0, "", err
+	}
+
+	n := 0
+	reports := json.NewDecoder(pr)
+	for {
+		var r dayReport
+		if reports.Decode(&r) != nil {
+			break
+		}
+		add(r)
+		n++
+	}
+
+	if err := cmd.Wait(); err != nil {
+		return n, crashLine(errs.String(), err), nil
+	}
+	os.Stderr.Write(errs.Bytes())
+	return n, "", nil
+}
+
+// crashLine returns the panic or fatal error line of a crashed runner
+// stderr, or its last line
+func crashLine(errs string, err error) string {
+	lines := strings.Split(strings.TrimSpace(errs), "\n")
+	for _, l := range lines {
+		if strings.HasPrefix(l, "panic: ") || strings.HasPrefix(l, "fatal error: ") {
+			return l
+		}
+	}
+	if last := lines[len(lines)-1]; last != "" {
+		return last
+	}
+	return err.Error()
+}
+
+// show formats an answer and its separator, a missing part is omitted and
+// multiline answers, eg. ascii art, are put on their own lines
+func show(v *string) string {
+	if v == nil {
+		return ""
+	}
+
+	s := *v
+	if strings.Contains(s, "\n") {
+		return "\n" + strings.TrimRight(s, "\n") + "\n"
+	}
+	return s + " "
+}
+
+// selectDays reads the positional year and day, all stands for every
+// year or every day of a year
+func selectDays(s *setup, args []string) error {
+	if len(args) > 2 {
+		return fmt.Errorf("usage: aoc run [year|all] [day|all]")
+	}
+
+	pick := func(arg string, lo, hi int) (int, error) {
+		if arg == "all" {
+			return 0, nil
+		}
+		n, err := strconv.Atoi(arg)
+		if err != nil || n < lo || n > hi {
+			return 0, fmt.Errorf("bad selection %q", arg)
+		}
+		return n, nil
+	}
+
+	var err error
+	switch len(args) {
+	case 2:
+		if s.Day, err = pick(args[1], 1, 25); err != nil {
+			return err
+		}
+		fallthrough
+	case 1:
+		if s.Year, err = pick(args[0], FIRST, 9999); err != nil {
+			return err
+		}
+		if len(args) == 1 {
+			s.Day = 0
+		}
+	}
+
+	return nil
+}
+
+// register checks that a day has an input and exposes a Solve entry point
+func register(dir string, year, day int, input string) (solver, error) {
+	e := solver{Year: year, Day: day, Dir: dir, Input: filepath.Join(dir, input)}
+
+	if _, err := os.Stat(e.Input); err != nil {
+		return e, fmt.Errorf("no %s", input)
+	}
+
+	files, err := sources(dir)
+	if err != nil {
+		return e, err
+	}
+	e.files = files
+
+	return e, entryPoint(files)
+}
+
+// entryPoint checks one of the day files declares the Solve entry point
+func entryPoint(files []string) error {
+	fset := token.NewFileSet()
+	for _, name := range files {
+		f, err := parser.ParseFile(fset, name, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return err
+		}
+
+		for _, decl := range f.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil && fn.Name.Name == "Solve" {
+				return nil
+			}
+		}
+	}
+
+	return fmt.Errorf("no Solve entry point")
+}
+
+// RUNNER is the generated runner main, days are timed on an in memory input
+// and a panic of Solve only fails its day. It runs the days from the index
+// it is given and writes their reports as json lines to fd 3
+const RUNNER = `// Code generated by aoc run. DO NOT EDIT.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+{{range .}}
+	{{.Alias}} "aocrun/{{.Pkg}}"
+{{- end}}
+)
+
+type day struct {
+	year, day int
+	input     string
+	solve     func(io.Reader) (any, any, error)
+}
+
+var days = []day{
+{{- range .}}
+	{ {{- .Year}}, {{.Day}}, {{printf "%q" .Input}}, func(r io.Reader) (any, any, error) {
+		res, err := {{.Alias}}.Solve(r)
+		return res.Part1, res.Part2, err
+	}},
+{{- end}}
+}
+
+// result is a dayReport of aoc run
+type result struct {
+	Year, Day    int
+	Part1, Part2 *string
+	Elapsed      int64
+	Error        string
+}
+
+func main() {
+	var from int
+	if len(os.Args) > 1 {
+		from, _ = strconv.Atoi(os.Args[1])
+	}
+
+	out := json.NewEncoder(os.NewFile(3, "results"))
+	for _, d := range days[from:] {
+		r := result{Year: d.year, Day: d.day}
+
+		data, err := os.ReadFile(d.input)
+		if err == nil {
+			var δ time.Duration
+			r.Part1, r.Part2, δ, err = solve(d, data)
+			r.Elapsed = δ.Nanoseconds()
+		}
+		if err != nil {
+			r.Error = err.Error()
+		}
+
+		if err := out.Encode(r); err != nil {
+			os.Exit(1)
+		}
+	}
+}
+
+func solve(d day, data []byte) (p1, p2 *string, δ time.Duration, err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			err = fmt.Errorf("panic: %v", e)
+		}
+	}()
+
+	t0 := time.Now()
+	v1, v2, err := d.solve(bytes.NewReader(data))
+	δ = time.Since(t0)
+
+	return text(v1), text(v2), δ, err
+}
+
+// text formats an answer, a missing part is nil
+func text(v any) *string {
+	if v == nil {
+		return nil
+	}
+	s := fmt.Sprint(v)
+	return &s
+}
+`
+
+var runner = template.Must(template.New("runner").Parse(RUNNER))
+
+// buildRunner writes the runner module into dir and compiles it
+func buildRunner(dir string, solvers []solver) (string, error) {
+	return buildProgram(dir, solvers, runner, nil)
+}
+
+// buildProgram writes a module into dir, each day as a package of its own
+// imported by the main generated from tmpl next to the extra files, and
+// compiles it
+func buildProgram(dir string, solvers []solver, tmpl *template.Template, extra map[string][]byte) (string, error) {
+	mod, sum, err := requirements(solvers)
+	if err != nil {
+		return "", err
+	}
+
+	version, err := exec.Command("go", "env", "GOVERSION").Output()
+	if err != nil {
+		return "", fmt.Errorf("locating go: %w", err)
+	}
+
+	var gomod bytes.Buffer
+	fmt.Fprintf(&gomod, "module aocrun\n\ngo %s\n", strings.TrimPrefix(strings.TrimSpace(string(version)), "go"))
+	for _, req := range mod {
+		fmt.Fprintf(&gomod, "\nrequire %s\n", req)
+	}
+
+	if path, root, ok := rootModule(solvers); ok { // days importing lib/
+		fmt.Fprintf(&gomod, "\nrequire %s v0.0.0\n\nreplace %s => %s\n", path, path, root)
+
+		data, err := os.ReadFile(filepath.Join(root, "go.sum"))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+		sum = slices.AppendSeq(sum, strings.Lines(string(data)))
+		slices.Sort(sum)
+		sum = slices.Compact(sum)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), gomod.Bytes(), 0o644); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "go.sum"), []byte(strings.Join(sum, "")), 0o644); err != nil {
+		return "", err
+	}
+
+	for _, e := range solvers {
+		if err := copyPackage(filepath.Join(dir, e.Pkg()), e); err != nil {
+			return "", err
+		}
+	}
+
+	var main bytes.Buffer
+	if err := tmpl.Execute(&main, solvers); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), main.Bytes(), 0o644); err != nil {
+		return "", err
+	}
+	for name, src := range extra {
+		if err := os.WriteFile(filepath.Join(dir, name), src, 0o644); err != nil {
+			return "", err
+		}
+	}
+
+	bin := filepath.Join(dir, "aocrun")
+
+	cmd := exec.Command("go", "build", "-o", bin, ".")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("building %s: %w\n%s", tmpl.Name(), err, out)
+	}
+
+	return bin, nil
+}
+
+// copyPackage copies the day sources into dir, renaming their main
+// package after the day
+func copyPackage(dir string, e solver) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	fset := token.NewFileSet()
+	for _, name := range e.files {
+		src, err := os.ReadFile(name)
+		if err != nil {
+			return err
+		}
+
+		f, err := parser.ParseFile(fset, name, src, parser.PackageClauseOnly)
+		if err != nil {
+			return err
+		}
+
+		at := fset.Position(f.Name.Pos()).Offset
+		src = slices.Concat(src[:at], []byte(e.Alias()), src[at+len(f.Name.Name):])
+
+		if err := os.WriteFile(filepath.Join(dir, filepath.Base(name)), src, 0o644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// requirements merges the go.mod requirements and go.sum lines of the days
+// that have their own module
+func requirements(solvers []solver) (mod, sum []string, err error) {
+	for _, e := range solvers {
+		data, err := os.ReadFile(filepath.Join(e.Dir, "go.mod"))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		block := false
+		input := bufio.NewScanner(bytes.NewReader(data))
+		for input.Scan() {
+			line, _, _ := strings.Cut(input.Text(), "//")
+			line = strings.TrimSpace(line)
+
+			switch {
+			case line == "require (":
+				block = true
+			case block && line == ")":
+				block = false
+			case block && line != "":
+				mod = append(mod, line)
+			case strings.HasPrefix(line, "require "):
+				mod = append(mod, strings.TrimSpace(strings.TrimPrefix(line, "require ")))
+			}
+		}
+
+		data, err = os.ReadFile(filepath.Join(e.Dir, "go.sum"))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, nil, err
+		}
+		for line := range strings.Lines(string(data)) {
+			sum = append(sum, line)
+		}
+	}
+
+	slices.Sort(mod)
+	slices.Sort(sum)
+	return slices.Compact(mod), slices.Compact(sum), nil
+}
+
+// rootModule returns the path and directory of the root module that the
+// days without a module of their own belong to, if any
+func rootModule(solvers []solver) (path, root string, ok bool) {
+	for _, e := range solvers {
+		if _, err := os.Stat(filepath.Join(e.Dir, "go.mod")); err == nil {
+			continue // own module
+		}
+
+		root = filepath.Dir(filepath.Dir(e.Dir))
+		data, err := os.ReadFile(filepath.Join(root, "go.mod"))
+		if err != nil {
+			return "", "", false
+		}
+
+		path = modulePath(data)
+		return path, root, path != ""
+	}
+	return "", "", false
+}
+
+// modulePath returns the module path a go.mod declares
+func modulePath(gomod []byte) string {
+	for line := range strings.Lines(string(gomod)) {
+		if rest, ok := strings.CutPrefix(strings.TrimSpace(line), "module "); ok {
+			rest, _, _ = strings.Cut(rest, "//")
+			return strings.Trim(strings.TrimSpace(rest), `"`)
+		}
+	}
+	return ""
+}
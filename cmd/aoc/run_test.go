@@ -0,0 +1,218 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSelectDays(t *testing.T) {
+	tests := []struct {
+		args      []string
+		year, day int
+		ok        bool
+	}{
+		{nil, 2023, 14, true}, // inferred
+		{[]string{"2022", "3"}, 2022, 3, true},
+		{[]string{"2022", "all"}, 2022, 0, true},
+		{[]string{"2022"}, 2022, 0, true},
+		{[]string{"all"}, 0, 0, true},
+		{[]string{"2022", "26"}, 0, 0, false},
+		{[]string{"1999"}, 0, 0, false},
+		{[]string{"2022", "1", "2"}, 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			s := setup{Year: 2023, Day: 14}
+			err := selectDays(&s, tt.args)
+			if (err == nil) != tt.ok {
+				t.Fatalf("selectDays(%q) expected ok %v, got %v", tt.args, tt.ok, err)
+			}
+			if tt.ok && (s.Year != tt.year || s.Day != tt.day) {
+				t.Errorf("selectDays(%q) = %d-%d, expected %d-%d", tt.args, s.Year, s.Day, tt.year, tt.day)
+			}
+		})
+	}
+}
+
+// SOLVE is a tiny day solver exposing its entry point, it panics on an
+// empty input
+const SOLVE = `package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+)
+
+func main() {
+	res, err := Solve(os.Stdin)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(res.Part1, res.Part2)
+}
+
+type Result struct {
+	Part1, Part2 any
+}
+
+func Solve(r io.Reader) (Result, error) {
+	data, err := io.ReadAll(r)
+	if len(data) == 0 {
+		panic("empty input")
+	}
+	return Result{len(data), 2 * len(data)}, err
+}
+`
+
+// CRASHING is a day solver whose goroutine panics, it takes the runner
+// down with it
+const CRASHING = `package main
+
+import "io"
+
+func main() {}
+
+type Result struct {
+	Part1, Part2 any
+}
+
+func Solve(r io.Reader) (Result, error) {
+	done := make(chan int)
+	go func() {
+		panic("lost goroutine")
+	}()
+	return Result{<-done, nil}, nil
+}
+`
+
+// SHARING is a day solver importing a package of its root module
+const SHARING = `package main
+
+import (
+	"io"
+
+	"example.com/aoc/lib/twice"
+)
+
+func main() {}
+
+type Result struct {
+	Part1, Part2 any
+}
+
+func Solve(r io.Reader) (Result, error) {
+	data, err := io.ReadAll(r)
+	return Result{twice.Twice(len(data)), nil}, err
+}
+`
+
+func TestRunner(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds solvers")
+	}
+
+	root := t.TempDir()
+	write := func(day int, name, data string) {
+		dir := filepath.Join(root, "2025", fmt.Sprint(day))
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(1, fmt.Sprintf(SRC, 1), SOLVE)
+	write(1, INPUT, "input\n")
+	write(2, fmt.Sprintf(SRC, 2), fmt.Sprintf(SOLVER, false)) // no Solve
+	write(2, INPUT, "input\n")
+	write(3, fmt.Sprintf(SRC, 3), SOLVE)
+	write(3, INPUT, "")
+	write(4, fmt.Sprintf(SRC, 4), SHARING)
+	write(4, INPUT, "input\n")
+	write(5, fmt.Sprintf(SRC, 5), CRASHING)
+	write(5, INPUT, "input\n")
+	write(6, fmt.Sprintf(SRC, 6), SOLVE)
+	write(6, INPUT, "more input\n")
+
+	// the root module day 4 imports its lib from
+	if err := os.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.com/aoc\n\ngo 1.25\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	lib := filepath.Join(root, "lib", "twice")
+	if err := os.MkdirAll(lib, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(lib, "twice.go"), []byte("package twice\n\nfunc Twice(n int) int { return 2 * n }\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var solvers []solver
+	for day := 1; day <= 6; day++ {
+		e, err := register(filepath.Join(root, "2025", fmt.Sprint(day)), 2025, day, INPUT)
+		if (err == nil) != (day != 2) {
+			t.Fatalf("register(%d) unexpected %v", day, err)
+		}
+		if err == nil {
+			solvers = append(solvers, e)
+		}
+	}
+
+	bin, err := buildRunner(t.TempDir(), solvers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out strings.Builder
+	if err := runDays(&out, bin, solvers); err == nil {
+		t.Errorf("runner expected to fail on days 3 and 5")
+	}
+
+	for _, expected := range []string{"2025-1 6 12 ", "2025-3: panic: empty input", "2025-4 12 ", "2025-5: panic: lost goroutine", "2025-6 11 22 ", "3 days in "} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("runner output %q, expected %q", out.String(), expected)
+		}
+	}
+}
+
+func TestRequirements(t *testing.T) {
+	mods := []string{
+		"module a\n\ngo 1.21\n\nrequire example.com/x v1.0.0\n\nrequire example.com/y v0.1.0 // indirect\n",
+		"module b\n\ngo 1.21\n\nrequire (\n\texample.com/x v1.0.0\n\texample.com/z v0.2.0\n)\n",
+		"", // no go.mod
+	}
+
+	var solvers []solver
+	for i, mod := range mods {
+		dir := t.TempDir()
+		if mod != "" {
+			if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(mod), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(dir, "go.sum"), []byte("example.com/x v1.0.0 h1:x=\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		solvers = append(solvers, solver{Year: 2025, Day: i + 1, Dir: dir})
+	}
+
+	mod, sum, err := requirements(solvers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "example.com/x v1.0.0|example.com/y v0.1.0|example.com/z v0.2.0"
+	if got := strings.Join(mod, "|"); got != expected {
+		t.Errorf("requirements() = %q, expected %q", got, expected)
+	}
+	if len(sum) != 1 {
+		t.Errorf("requirements() go.sum = %q, expected a single line", sum)
+	}
+}
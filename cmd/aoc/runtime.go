@@ -0,0 +1,273 @@
+package main
+
+import (
+	"cmp"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	RUNTIME  = "runtime"  // runtime.md, runtime.json and runtime.csv
+	PROFILES = "profiles" // the slowest days profiles, in the year
+)
+
+var (
+	stat     string // see -stat
+	profiles int    // see -profile
+)
+
+func runtimeFlags(fs *flag.FlagSet) {
+	benchFlags(fs)
+	fs.StringVar(&stat, "stat", "min", "runtime.md statistic: min, median, mean or p95")
+	fs.IntVar(&profiles, "profile", 0, "profile the `n` slowest days and show their top functions")
+}
+
+// pick returns the statistic selected by -stat
+func (s stats) pick() (time.Duration, error) {
+	switch stat {
+	case "min":
+		return s.Min, nil
+	case "median":
+		return s.Median, nil
+	case "mean":
+		return s.Mean, nil
+	case "p95":
+		return s.P95, nil
+	}
+	return 0, fmt.Errorf("unknown statistic %q", stat)
+}
+
+// timings benchmarks every day of a year with an input and writes the
+// year runtime.md table, and runtime.json and runtime.csv alongside, the
+// run is also appended to the year history for later comparisons, -profile
+// then profiles the slowest days
+func timings(s *setup, _ []string) error {
+	all, err := benchYear(s)
+	if err != nil {
+		return err
+	}
+
+	dir, _ := s.yearDir()
+	name := filepath.Join(dir, RUNTIME)
+
+	r := newRun(dir, all) // before touching the tree
+
+	for ext, write := range map[string]func(io.Writer, []stats) error{
+		".md":   writeMD,
+		".json": writeJSON,
+		".csv":  writeCSV,
+	} {
+		fd, err := os.Create(name + ext)
+		if err != nil {
+			return err
+		}
+
+		if err := write(fd, all); err != nil {
+			fd.Close()
+			return err
+		}
+
+		if err := fd.Close(); err != nil {
+			return err
+		}
+	}
+
+	if err := appendHistory(filepath.Join(dir, HISTORY), r); err != nil {
+		return err
+	}
+
+	if profiles > 0 {
+		return profileSlowest(dir, all, profiles)
+	}
+	return nil
+}
+
+// profileSlowest runs the n slowest days once more with their cpu and
+// memory profiles on, the profiles are kept in the year profiles directory
+// and their top functions printed
+func profileSlowest(ydir string, all []stats, n int) error {
+	slow := slices.Clone(all)
+	slices.SortStableFunc(slow, func(a, b stats) int {
+		δa, _ := a.pick()
+		δb, _ := b.pick()
+		return cmp.Compare(δb, δa)
+	})
+	slow = slow[:min(n, len(slow))]
+
+	out := filepath.Join(ydir, PROFILES)
+	if err := os.MkdirAll(out, 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.MkdirTemp("", "aoc")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	for _, st := range slow {
+		dir := filepath.Join(ydir, strconv.Itoa(st.Day))
+
+		bin, err := build(dir, st.Day, tmp)
+		if err != nil {
+			return err
+		}
+
+		cpu := filepath.Join(out, fmt.Sprintf("aoc%d.cpu.prof", st.Day))
+		mem := filepath.Join(out, fmt.Sprintf("aoc%d.mem.prof", st.Day))
+
+		in, err := os.Open(filepath.Join(dir, INPUT))
+		if err != nil {
+			return err
+		}
+
+		cmd := exec.Command(bin)
+		cmd.Dir = dir
+		cmd.Stdin = in
+		cmd.Env = append(os.Environ(), fmt.Sprintf("AOC_PROFILE=cpu=%s,mem=%s", cpu, mem))
+		err = cmd.Run()
+		in.Close()
+		if err != nil {
+			return fmt.Errorf("profiling %d-%d: %w", st.Year, st.Day, err)
+		}
+
+		δ, _ := st.pick()
+		fmt.Printf("## %d-%d %v\n\n", st.Year, st.Day, δ)
+
+		top, err := exec.Command("go", "tool", "pprof", "-top", "-nodecount=10", bin, cpu).CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("reading %s: %w\n%s", cpu, err, top)
+		}
+
+		// drop the file, build and time lines
+		lines := strings.Split(string(top), "\n")
+		if i := slices.IndexFunc(lines, func(l string) bool {
+			return strings.HasPrefix(l, "Duration:") || strings.HasPrefix(l, "Showing")
+		}); i >= 0 {
+			lines = lines[i:]
+		}
+		fmt.Println(strings.Join(lines, "\n"))
+	}
+
+	return nil
+}
+
+// benchYear builds and times every day of the selected year that has a
+// solver and an input, by day order
+func benchYear(s *setup) ([]stats, error) {
+	ydir, err := s.yearDir()
+	if err != nil {
+		return nil, err
+	}
+
+	tmp, err := os.MkdirTemp("", "aoc")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+
+	var (
+		all  []stats
+		base time.Duration
+	)
+
+	for day := 1; day <= 25; day++ {
+		dir := filepath.Join(ydir, strconv.Itoa(day))
+
+		if _, err := os.Stat(filepath.Join(dir, fmt.Sprintf(SRC, day))); errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+
+		if _, err := os.Stat(filepath.Join(dir, INPUT)); errors.Is(err, os.ErrNotExist) {
+			warnf("skipping %d-%d: no %s", s.Year, day, INPUT)
+			continue
+		}
+
+		if base == 0 {
+			if base, err = baseline(dir); err != nil {
+				return nil, err
+			}
+		}
+
+		bin, err := build(dir, day, tmp)
+		if err != nil {
+			return nil, err
+		}
+
+		st, err := benchDay(s.Year, day, dir, bin, base)
+		if err != nil {
+			return nil, err
+		}
+		warnf("%v", st)
+
+		all = append(all, st)
+	}
+
+	return all, nil
+}
+
+// writeMD writes the year table in ms, in day order
+func writeMD(w io.Writer, all []stats) error {
+	type row struct {
+		day  int
+		time time.Duration
+	}
+
+	rows := make([]row, 0, len(all))
+	for _, s := range all {
+		δ, err := s.pick()
+		if err != nil {
+			return err
+		}
+		rows = append(rows, row{s.Day, δ})
+	}
+
+	ms := func(δ time.Duration) float64 {
+		return float64(δ) / float64(time.Millisecond)
+	}
+
+	var total time.Duration
+
+	fmt.Fprintln(w, "| day | time |")
+	fmt.Fprintln(w, "|-----|-----:|")
+	for _, r := range rows {
+		fmt.Fprintf(w, "| %d | %.1f |\n", r.day, ms(r.time))
+		total += r.time
+	}
+	_, err := fmt.Fprintf(w, "| total | %.1f |\n", ms(total))
+
+	return err
+}
+
+func writeJSON(w io.Writer, all []stats) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(all)
+}
+
+func writeCSV(w io.Writer, all []stats) error {
+	out := csv.NewWriter(w)
+	out.Write([]string{"year", "day", "runs", "source", "min_ns", "median_ns", "mean_ns", "p95_ns", "stddev_ns"})
+
+	for _, s := range all {
+		row := []string{strconv.Itoa(s.Year), strconv.Itoa(s.Day), strconv.Itoa(s.Runs), s.Source}
+		for _, δ := range []time.Duration{s.Min, s.Median, s.Mean, s.P95, s.Stddev} {
+			row = append(row, strconv.FormatInt(int64(δ), 10))
+		}
+		out.Write(row)
+	}
+
+	out.Flush()
+	return out.Error()
+}
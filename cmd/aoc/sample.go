@@ -0,0 +1,149 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	SAMPLE  = "sample.txt"
+	SAMPLES = "sample%d.txt"   // extra examples
+	EXPECTS = "sample.answers" // example answers, one "part: answer" per line
+	PAGE    = "%s/%d/day/%d"
+)
+
+var saved string // saved puzzle page, see -html
+
+func sampleFlags(fs *flag.FlagSet) {
+	fs.StringVar(&saved, "html", "", "read the puzzle page from a saved html file")
+}
+
+// sample extracts the selected day examples and their answers from its
+// puzzle page into the day directory, existing files are kept
+func sample(s *setup, _ []string) error {
+	dir, err := s.dayDir()
+	if err != nil {
+		return err
+	}
+
+	var page []byte
+	if saved != "" {
+		page, err = os.ReadFile(saved)
+	} else {
+		page, err = fetchPage(fmt.Sprintf(PAGE, site, s.Year, s.Day), os.Getenv("SESSION"))
+	}
+	if err != nil {
+		return err
+	}
+
+	examples, answers := parsePage(page)
+	if len(examples) == 0 {
+		return fmt.Errorf("no example found in %d-%d puzzle page", s.Year, s.Day)
+	}
+
+	for i, ex := range examples {
+		name := SAMPLE
+		if i > 0 {
+			name = fmt.Sprintf(SAMPLES, i)
+		}
+
+		if err := create(filepath.Join(dir, name), []byte(ex)); err != nil {
+			return err
+		}
+	}
+
+	var sb strings.Builder
+	for i, a := range answers {
+		if a != "" {
+			fmt.Fprintf(&sb, "%d: %s\n", i+1, a)
+		}
+	}
+	if sb.Len() > 0 {
+		return create(filepath.Join(dir, EXPECTS), []byte(sb.String()))
+	}
+
+	return nil
+}
+
+// create writes a new file, it warns and keeps the file when it exists
+func create(name string, data []byte) error {
+	fd, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if errors.Is(err, os.ErrExist) {
+		warnf("keeping existing %s", name)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if _, err := fd.Write(data); err != nil {
+		fd.Close()
+		return err
+	}
+	warnf("wrote %s", name)
+
+	return fd.Close()
+}
+
+// fetchPage gets a puzzle page, the session is optional and only needed
+// to unveil part 2
+func fetchPage(url, session string) ([]byte, error) {
+	req, err := newRequest("GET", url, session, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error fetching %s: %s", url, resp.Status)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+var (
+	descs    = regexp.MustCompile(`(?s)<article class="day-desc">(.*?)</article>`)
+	blocks   = regexp.MustCompile(`(?s)<p>((?:[^<]|<[^/]|</[^p])*?)</p>\s*<pre><code>(.*?)</code></pre>`)
+	emphases = regexp.MustCompile(`<code><em>([^<]*)</em></code>`)
+)
+
+// parsePage returns the distinct example blocks of a puzzle page, ie. the
+// code blocks introduced by a paragraph mentioning an example, and the
+// part 1 & 2 example answers, ie. the last emphasized code of each part
+func parsePage(page []byte) (examples []string, answers [2]string) {
+	text := func(s string) string {
+		return html.UnescapeString(tags.ReplaceAllString(s, ""))
+	}
+
+	seen := make(map[string]bool)
+	for i, desc := range descs.FindAllSubmatch(page, 2) {
+		for _, m := range blocks.FindAllSubmatch(desc[1], -1) {
+			intro, block := text(string(m[1])), text(string(m[2]))
+			if !strings.Contains(strings.ToLower(intro), "example") || seen[block] {
+				continue
+			}
+
+			seen[block] = true
+			examples = append(examples, block)
+		}
+
+		if all := emphases.FindAllSubmatch(desc[1], -1); len(all) > 0 {
+			answers[i] = text(string(all[len(all)-1][1]))
+		}
+	}
+
+	return
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const FIXTURE = "testdata/day.html" // 2023 day 1 puzzle page, both parts
+
+func TestParsePage(t *testing.T) {
+	page, err := os.ReadFile(FIXTURE)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	examples, answers := parsePage(page)
+
+	expected := []string{
+		"1abc2\npqr3stu8vwx\na1b2c3d4e5f\ntreb7uchet\n",
+		"two1nine\neightwothree\nabcone2threexyz\nxtwone3four\n4nineeightseven2\nzoneight234\n7pqrstsixteen\n",
+	}
+
+	if len(examples) != len(expected) {
+		t.Fatalf("expected %d examples, got %d: %q", len(expected), len(examples), examples)
+	}
+	for i := range expected {
+		if examples[i] != expected[i] {
+			t.Errorf("example %d: expected %q, got %q", i, expected[i], examples[i])
+		}
+	}
+
+	if answers != [2]string{"142", "281"} {
+		t.Errorf("expected answers [142 281], got %q", answers)
+	}
+}
+
+func TestSample(t *testing.T) {
+	page, err := os.ReadFile(FIXTURE)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /2023/day/1", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(page)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	old := site
+	site = srv.URL
+	defer func() { site = old }()
+
+	tests := []struct {
+		name  string
+		saved string
+	}{
+		{"stand-in", ""},
+		{"saved html", FIXTURE},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "2023", "1")
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				t.Fatal(err)
+			}
+
+			s := setup{dir: dir}
+			if err := s.resolve(); err != nil {
+				t.Fatal(err)
+			}
+
+			saved = tt.saved
+			defer func() { saved = "" }()
+
+			if err := sample(&s, nil); err != nil {
+				t.Fatal(err)
+			}
+
+			files := map[string]string{
+				SAMPLE:        "1abc2\npqr3stu8vwx\na1b2c3d4e5f\ntreb7uchet\n",
+				"sample1.txt": "two1nine\neightwothree\nabcone2threexyz\nxtwone3four\n4nineeightseven2\nzoneight234\n7pqrstsixteen\n",
+				EXPECTS:       "1: 142\n2: 281\n",
+			}
+
+			for name, expected := range files {
+				data, err := os.ReadFile(filepath.Join(dir, name))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(data) != expected {
+					t.Errorf("%s: expected %q, got %q", name, expected, data)
+				}
+			}
+		})
+	}
+}
+
+func TestSampleKeepsExisting(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "2023", "1")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	const mine = "handmade\n"
+	if err := os.WriteFile(filepath.Join(dir, SAMPLE), []byte(mine), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	s := setup{dir: dir}
+	if err := s.resolve(); err != nil {
+		t.Fatal(err)
+	}
+
+	saved = FIXTURE
+	defer func() { saved = "" }()
+
+	if err := sample(&s, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, SAMPLE))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != mine {
+		t.Errorf("sample.txt was overwritten: %q", data)
+	}
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"text/template"
+	"time"
+)
+
+var (
+	serveAddr    string        // see -addr
+	serveTimeout time.Duration // see -timeout
+	serveJobs    int           // see -jobs
+)
+
+func serveFlags(fs *flag.FlagSet) {
+	fs.StringVar(&serveAddr, "addr", "localhost:8025", "listen address")
+	fs.DurationVar(&serveTimeout, "timeout", 30*time.Second, "solve timeout per request, queueing included")
+	fs.IntVar(&serveJobs, "jobs", runtime.NumCPU(), "maximum concurrent solves")
+}
+
+// serveDays serves the days of the selected year, or of every year, that
+// expose a Solve entry point:
+//
+//	POST /solve/{year}/{day}  the raw input in, its json answers out
+//	GET  /metrics             request counts and latencies by day
+//
+// the days are compiled as packages of a generated server, the way aoc run
+// generates its runner, and solved in its process. A panic of Solve only
+// fails its request but a day crashing the server, eg. from one of its
+// goroutines, takes it down: it is then started again
+func serveDays(s *setup, _ []string) error {
+	solvers, err := solvable(s)
+	if err != nil {
+		return err
+	}
+	if len(solvers) == 0 {
+		return fmt.Errorf("no day to serve")
+	}
+
+	tmp, err := os.MkdirTemp("", "aoc")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	bin, err := buildServer(tmp, solvers)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	for {
+		err := runServer(ctx, bin)
+		switch {
+		case ctx.Err() != nil:
+			return nil // interrupted
+		case !crashed(err):
+			return err
+		}
+		log.Printf("server crashed, restarting")
+	}
+}
+
+// runServer runs the server bin until it exits or ctx is done
+func runServer(ctx context.Context, bin string) error {
+	cmd := exec.CommandContext(ctx, bin,
+		"-addr", serveAddr,
+		"-timeout", serveTimeout.String(),
+		"-jobs", strconv.Itoa(serveJobs),
+	)
+	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+	cmd.Cancel = func() error { return cmd.Process.Signal(os.Interrupt) }
+
+	return cmd.Run()
+}
+
+// CRASH is the exit code of a go program dying of a panic or a fatal
+// error, the server exits with 1 on its own errors, eg. a busy address
+const CRASH = 2
+
+// crashed tells a server run ended by a crash
+func crashed(err error) bool {
+	var exit *exec.ExitError
+	return errors.As(err, &exit) && exit.ExitCode() == CRASH
+}
+
+// solvable returns the selected days exposing a Solve entry point
+func solvable(s *setup) ([]solver, error) {
+	years, err := s.years()
+	if err != nil {
+		return nil, err
+	}
+
+	var solvers []solver
+	for _, year := range years {
+		for day := 1; day <= 25; day++ {
+			if s.Day != 0 && day != s.Day {
+				continue
+			}
+
+			dir := filepath.Join(s.root, strconv.Itoa(year), strconv.Itoa(day))
+			if _, err := os.Stat(filepath.Join(dir, fmt.Sprintf(SRC, day))); err != nil {
+				continue
+			}
+
+			files, err := sources(dir)
+			if err != nil {
+				return nil, err
+			}
+			if entryPoint(files) == nil {
+				solvers = append(solvers, solver{Year: year, Day: day, Dir: dir, files: files})
+			}
+		}
+	}
+	return solvers, nil
+}
+
+// SERVER is the generated server main, service.go is copied next to it
+const SERVER = `// Code generated by aoc serve. DO NOT EDIT.
+
+package main
+
+import (
+	"flag"
+	"io"
+	"log"
+	"runtime"
+	"time"
+{{range .}}
+	{{.Alias}} "aocrun/{{.Pkg}}"
+{{- end}}
+)
+
+var days = map[[2]int]dayFunc{
+{{- range .}}
+	{ {{- .Year}}, {{.Day}}}: func(r io.Reader) (any, any, error) {
+		res, err := {{.Alias}}.Solve(r)
+		return res.Part1, res.Part2, err
+	},
+{{- end}}
+}
+
+func main() {
+	addr := flag.String("addr", "localhost:8025", "listen address")
+	timeout := flag.Duration("timeout", 30*time.Second, "solve timeout per request, queueing included")
+	jobs := flag.Int("jobs", runtime.NumCPU(), "maximum concurrent solves")
+	flag.Parse()
+
+	keys := make(map[[2]int]bool, len(days))
+	for key := range days {
+		keys[key] = true
+	}
+
+	h := newServer(keys, inProcess(days), *timeout, *jobs)
+	if err := listen(*addr, h, len(days)); err != nil {
+		log.Fatal(err)
+	}
+}
+`
+
+var serverMain = template.Must(template.New("server").Parse(SERVER))
+
+//go:embed service.go
+var service []byte
+
+// buildServer writes the server module into dir and compiles it
+func buildServer(dir string, solvers []solver) (string, error) {
+	return buildProgram(dir, solvers, serverMain, map[string][]byte{"service.go": service})
+}
@@ -0,0 +1,307 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeSolve answers the input length, fails on "bad", blocks on "slow"
+// until ctx is done and on "hold" until hold is closed
+func fakeSolve(hold <-chan struct{}, busy chan<- struct{}) solveFunc {
+	return func(ctx context.Context, _ [2]int, input []byte) (report, error) {
+		switch string(input) {
+		case "bad":
+			return report{}, errors.New("line 1: bad")
+		case "slow":
+			<-ctx.Done()
+			return report{}, ctx.Err()
+		case "hold":
+			busy <- struct{}{}
+			<-hold
+		}
+		return report{Part1: len(input), Part2: "x", Elapsed: 42}, nil
+	}
+}
+
+func post(t *testing.T, url, body string) (int, solution) {
+	t.Helper()
+
+	resp, err := http.Post(url, "text/plain", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var res solution
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, res
+}
+
+func TestServeSolve(t *testing.T) {
+	days := map[[2]int]bool{{2024, 9}: true}
+	ts := httptest.NewServer(newServer(days, fakeSolve(nil, nil), 50*time.Millisecond, 2))
+	defer ts.Close()
+
+	tests := []struct {
+		name, path, body string
+		code             int
+		expected         string // part 1 or error
+	}{
+		{"ok", "/solve/2024/9", "2333133121414131402", http.StatusOK, "19"},
+		{"unknown day", "/solve/2024/10", "", http.StatusNotFound, "no solver for 2024/10"},
+		{"bad year", "/solve/x/9", "", http.StatusNotFound, "no solver for x/9"},
+		{"malformed", "/solve/2024/9", "bad", http.StatusUnprocessableEntity, "line 1: bad"},
+		{"timeout", "/solve/2024/9", "slow", http.StatusGatewayTimeout, "no answer within 50ms"},
+		{"too large", "/solve/2024/9", strings.Repeat("1", MAXINPUT+1), http.StatusRequestEntityTooLarge, fmt.Sprintf("input over %d bytes", MAXINPUT)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, res := post(t, ts.URL+tt.path, tt.body)
+			if code != tt.code {
+				t.Errorf("status %d, expected %d", code, tt.code)
+			}
+
+			got := res.Error
+			if code == http.StatusOK {
+				got = fmt.Sprint(res.Part1)
+				if res.Year != 2024 || res.Day != 9 || res.Part2 != "x" || res.Elapsed != 42 {
+					t.Errorf("solution = %+v, expected 2024-9 x in 42ns", res)
+				}
+			}
+			if got != tt.expected {
+				t.Errorf("got %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+
+	resp, err := http.Get(ts.URL + "/solve/2024/9")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("GET status %d, expected %d", resp.StatusCode, http.StatusMethodNotAllowed)
+	}
+}
+
+func TestServeLimit(t *testing.T) {
+	hold, busy := make(chan struct{}), make(chan struct{})
+
+	days := map[[2]int]bool{{2024, 9}: true}
+	ts := httptest.NewServer(newServer(days, fakeSolve(hold, busy), 100*time.Millisecond, 1))
+	defer ts.Close()
+
+	done := make(chan int)
+	go func() {
+		resp, err := http.Post(ts.URL+"/solve/2024/9", "text/plain", strings.NewReader("hold"))
+		if err != nil {
+			done <- 0
+			return
+		}
+		resp.Body.Close()
+		done <- resp.StatusCode
+	}()
+	<-busy // the only slot is taken
+
+	if code, res := post(t, ts.URL+"/solve/2024/9", "queued"); code != http.StatusServiceUnavailable {
+		t.Errorf("status %d, %q, expected %d", code, res.Error, http.StatusServiceUnavailable)
+	}
+
+	close(hold)
+	if code := <-done; code != http.StatusGatewayTimeout {
+		t.Errorf("held request status %d, expected %d", code, http.StatusGatewayTimeout)
+	}
+
+	if code, _ := post(t, ts.URL+"/solve/2024/9", "free"); code != http.StatusOK {
+		t.Errorf("status %d once the slot is free, expected %d", code, http.StatusOK)
+	}
+}
+
+func TestServeMetrics(t *testing.T) {
+	days := map[[2]int]bool{{2024, 9}: true, {2025, 1}: true}
+	ts := httptest.NewServer(newServer(days, fakeSolve(nil, nil), time.Second, 2))
+	defer ts.Close()
+
+	for _, body := range []string{"a", "b", "bad"} {
+		post(t, ts.URL+"/solve/2024/9", body)
+	}
+	post(t, ts.URL+"/solve/2025/1", "c")
+	post(t, ts.URL+"/solve/2025/2", "unknown") // not a registered day
+
+	resp, err := http.Get(ts.URL + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	metrics := string(data)
+
+	for _, expected := range []string{
+		"# TYPE aoc_solve_requests_total counter\n",
+		`aoc_solve_requests_total{year="2024",day="9",code="200"} 2` + "\n",
+		`aoc_solve_requests_total{year="2024",day="9",code="422"} 1` + "\n",
+		`aoc_solve_requests_total{year="2025",day="1",code="200"} 1` + "\n",
+		"# TYPE aoc_solve_duration_seconds histogram\n",
+		`aoc_solve_duration_seconds_bucket{year="2024",day="9",le="30"} 3` + "\n",
+		`aoc_solve_duration_seconds_bucket{year="2024",day="9",le="+Inf"} 3` + "\n",
+		`aoc_solve_duration_seconds_count{year="2024",day="9"} 3` + "\n",
+		`aoc_solve_duration_seconds_count{year="2025",day="1"} 1` + "\n",
+	} {
+		if !strings.Contains(metrics, expected) {
+			t.Errorf("metrics lack %q", expected)
+		}
+	}
+	if strings.Contains(metrics, `day="2"`) {
+		t.Error("metrics count an unknown day")
+	}
+	if strings.Index(metrics, `year="2024"`) > strings.Index(metrics, `year="2025"`) {
+		t.Error("metrics are not sorted by day")
+	}
+}
+
+func TestServeInProcess(t *testing.T) {
+	running := 0 // solves of 2024-9 under way
+
+	days := map[[2]int]dayFunc{
+		{2024, 9}: func(r io.Reader) (any, any, error) {
+			if running++; running > 1 {
+				return nil, nil, errors.New("solved twice at once")
+			}
+			defer func() { running-- }()
+
+			data, _ := io.ReadAll(r)
+			if string(data) == "panic" {
+				panic("index out of range")
+			}
+			time.Sleep(time.Millisecond)
+			return len(data), "x", nil
+		},
+	}
+
+	keys := map[[2]int]bool{{2024, 9}: true}
+	ts := httptest.NewServer(newServer(keys, inProcess(days), time.Second, 4))
+	defer ts.Close()
+
+	code, res := post(t, ts.URL+"/solve/2024/9", "panic")
+	if code != http.StatusUnprocessableEntity || res.Error != "panic: index out of range" {
+		t.Errorf("status %d, %q, expected %d panic", code, res.Error, http.StatusUnprocessableEntity)
+	}
+
+	codes := make(chan int)
+	for range 4 {
+		go func() {
+			resp, err := http.Post(ts.URL+"/solve/2024/9", "text/plain", strings.NewReader("input"))
+			if err != nil {
+				codes <- 0
+				return
+			}
+			resp.Body.Close()
+			codes <- resp.StatusCode
+		}()
+	}
+	for range 4 {
+		if code := <-codes; code != http.StatusOK {
+			t.Errorf("status %d, expected %d for solves of a day in turn", code, http.StatusOK)
+		}
+	}
+}
+
+// SERVED is a tiny day solver, it fails on an empty input
+const SERVED = `package main
+
+import (
+	"errors"
+	"io"
+)
+
+type Result struct{ Part1, Part2 any }
+
+func main() {}
+
+func Solve(r io.Reader) (Result, error) {
+	data, _ := io.ReadAll(r)
+	if len(data) == 0 {
+		return Result{}, errors.New("empty input")
+	}
+	return Result{len(data), "served"}, nil
+}
+`
+
+func TestServeBuild(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds the server")
+	}
+
+	root := t.TempDir()
+	dir := filepath.Join(root, "2023", "5")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf(SRC, 5)), []byte(SERVED), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	s := setup{dir: root, Year: 2023}
+	if err := s.resolve(); err != nil {
+		t.Fatal(err)
+	}
+
+	solvers, err := solvable(&s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bin, err := buildServer(t.TempDir(), solvers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(bin, "-addr", "127.0.0.1:0")
+	errs, err := cmd.StderrPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer cmd.Wait()
+	defer cmd.Process.Kill()
+
+	line, err := bufio.NewReader(errs).ReadString('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, url, ok := strings.Cut(strings.TrimSpace(line), " on ")
+	if !ok {
+		t.Fatalf("no address in %q", line)
+	}
+
+	code, res := post(t, url+"/solve/2023/5", "input\n")
+	if code != http.StatusOK || fmt.Sprint(res.Part1) != "6" || res.Part2 != "served" {
+		t.Errorf("status %d, %+v, expected 6 served", code, res)
+	}
+
+	code, res = post(t, url+"/solve/2023/5", "")
+	if code != http.StatusUnprocessableEntity || res.Error != "empty input" {
+		t.Errorf("status %d, %q, expected %d empty input", code, res.Error, http.StatusUnprocessableEntity)
+	}
+}
@@ -0,0 +1,312 @@
+// service.go is the solve service aoc serve generates its server from: the
+// file is embedded and copied as is next to the generated main, so it only
+// depends on the standard library
+
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// MAXINPUT bounds the input of a solve request
+const MAXINPUT = 8 << 20
+
+// LATENCY are the upper bounds of the solve latency histogram buckets
+var LATENCY = []time.Duration{
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	5 * time.Second,
+	10 * time.Second,
+	30 * time.Second,
+}
+
+// report is a solver json report
+type report struct {
+	Year    int    `json:"year"`
+	Day     int    `json:"day"`
+	Part1   any    `json:"part1"`
+	Part2   any    `json:"part2"`
+	Elapsed int64  `json:"elapsed_ns"`
+	Allocs  uint64 `json:"allocs,omitempty"`
+	Bytes   uint64 `json:"alloc_bytes,omitempty"`
+}
+
+// dayFunc is the Solve of a day, its parts unwrapped
+type dayFunc func(io.Reader) (any, any, error)
+
+// solveFunc solves a day input, it gives up when ctx is done
+type solveFunc func(ctx context.Context, key [2]int, input []byte) (report, error)
+
+// inProcess solves the days in this process, a single solve of a day at a
+// time as days keep package state. A solve given up past its deadline runs
+// to its end, the next solve of its day waits for it
+func inProcess(days map[[2]int]dayFunc) solveFunc {
+	locks := make(map[[2]int]*sync.Mutex, len(days))
+	for key := range days {
+		locks[key] = new(sync.Mutex)
+	}
+
+	type answer struct {
+		report
+		err error
+	}
+
+	solve := func(key [2]int, input []byte) (a answer) {
+		defer func() {
+			if x := recover(); x != nil {
+				a.err = fmt.Errorf("panic: %v", x)
+			}
+		}()
+
+		t0 := time.Now()
+		a.Part1, a.Part2, a.err = days[key](bytes.NewReader(input))
+		a.Elapsed = time.Since(t0).Nanoseconds()
+		return
+	}
+
+	return func(ctx context.Context, key [2]int, input []byte) (report, error) {
+		done := make(chan answer, 1)
+
+		go func() {
+			locks[key].Lock()
+			defer locks[key].Unlock()
+
+			if err := ctx.Err(); err != nil {
+				done <- answer{err: err} // given up while waiting
+				return
+			}
+			done <- solve(key, input)
+		}()
+
+		select {
+		case a := <-done:
+			return a.report, a.err
+		case <-ctx.Done():
+			return report{}, ctx.Err()
+		}
+	}
+}
+
+// listen serves h on addr until interrupted
+func listen(addr string, h http.Handler, ndays int) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	srv := &http.Server{Handler: h}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	go func() {
+		<-ctx.Done()
+		srv.Shutdown(context.Background())
+	}()
+
+	log.Printf("serving %d days on http://%s", ndays, ln.Addr())
+	if err := srv.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// server is the solve service, it runs at most cap(slots) solves at once
+type server struct {
+	days    map[[2]int]bool
+	solve   solveFunc
+	timeout time.Duration
+	slots   chan struct{}
+	traffic traffic
+
+	mux *http.ServeMux
+}
+
+func newServer(days map[[2]int]bool, solve solveFunc, timeout time.Duration, jobs int) *server {
+	sv := &server{
+		days:    days,
+		solve:   solve,
+		timeout: timeout,
+		slots:   make(chan struct{}, max(jobs, 1)),
+		traffic: traffic{days: make(map[[2]int]*dayTraffic)},
+		mux:     http.NewServeMux(),
+	}
+
+	sv.mux.HandleFunc("POST /solve/{year}/{day}", sv.handleSolve)
+	sv.mux.HandleFunc("GET /metrics", sv.handleMetrics)
+	return sv
+}
+
+func (sv *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	sv.mux.ServeHTTP(w, r)
+}
+
+// solution is the json answer to a solve request, a failed one has an error
+// instead of parts
+type solution struct {
+	report
+	Error string `json:"error,omitempty"`
+}
+
+func (sv *server) handleSolve(w http.ResponseWriter, r *http.Request) {
+	year, _ := strconv.Atoi(r.PathValue("year"))
+	day, _ := strconv.Atoi(r.PathValue("day"))
+
+	key := [2]int{year, day}
+	if !sv.days[key] {
+		reply(w, http.StatusNotFound, solution{Error: fmt.Sprintf("no solver for %s/%s", r.PathValue("year"), r.PathValue("day"))})
+		return
+	}
+
+	t0 := time.Now()
+	code, res := sv.solveDay(w, r, key)
+	sv.traffic.observe(key, code, time.Since(t0))
+
+	res.Year, res.Day = year, day
+	reply(w, code, res)
+}
+
+// solveDay reads the input of a request, waits for a free slot and solves
+// it, all within the server timeout
+func (sv *server) solveDay(w http.ResponseWriter, r *http.Request, key [2]int) (int, solution) {
+	fail := func(code int, format string, args ...any) (int, solution) {
+		return code, solution{Error: fmt.Sprintf(format, args...)}
+	}
+
+	input, err := io.ReadAll(http.MaxBytesReader(w, r.Body, MAXINPUT))
+	if tooBig := new(http.MaxBytesError); errors.As(err, &tooBig) {
+		return fail(http.StatusRequestEntityTooLarge, "input over %d bytes", tooBig.Limit)
+	}
+	if err != nil {
+		return fail(http.StatusBadRequest, "reading input: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), sv.timeout)
+	defer cancel()
+
+	select {
+	case sv.slots <- struct{}{}:
+		defer func() { <-sv.slots }()
+	case <-ctx.Done():
+		return fail(http.StatusServiceUnavailable, "no free solver within %v", sv.timeout)
+	}
+
+	res, err := sv.solve(ctx, key, input)
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return fail(http.StatusGatewayTimeout, "no answer within %v", sv.timeout)
+	case err != nil:
+		return fail(http.StatusUnprocessableEntity, "%v", err)
+	}
+	return http.StatusOK, solution{report: res}
+}
+
+func (sv *server) handleMetrics(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	sv.traffic.WriteTo(w)
+}
+
+func reply(w http.ResponseWriter, code int, res solution) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(res)
+}
+
+// traffic is the solve requests counts and latencies by day
+type traffic struct {
+	sync.Mutex
+	days map[[2]int]*dayTraffic
+}
+
+type dayTraffic struct {
+	codes   map[int]int // requests by status code
+	buckets []int       // requests by LATENCY bucket, the last one unbounded
+	sum     time.Duration
+	count   int
+}
+
+func (st *traffic) observe(key [2]int, code int, δ time.Duration) {
+	st.Lock()
+	defer st.Unlock()
+
+	d, ok := st.days[key]
+	if !ok {
+		d = &dayTraffic{codes: make(map[int]int), buckets: make([]int, len(LATENCY)+1)}
+		st.days[key] = d
+	}
+
+	d.codes[code]++
+	d.buckets[bucket(δ)]++
+	d.sum += δ
+	d.count++
+}
+
+// bucket returns the LATENCY bucket of δ
+func bucket(δ time.Duration) int {
+	i, _ := slices.BinarySearch(LATENCY, δ)
+	return i
+}
+
+// WriteTo writes the traffic in the prometheus text format
+func (st *traffic) WriteTo(w io.Writer) (int64, error) {
+	st.Lock()
+	defer st.Unlock()
+
+	keys := slices.SortedFunc(maps.Keys(st.days), func(a, b [2]int) int {
+		return cmp.Or(a[0]-b[0], a[1]-b[1])
+	})
+
+	var buf bytes.Buffer
+
+	fmt.Fprintln(&buf, "# HELP aoc_solve_requests_total Solve requests by day and status code.")
+	fmt.Fprintln(&buf, "# TYPE aoc_solve_requests_total counter")
+	for _, k := range keys {
+		d := st.days[k]
+		for _, code := range slices.Sorted(maps.Keys(d.codes)) {
+			fmt.Fprintf(&buf, "aoc_solve_requests_total{year=\"%d\",day=\"%d\",code=\"%d\"} %d\n", k[0], k[1], code, d.codes[code])
+		}
+	}
+
+	fmt.Fprintln(&buf, "# HELP aoc_solve_duration_seconds Solve request latency by day.")
+	fmt.Fprintln(&buf, "# TYPE aoc_solve_duration_seconds histogram")
+	for _, k := range keys {
+		d := st.days[k]
+		labels := fmt.Sprintf("year=\"%d\",day=\"%d\"", k[0], k[1])
+
+		n := 0
+		for i, count := range d.buckets {
+			n += count // cumulative
+
+			le := "+Inf"
+			if i < len(LATENCY) {
+				le = strconv.FormatFloat(LATENCY[i].Seconds(), 'g', -1, 64)
+			}
+			fmt.Fprintf(&buf, "aoc_solve_duration_seconds_bucket{%s,le=\"%s\"} %d\n", labels, le, n)
+		}
+		fmt.Fprintf(&buf, "aoc_solve_duration_seconds_sum{%s} %g\n", labels, d.sum.Seconds())
+		fmt.Fprintf(&buf, "aoc_solve_duration_seconds_count{%s} %d\n", labels, d.count)
+	}
+
+	return buf.WriteTo(w)
+}
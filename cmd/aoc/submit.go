@@ -0,0 +1,234 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ANSWERS = "answers.txt" // accepted answers, one "part: answer" per line
+	POST    = "%s/%d/day/%d/answer"
+)
+
+var part int // submitted part
+
+func submitFlags(fs *flag.FlagSet) {
+	fs.IntVar(&part, "part", 1, "puzzle part: 1 or 2")
+}
+
+// verdict is the typed outcome of a submission
+type verdict int
+
+const (
+	UNKNOWN verdict = iota
+	CORRECT
+	TOOHIGH
+	TOOLOW
+	WRONG
+	RATELIMITED
+	SOLVED
+)
+
+func (v verdict) String() string {
+	return [...]string{
+		UNKNOWN:     "unknown",
+		CORRECT:     "correct",
+		TOOHIGH:     "too high",
+		TOOLOW:      "too low",
+		WRONG:       "wrong",
+		RATELIMITED: "rate limited",
+		SOLVED:      "already solved",
+	}[v]
+}
+
+// result is a parsed answer page
+type result struct {
+	verdict verdict
+	wait    time.Duration // before next submission, if any
+	msg     string        // page article text
+}
+
+// submit posts an answer for the selected day and part and records it
+// into the day answers file when accepted
+func submit(s *setup, args []string) error {
+	dir, err := s.dayDir()
+	if err != nil {
+		return err
+	}
+
+	if part != 1 && part != 2 {
+		return fmt.Errorf("bad part %d: use -part 1 or 2", part)
+	}
+
+	if len(args) != 1 || len(strings.TrimSpace(args[0])) == 0 {
+		return errors.New("usage: aoc submit [-part 1|2] <answer>")
+	}
+	answer := strings.TrimSpace(args[0])
+
+	session := os.Getenv("SESSION")
+	if len(session) == 0 {
+		return errors.New("$SESSION not set")
+	}
+
+	res, err := postAnswer(s.Year, s.Day, part, answer, session)
+	if err != nil {
+		return fmt.Errorf("error submitting answer: %w", err)
+	}
+
+	switch res.verdict {
+	case CORRECT:
+		if err := record(filepath.Join(dir, ANSWERS), part, answer); err != nil {
+			return err
+		}
+		fmt.Printf("%d-%d part %d: %s is correct\n", s.Year, s.Day, part, answer)
+	case RATELIMITED:
+		fmt.Printf("%d-%d part %d: rate limited, wait %v\n", s.Year, s.Day, part, res.wait)
+	case UNKNOWN:
+		return fmt.Errorf("unexpected reply: %s", res.msg)
+	default:
+		fmt.Printf("%d-%d part %d: %s is %v\n", s.Year, s.Day, part, answer, res.verdict)
+	}
+
+	return nil
+}
+
+// postAnswer submits answer and parses the reply
+func postAnswer(year, day, part int, answer, session string) (result, error) {
+	form := url.Values{
+		"level":  {strconv.Itoa(part)},
+		"answer": {answer},
+	}
+
+	req, err := newRequest("POST", fmt.Sprintf(POST, site, year, day), session, strings.NewReader(form.Encode()))
+	if err != nil {
+		return result{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return result{}, fmt.Errorf("error making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return result{}, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	page, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return result{}, fmt.Errorf("error reading reply: %w", err)
+	}
+
+	return parseAnswer(page), nil
+}
+
+var (
+	article = regexp.MustCompile(`(?s)<article[^>]*>(.*?)</article>`)
+	tags    = regexp.MustCompile(`<[^>]*>`)
+
+	timeleft = regexp.MustCompile(`You have (?:(\d+)m )?(\d+)s left to wait`)
+	minutes  = regexp.MustCompile(`wait (one|\d+) minutes?`)
+)
+
+// parseAnswer reads the verdict out of an answer page
+func parseAnswer(page []byte) result {
+	text := string(page)
+	if m := article.FindStringSubmatch(text); m != nil {
+		text = m[1]
+	}
+	text = strings.Join(strings.Fields(html.UnescapeString(tags.ReplaceAllString(text, ""))), " ")
+
+	res := result{msg: text}
+
+	switch {
+	case strings.Contains(text, "That's the right answer"):
+		res.verdict = CORRECT
+	case strings.Contains(text, "You gave an answer too recently"):
+		res.verdict = RATELIMITED
+		if m := timeleft.FindStringSubmatch(text); m != nil {
+			mm, _ := strconv.Atoi(m[1])
+			ss, _ := strconv.Atoi(m[2])
+			res.wait = time.Duration(mm)*time.Minute + time.Duration(ss)*time.Second
+		}
+	case strings.Contains(text, "You don't seem to be solving the right level"):
+		res.verdict = SOLVED
+	case strings.Contains(text, "That's not the right answer"):
+		switch {
+		case strings.Contains(text, "your answer is too high"):
+			res.verdict = TOOHIGH
+		case strings.Contains(text, "your answer is too low"):
+			res.verdict = TOOLOW
+		default:
+			res.verdict = WRONG
+		}
+
+		if m := minutes.FindStringSubmatch(text); m != nil {
+			n := 1
+			if m[1] != "one" {
+				n, _ = strconv.Atoi(m[1])
+			}
+			res.wait = time.Duration(n) * time.Minute
+		}
+	}
+
+	return res
+}
+
+// readAnswers reads an answers file into a part indexed array, missing
+// parts are empty
+func readAnswers(name string) ([3]string, error) {
+	var answers [3]string
+
+	fd, err := os.Open(name)
+	if err != nil {
+		return answers, err
+	}
+	defer fd.Close()
+
+	input := bufio.NewScanner(fd)
+	for input.Scan() {
+		lhs, rhs, ok := strings.Cut(input.Text(), ":")
+		if !ok {
+			continue
+		}
+
+		i, err := strconv.Atoi(strings.TrimSpace(lhs))
+		if err != nil || i < 1 || i > 2 {
+			continue
+		}
+		answers[i] = strings.TrimSpace(rhs)
+	}
+
+	return answers, input.Err()
+}
+
+// record stores an accepted answer, keeping parts in order
+func record(name string, part int, answer string) error {
+	answers, err := readAnswers(name)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	answers[part] = answer
+
+	var sb strings.Builder
+	for i, a := range answers[1:] {
+		if a != "" {
+			fmt.Fprintf(&sb, "%d: %s\n", i+1, a)
+		}
+	}
+
+	return os.WriteFile(name, []byte(sb.String()), 0o644)
+}
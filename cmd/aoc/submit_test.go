@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// replies are adventofcode.com answer pages, trimmed to their article
+var replies = map[string]string{
+	"42":  `<main><article><p>That's the right answer!  You are <em>one gold star</em> closer to saving Christmas. <a href="/2023/day/1#part2">[Continue to Part Two]</a></p></article></main>`,
+	"99":  `<main><article><p>That's not the right answer; your answer is too high.  If you're stuck, make sure you're using the full input data; please wait one minute before trying again. <a href="/2023/day/1">[Return to Day 1]</a></p></article></main>`,
+	"1":   `<main><article><p>That's not the right answer; your answer is too low.  please wait 5 minutes before trying again.</p></article></main>`,
+	"7":   `<main><article><p>That's not the right answer.  If you're stuck, make sure you're using the full input data.</p></article></main>`,
+	"0":   `<main><article><p>You gave an answer too recently; you have to wait after submitting an answer before trying again.  You have 1m 23s left to wait. <a href="/2023/day/1">[Return to Day 1]</a></p></article></main>`,
+	"-1":  `<main><article><p>You gave an answer too recently.  You have 37s left to wait.</p></article></main>`,
+	"old": `<main><article><p>You don't seem to be solving the right level.  Did you already complete it? <a href="/2023/day/1">[Return to Day 1]</a></p></article></main>`,
+}
+
+// standin serves answer pages keyed by the submitted answer
+func standin(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /{year}/day/{day}/answer", func(w http.ResponseWriter, r *http.Request) {
+		if c, err := r.Cookie("session"); err != nil || c.Value != "cafe" {
+			http.Error(w, "Please log in", http.StatusBadRequest)
+			return
+		}
+
+		if r.FormValue("level") != "1" && r.FormValue("level") != "2" {
+			http.Error(w, "bad level", http.StatusBadRequest)
+			return
+		}
+
+		page, ok := replies[r.FormValue("answer")]
+		if !ok {
+			page = "<main><article><p>?</p></article></main>"
+		}
+		fmt.Fprint(w, "<html><body>"+page+"</body></html>")
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	old := site
+	site = srv.URL
+	t.Cleanup(func() { site = old })
+
+	return srv
+}
+
+func TestPostAnswer(t *testing.T) {
+	standin(t)
+
+	tests := []struct {
+		answer  string
+		verdict verdict
+		wait    time.Duration
+	}{
+		{"42", CORRECT, 0},
+		{"99", TOOHIGH, time.Minute},
+		{"1", TOOLOW, 5 * time.Minute},
+		{"7", WRONG, 0},
+		{"0", RATELIMITED, time.Minute + 23*time.Second},
+		{"-1", RATELIMITED, 37 * time.Second},
+		{"old", SOLVED, 0},
+		{"nope", UNKNOWN, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.answer, func(t *testing.T) {
+			res, err := postAnswer(2023, 1, 1, tt.answer, "cafe")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if res.verdict != tt.verdict || res.wait != tt.wait {
+				t.Errorf("answer %s: got %v (wait %v), expected %v (wait %v)",
+					tt.answer, res.verdict, res.wait, tt.verdict, tt.wait)
+			}
+		})
+	}
+}
+
+func TestPostAnswerLoggedOut(t *testing.T) {
+	standin(t)
+
+	if _, err := postAnswer(2023, 1, 1, "42", "dead"); err == nil {
+		t.Error("expected an error on a bad session")
+	}
+}
+
+func TestSubmitRecords(t *testing.T) {
+	standin(t)
+	t.Setenv("SESSION", "cafe")
+
+	root := t.TempDir()
+	dir := filepath.Join(root, "2023", "1")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	s := setup{dir: dir}
+	if err := s.resolve(); err != nil {
+		t.Fatal(err)
+	}
+
+	steps := []struct {
+		part   int
+		answer string
+	}{
+		{2, "42"},
+		{1, "42"},
+		{1, "99"}, // rejected, not recorded
+	}
+
+	for _, st := range steps {
+		part = st.part
+		if err := submit(&s, []string{st.answer}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	part = 1
+
+	data, err := os.ReadFile(filepath.Join(dir, ANSWERS))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := "1: 42\n2: 42\n"; string(data) != expected {
+		t.Errorf("answers file: got %q, expected %q", data, expected)
+	}
+}
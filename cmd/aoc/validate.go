@@ -0,0 +1,311 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp/syntax"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// GRAMMAR is the day input format, one line pattern per input line:
+//
+//	Register [ABC]: \d+
+//	+ [a-z]{3} (AND|OR|XOR) [a-z]{3}
+//
+// a pattern is an anchored regexp, a leading "+ ", "* " or "? " repeats it
+// one or more, any or at most one time and an empty pattern is a blank line
+const GRAMMAR = "input.grammar"
+
+// validate checks the inputs and examples of the selected day, year or of
+// every year against their day grammar
+func validate(s *setup, _ []string) error {
+	years, err := s.years()
+	if err != nil {
+		return err
+	}
+
+	var nfail, nfile int
+	for _, year := range years {
+		for day := 1; day <= 25; day++ {
+			if s.Day != 0 && day != s.Day {
+				continue
+			}
+
+			dir := filepath.Join(s.root, strconv.Itoa(year), strconv.Itoa(day))
+			if _, err := os.Stat(filepath.Join(dir, fmt.Sprintf(SRC, day))); err != nil {
+				continue
+			}
+
+			g, err := readGrammar(filepath.Join(dir, GRAMMAR))
+			if errors.Is(err, os.ErrNotExist) {
+				warnf("%d-%d: no %s", year, day, GRAMMAR)
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			names, _ := filepath.Glob(filepath.Join(dir, "sample*.txt"))
+			names = append([]string{filepath.Join(dir, INPUT)}, names...)
+
+			for _, name := range names {
+				data, err := os.ReadFile(name)
+				if errors.Is(err, os.ErrNotExist) {
+					continue
+				}
+				if err != nil {
+					return err
+				}
+				nfile++
+
+				if err := g.check(data); err != nil {
+					rel, _ := filepath.Rel(s.root, name)
+					fmt.Printf("%s:%v\n", rel, err)
+					nfail++
+				}
+			}
+		}
+	}
+
+	fmt.Printf("\n%d files checked, %d malformed\n", nfile, nfail)
+
+	if nfail > 0 {
+		return fmt.Errorf("%d malformed files", nfail)
+	}
+	return nil
+}
+
+// rule is a grammar line: a pattern and how many input lines it matches
+type rule struct {
+	text     string // as declared
+	prog     *syntax.Prog
+	min, max int // max < 0 is unbounded
+}
+
+// grammar is a day input format, see GRAMMAR
+type grammar []rule
+
+// readGrammar reads and compiles a grammar file
+func readGrammar(name string) (grammar, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	g, err := parseGrammar(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", name, err)
+	}
+	return g, nil
+}
+
+// parseGrammar compiles the line patterns of a grammar
+func parseGrammar(text string) (grammar, error) {
+	var g grammar
+	for i, line := range splitLines(text) {
+		r := rule{text: line, min: 1, max: 1}
+
+		if len(line) > 1 && line[1] == ' ' && strings.ContainsRune("+*?", rune(line[0])) {
+			switch line[0] {
+			case '+':
+				r.max = -1
+			case '*':
+				r.min, r.max = 0, -1
+			case '?':
+				r.min = 0
+			}
+			line = line[2:]
+		}
+
+		re, err := syntax.Parse(line, syntax.Perl)
+		if err != nil {
+			return nil, fmt.Errorf("%d: %w", i+1, err)
+		}
+		if r.prog, err = syntax.Compile(re.Simplify()); err != nil {
+			return nil, fmt.Errorf("%d: %w", i+1, err)
+		}
+
+		g = append(g, r)
+	}
+	return g, nil
+}
+
+// splitLines splits a text into lines, trailing blank lines aside
+func splitLines(text string) []string {
+	text = strings.TrimRight(text, "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
+
+// inputError locates where an input departs from its grammar
+type inputError struct {
+	line, col int
+	expected  string
+	got       string
+}
+
+func (e *inputError) Error() string {
+	return fmt.Sprintf("%d:%d: expected \"%s\", got %s", e.line, e.col, e.expected, e.got)
+}
+
+// check matches every input line against the grammar and reports the
+// furthest point reached when it doesn't match
+func (g grammar) check(input []byte) error {
+	lines := splitLines(string(input))
+
+	var worst *inputError
+	fail := func(e *inputError) {
+		if worst == nil || e.line > worst.line || e.line == worst.line && e.col > worst.col {
+			worst = e
+		}
+	}
+
+	// dead memoizes the (rule, line) positions known not to match
+	dead := make(map[[2]int]bool)
+
+	var match func(i, j int) bool
+	match = func(i, j int) bool {
+		if dead[[2]int{i, j}] {
+			return false
+		}
+
+		if i == len(g) {
+			if j == len(lines) {
+				return true
+			}
+			fail(&inputError{line: j + 1, col: 1, expected: "end of input", got: strconv.Quote(lines[j])})
+			dead[[2]int{i, j}] = true
+			return false
+		}
+
+		r := g[i]
+
+		// greedily take as many lines as the rule allows, then backtrack
+		n := j
+		for (r.max < 0 || n-j < r.max) && n < len(lines) {
+			col, ok := accept(r.prog, lines[n])
+			if !ok {
+				fail(&inputError{line: n + 1, col: col + 1, expected: r.text, got: describe(lines[n], col)})
+				break
+			}
+			n++
+		}
+		if n == len(lines) && n-j < r.min {
+			fail(&inputError{line: n + 1, col: 1, expected: r.text, got: "end of input"})
+		}
+
+		for ; n-j >= r.min; n-- {
+			if match(i+1, n) {
+				return true
+			}
+		}
+
+		dead[[2]int{i, j}] = true
+		return false
+	}
+
+	if match(0, 0) {
+		return nil
+	}
+	return worst
+}
+
+// describe tells what stands at col in line
+func describe(line string, col int) string {
+	if col >= len(line) {
+		return "end of line"
+	}
+
+	r, _ := utf8.DecodeRuneInString(line[col:])
+	if r == '\r' {
+		return `"\r" (CRLF line endings?)`
+	}
+	return strconv.QuoteRune(r)
+}
+
+// accept runs prog over the whole of line: ok tells if line matches,
+// otherwise col is the offset of the first byte no match can go through
+func accept(prog *syntax.Prog, line string) (col int, ok bool) {
+	var cur, nxt []uint32
+	seen := make([]int, len(prog.Inst)) // generation + 1 a pc was added at
+
+	// add follows the empty transitions from pc at offset i of line
+	var add func(set []uint32, pc uint32, i, gen int) []uint32
+	add = func(set []uint32, pc uint32, i, gen int) []uint32 {
+		if seen[pc] == gen+1 {
+			return set
+		}
+		seen[pc] = gen + 1
+
+		inst := &prog.Inst[pc]
+		switch inst.Op {
+		case syntax.InstAlt, syntax.InstAltMatch:
+			set = add(set, inst.Out, i, gen)
+			set = add(set, inst.Arg, i, gen)
+		case syntax.InstCapture, syntax.InstNop:
+			set = add(set, inst.Out, i, gen)
+		case syntax.InstEmptyWidth:
+			before, after := rune(-1), rune(-1)
+			if i > 0 {
+				before, _ = utf8.DecodeLastRuneInString(line[:i])
+			}
+			if i < len(line) {
+				after, _ = utf8.DecodeRuneInString(line[i:])
+			}
+			if syntax.EmptyOp(inst.Arg)&^syntax.EmptyOpContext(before, after) == 0 {
+				set = add(set, inst.Out, i, gen)
+			}
+		case syntax.InstFail:
+		default: // runes and match
+			set = append(set, pc)
+		}
+		return set
+	}
+
+	gen := 0
+	cur = add(cur, uint32(prog.Start), 0, gen)
+
+	for i := 0; i < len(line); {
+		r, size := utf8.DecodeRuneInString(line[i:])
+
+		gen++
+		nxt = nxt[:0]
+		for _, pc := range cur {
+			inst := &prog.Inst[pc]
+
+			var ok bool
+			switch inst.Op {
+			case syntax.InstRune:
+				ok = inst.MatchRune(r)
+			case syntax.InstRune1:
+				ok = r == inst.Rune[0]
+			case syntax.InstRuneAny:
+				ok = true
+			case syntax.InstRuneAnyNotNL:
+				ok = r != '\n'
+			}
+			if ok {
+				nxt = add(nxt, inst.Out, i+size, gen)
+			}
+		}
+		if len(nxt) == 0 {
+			return i, false
+		}
+
+		cur, nxt = nxt, cur
+		i += size
+	}
+
+	for _, pc := range cur {
+		if prog.Inst[pc].Op == syntax.InstMatch {
+			return len(line), true
+		}
+	}
+	return len(line), false
+}
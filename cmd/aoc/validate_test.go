@@ -0,0 +1,102 @@
+package main
+
+import (
+	"regexp/syntax"
+	"testing"
+)
+
+func TestAccept(t *testing.T) {
+	tests := []struct {
+		pattern, line string
+		col           int
+		ok            bool
+	}{
+		{`Register [ABC]: \d+`, "Register A: 729", 15, true},
+		{`Register [ABC]: \d+`, "Register D: 729", 9, false},
+		{`Register [ABC]: \d+`, "Register A: ", 12, false}, // truncated
+		{`Register [ABC]: \d+`, "Register A: 7x", 13, false},
+		{`-?\d+(,-?\d+)*`, "1,-2,3", 6, true},
+		{`-?\d+(,-?\d+)*`, "1,,3", 2, false},
+		{`.*`, "anything", 8, true},
+		{`[.#]+`, "..#\r", 3, false}, // crlf
+		{`\bx\b`, "x", 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			re, err := syntax.Parse(tt.pattern, syntax.Perl)
+			if err != nil {
+				t.Fatal(err)
+			}
+			prog, err := syntax.Compile(re.Simplify())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if col, ok := accept(prog, tt.line); col != tt.col || ok != tt.ok {
+				t.Errorf("accept(%q, %q) = %d, %t, expected %d, %t", tt.pattern, tt.line, col, ok, tt.col, tt.ok)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	const GRAMMAR = `Register [ABC]: \d+
+Register [ABC]: \d+
+Register [ABC]: \d+
+
+Program: [0-7](,[0-7])*
+`
+
+	g, err := parseGrammar(GRAMMAR)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name, input string
+		expected    string
+	}{
+		{"valid", "Register A: 729\nRegister B: 0\nRegister C: 0\n\nProgram: 0,1,5,4,3,0\n\n", ""},
+		{"truncated", "Register A: 729\nRegister B: 0\n", `3:1: expected "Register [ABC]: \d+", got end of input`},
+		{"bad digit", "Register A: 729\nRegister B: 0\nRegister C: 0\n\nProgram: 0,8\n", `5:12: expected "Program: [0-7](,[0-7])*", got '8'`},
+		{"crlf", "Register A: 729\r\n", `1:16: expected "Register [ABC]: \d+", got "\r" (CRLF line endings?)`},
+		{"trailing", "Register A: 729\nRegister B: 0\nRegister C: 0\n\nProgram: 0\nextra\n", `6:1: expected "end of input", got "extra"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := g.check([]byte(tt.input))
+			switch {
+			case tt.expected == "" && err != nil:
+				t.Errorf("check() = %v, expected no error", err)
+			case tt.expected != "" && (err == nil || err.Error() != tt.expected):
+				t.Errorf("check() = %v, expected %s", err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRepeat(t *testing.T) {
+	g, err := parseGrammar("+ \\d+\n* \n? #\n+ [a-z]+\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		input string
+		ok    bool
+	}{
+		{"1\n2\n\n\nab\n", true},
+		{"1\n#\nab\ncd\n", true},
+		{"1\n#\n#\nab\n", false},
+		{"ab\n", false},
+		{"1\n", false},
+	}
+
+	for _, tt := range tests {
+		if err := g.check([]byte(tt.input)); (err == nil) != tt.ok {
+			t.Errorf("check(%q) = %v, expected ok %t", tt.input, err, tt.ok)
+		}
+	}
+}
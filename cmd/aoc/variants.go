@@ -0,0 +1,349 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// VARIANTS registers the alternative solvers of a day, one per line:
+//
+//	# name  source [args...]
+//	reddit  RedditOne
+//	python  aoc24.py
+//
+// a source is a directory or a lone .go file built as a program, a .py script
+// run by python3 or any other executable, all of them relative to the day
+// directory and run from there on the day input
+const VARIANTS = "variants.txt"
+
+var variantRuns int // see -n
+
+func variantsFlags(fs *flag.FlagSet) {
+	fs.IntVar(&variantRuns, "n", 10, "timed runs per variant")
+	fs.BoolVar(&samples, "sample", false, "run on sample.txt instead of input.txt")
+}
+
+// variant is a day solver, the main one or a registered alternative
+type variant struct {
+	name, source string
+	args         []string
+}
+
+// readVariants reads the registered variants of a day
+func readVariants(name string) ([]variant, error) {
+	fd, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+
+	var all []variant
+
+	input := bufio.NewScanner(fd)
+	for i := 1; input.Scan(); i++ {
+		fields := strings.Fields(input.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("%s:%d: expected a name and a source", name, i)
+		}
+		v := variant{name: fields[0], source: fields[1], args: fields[2:]}
+
+		if v.name == "main" || slices.ContainsFunc(all, func(w variant) bool { return w.name == v.name }) {
+			return nil, fmt.Errorf("%s:%d: duplicate variant %s", name, i, v.name)
+		}
+		all = append(all, v)
+	}
+
+	return all, input.Err()
+}
+
+// tally sums up the runs of a variant
+type tally struct {
+	name    string
+	answers []string
+	err     error
+
+	wall, self    time.Duration // medians, self is 0 when not reported
+	allocs, bytes uint64        // from the json report, 0 when not reported
+	rss           int64         // peak resident set size in bytes, 0 when unknown
+}
+
+// compareVariants runs the main solver and the registered variants of the
+// selected day, every day of the selected year or of every year on the
+// same input, checks they agree and compares their timings and allocations
+func compareVariants(s *setup, _ []string) error {
+	years, err := s.years()
+	if err != nil {
+		return err
+	}
+
+	input := INPUT
+	if samples {
+		input = SAMPLE
+	}
+
+	tmp, err := os.MkdirTemp("", "aoc")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	var registered, days, differ int
+	for _, year := range years {
+		for day := 1; day <= 25; day++ {
+			if s.Day != 0 && day != s.Day {
+				continue
+			}
+
+			dir := filepath.Join(s.root, strconv.Itoa(year), strconv.Itoa(day))
+			all, err := readVariants(filepath.Join(dir, VARIANTS))
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			registered++
+
+			if _, err := os.Stat(filepath.Join(dir, input)); err != nil {
+				warnf("%d-%d: no %s", year, day, input)
+				continue
+			}
+
+			out := filepath.Join(tmp, strconv.Itoa(year), strconv.Itoa(day))
+			ms := measureVariants(dir, day, out, input, all)
+			for _, m := range ms {
+				if m.err != nil {
+					warnf("%d-%d %s: %v", year, day, m.name, m.err)
+				}
+			}
+
+			if days > 0 {
+				fmt.Println()
+			}
+			fmt.Printf("%d-%d on %s, %d runs\n", year, day, input, variantRuns)
+			n, err := printVariants(os.Stdout, ms)
+			if err != nil {
+				return err
+			}
+
+			days++
+			differ += n
+		}
+	}
+
+	if registered == 0 {
+		return fmt.Errorf("no day registers %s", VARIANTS)
+	}
+	if differ > 0 {
+		return fmt.Errorf("%d variants disagree", differ)
+	}
+	return nil
+}
+
+// measureVariants builds and runs the main solver of a day and its
+// variants, the main one first
+func measureVariants(dir string, day int, out, input string, all []variant) []tally {
+	all = append([]variant{{name: "main"}}, all...)
+
+	ms := make([]tally, len(all))
+	for i, v := range all {
+		ms[i].name = v.name
+
+		argv, err := variantCommand(dir, day, filepath.Join(out, v.name), v)
+		if err != nil {
+			ms[i].err = err
+			continue
+		}
+		ms[i] = measureVariant(dir, input, v.name, argv)
+	}
+	return ms
+}
+
+// variantCommand builds a variant if need be and returns its command line,
+// the main solver has no source
+func variantCommand(dir string, day int, out string, v variant) ([]string, error) {
+	if err := os.MkdirAll(out, 0o755); err != nil {
+		return nil, err
+	}
+
+	if v.source == "" {
+		bin, err := build(dir, day, out)
+		return []string{bin}, err
+	}
+
+	src := filepath.Join(dir, v.source)
+	fi, err := os.Stat(src)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case fi.IsDir():
+		bin, err := build(src, day, out)
+		return append([]string{bin}, v.args...), err
+
+	case filepath.Ext(src) == ".go":
+		bin := filepath.Join(out, v.name)
+
+		cmd := exec.Command("go", "build", "-o", bin, src)
+		cmd.Dir = dir
+		if msg, err := cmd.CombinedOutput(); err != nil {
+			return nil, fmt.Errorf("building %s: %w\n%s", v.source, err, msg)
+		}
+		return append([]string{bin}, v.args...), nil
+
+	case filepath.Ext(src) == ".py":
+		return append([]string{"python3", src}, v.args...), nil
+	}
+	return append([]string{src}, v.args...), nil
+}
+
+// measureVariant runs argv variantRuns times, the first one also gives the
+// answers
+func measureVariant(dir, input, name string, argv []string) tally {
+	m := tally{name: name}
+
+	var walls, selves []time.Duration
+	for range max(variantRuns, 1) {
+		r, err := runVariant(dir, input, argv)
+		if err != nil {
+			m.err = err
+			return m
+		}
+
+		if m.answers == nil {
+			m.answers = normalize(r.out)
+		}
+		walls = append(walls, r.wall)
+		if r.self > 0 {
+			selves = append(selves, r.self)
+		}
+		m.allocs, m.bytes, m.rss = r.allocs, r.bytes, max(m.rss, r.rss)
+	}
+
+	m.wall = summarize(0, 0, "wall", walls).Median
+	if len(selves) == len(walls) {
+		m.self = summarize(0, 0, "self", selves).Median
+	}
+	return m
+}
+
+// trial is a single variant run
+type trial struct {
+	out        []byte
+	wall, self time.Duration
+	allocs     uint64
+	bytes      uint64
+	rss        int64
+}
+
+// runVariant runs argv in dir in json mode with input as stdin
+func runVariant(dir, input string, argv []string) (trial, error) {
+	in, err := os.Open(filepath.Join(dir, input))
+	if err != nil {
+		return trial{}, err
+	}
+	defer in.Close()
+
+	var out, diag bytes.Buffer
+
+	cmd := exec.Command(argv[0], argv[1:]...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), OUTPUT)
+	cmd.Stdin = in
+	cmd.Stdout = &out
+	cmd.Stderr = &diag
+
+	t0 := time.Now()
+	if err := cmd.Run(); err != nil {
+		return trial{}, fmt.Errorf("running %s: %w\n%s", filepath.Base(argv[0]), err, diag.Bytes())
+	}
+
+	r := trial{out: out.Bytes(), wall: time.Since(t0), rss: maxRSS(cmd.ProcessState)}
+	if rep, ok := parseReport(r.out); ok {
+		r.self = time.Duration(rep.Elapsed)
+		r.allocs, r.bytes = rep.Allocs, rep.Bytes
+	} else if δ, ok := parseDuration(r.out); ok {
+		r.self = δ
+	}
+	return r, nil
+}
+
+// printVariants writes the comparison table of a day and returns how many
+// variants disagree with the main solver, or failed, whose error is logged
+func printVariants(w io.Writer, ms []tally) (int, error) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "variant\tanswers\t\twall\tself\tallocs\talloc\tmax rss")
+
+	dash := func(ok bool, s string) string {
+		if !ok {
+			return "-"
+		}
+		return s
+	}
+
+	differ := 0
+	for _, m := range ms {
+		if m.err != nil {
+			differ++
+			fmt.Fprintf(tw, "%s\t-\tFAILS\t\t\t\t\t\n", m.name)
+			continue
+		}
+
+		agree := "ok"
+		if m.name == "main" {
+			agree = ""
+		} else if ms[0].err != nil || !slices.Equal(m.answers, ms[0].answers) {
+			agree = "DIFFERS"
+			differ++
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%v\t%s\t%s\t%s\t%s\n",
+			m.name, clip(strings.Join(m.answers, " "), 32), agree,
+			m.wall.Round(time.Microsecond),
+			dash(m.self > 0, m.self.Round(time.Microsecond).String()),
+			dash(m.allocs > 0, strconv.FormatUint(m.allocs, 10)),
+			dash(m.bytes > 0, size(int64(m.bytes))),
+			dash(m.rss > 0, size(m.rss)))
+	}
+	return differ, tw.Flush()
+}
+
+// size formats a byte count in binary units
+func size(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// clip shortens s to n runes at most
+func clip(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n-1]) + "…"
+	}
+	return s
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadVariants(t *testing.T) {
+	tests := []struct {
+		name, text string
+		expected   string // name:source:args of each variant, or an error
+	}{
+		{"one", "reddit  RedditOne\n", "reddit:RedditOne:[]"},
+		{"args", "# name source\n\npython aoc6.py 80\nv1 aoc6v1.go\n", "python:aoc6.py:[80] v1:aoc6v1.go:[]"},
+		{"no source", "reddit\n", "variants.txt:1: expected a name and a source"},
+		{"duplicate", "a x.py\na y.py\n", "variants.txt:2: duplicate variant a"},
+		{"main", "main x.py\n", "variants.txt:1: duplicate variant main"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name := filepath.Join(t.TempDir(), VARIANTS)
+			if err := os.WriteFile(name, []byte(tt.text), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			all, err := readVariants(name)
+
+			var got []string
+			for _, v := range all {
+				got = append(got, fmt.Sprintf("%s:%s:%v", v.name, v.source, v.args))
+			}
+			if err != nil {
+				got = []string{strings.TrimPrefix(err.Error(), filepath.Dir(name)+string(filepath.Separator))}
+			}
+
+			if s := strings.Join(got, " "); s != tt.expected {
+				t.Errorf("readVariants() = %q, expected %q", s, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSize(t *testing.T) {
+	tests := []struct {
+		n        int64
+		expected string
+	}{
+		{0, "0B"},
+		{1023, "1023B"},
+		{1536, "1.5KiB"},
+		{10 << 20, "10.0MiB"},
+	}
+
+	for _, tt := range tests {
+		if got := size(tt.n); got != tt.expected {
+			t.Errorf("size(%d) = %q, expected %q", tt.n, got, tt.expected)
+		}
+	}
+}
+
+// TWICE is a variant that gets its answer wrong
+const TWICE = `package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+func main() {
+	data, _ := io.ReadAll(os.Stdin)
+	fmt.Println(2 * len(data))
+}
+`
+
+func TestVariants(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds solvers")
+	}
+
+	root := t.TempDir()
+	dir := filepath.Join(root, "2025", "1")
+	write := func(name, data string) {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(fmt.Sprintf(SRC, 1), fmt.Sprintf(SOLVER, false))
+	write(filepath.Join("timed", "timed.go"), fmt.Sprintf(SOLVER, true))
+	write(filepath.Join("wrong", "twice.go"), TWICE)
+	write(INPUT, "input\n")
+
+	s := setup{dir: root, Year: 2025, Day: 1}
+	if err := s.resolve(); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func(n int) { variantRuns = n }(variantRuns)
+	variantRuns = 2
+
+	tests := []struct {
+		name, variants string
+		ok             bool
+	}{
+		{"agree", "timed timed\n", true},
+		{"differ", "timed timed\ntwice wrong/twice.go\n", false},
+		{"missing", "gone gone.py\n", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			write(VARIANTS, tt.variants)
+			if err := compareVariants(&s, nil); (err == nil) != tt.ok {
+				t.Errorf("compareVariants() expected ok %v, got %v", tt.ok, err)
+			}
+		})
+	}
+}
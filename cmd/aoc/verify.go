@@ -0,0 +1,193 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var samples bool // see -sample
+
+func verifyFlags(fs *flag.FlagSet) {
+	fs.BoolVar(&samples, "sample", false, "check sample.txt against sample.answers")
+}
+
+// outcome is a day verification result
+type outcome int
+
+const (
+	PASS outcome = iota
+	FAIL
+	MISSING // no answers or no input
+)
+
+func (o outcome) String() string {
+	return [...]string{PASS: "pass", FAIL: "fail", MISSING: "missing"}[o]
+}
+
+// verify runs the selected day, every day of the selected year or of every
+// year and checks their output against the day golden answers
+func verify(s *setup, _ []string) error {
+	years, err := s.years()
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.MkdirTemp("", "aoc")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	input, answers := INPUT, ANSWERS
+	if samples {
+		input, answers = SAMPLE, EXPECTS
+	}
+
+	var count [3]int
+	for _, year := range years {
+		days := []int{s.Day}
+		if s.Day == 0 {
+			days = days[:0]
+			for day := 1; day <= 25; day++ {
+				days = append(days, day)
+			}
+		}
+
+		for _, day := range days {
+			dir := filepath.Join(s.root, strconv.Itoa(year), strconv.Itoa(day))
+			if _, err := os.Stat(filepath.Join(dir, fmt.Sprintf(SRC, day))); errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+
+			o, msg := verifyDay(dir, day, tmp, input, answers)
+			count[o]++
+
+			if msg != "" {
+				msg = ": " + msg
+			}
+			fmt.Printf("%d-%d: %v%s\n", year, day, o, msg)
+		}
+	}
+
+	fmt.Printf("\n%d passed, %d failed, %d missing\n", count[PASS], count[FAIL], count[MISSING])
+
+	if count[FAIL] > 0 {
+		return fmt.Errorf("%d days failed", count[FAIL])
+	}
+	return nil
+}
+
+// verifyDay builds and runs a day solver and compares its output with the
+// known answers, a part without an answer is not checked
+func verifyDay(dir string, day int, tmp, input, answers string) (outcome, string) {
+	known, err := readAnswers(filepath.Join(dir, answers))
+	if err != nil || known == [3]string{} {
+		return MISSING, fmt.Sprintf("no %s", answers)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, input)); err != nil {
+		return MISSING, fmt.Sprintf("no %s", input)
+	}
+
+	bin, err := build(dir, day, tmp)
+	if err != nil {
+		return FAIL, err.Error()
+	}
+
+	out, err := solve(dir, bin, input)
+	if err != nil {
+		return FAIL, err.Error()
+	}
+
+	got := normalize(out)
+	if !match(got, known) {
+		expected := strings.Join(slices.DeleteFunc(known[1:], func(a string) bool { return a == "" }), " ")
+		return FAIL, fmt.Sprintf("expected %q, got %q", expected, strings.Join(got, " "))
+	}
+	return PASS, ""
+}
+
+// solve runs bin in dir with input, relative to dir or not, as stdin and
+// returns its stdout, its json report when it has a hook
+func solve(dir, bin, input string) ([]byte, error) {
+	if !filepath.IsAbs(input) {
+		input = filepath.Join(dir, input)
+	}
+
+	in, err := os.Open(input)
+	if err != nil {
+		return nil, err
+	}
+	defer in.Close()
+
+	out, errs, err := runSolver(context.Background(), dir, bin, in)
+	if err != nil {
+		return nil, fmt.Errorf("running %s: %w\n%s", filepath.Base(bin), err, errs)
+	}
+	return out, nil
+}
+
+// runSolver runs bin in dir in json mode until done or ctx is, and returns
+// its stdout and stderr
+func runSolver(ctx context.Context, dir, bin string, in io.Reader) (stdout, stderr []byte, err error) {
+	var out, errs bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, bin)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), OUTPUT)
+	cmd.Stdin = in
+	cmd.Stdout = &out
+	cmd.Stderr = &errs
+
+	err = cmd.Run()
+	return out.Bytes(), errs.Bytes(), err
+}
+
+// normalize splits a solver answers into whitespace separated tokens, from
+// its json report or from its output less the trailing time.Since some
+// solvers print
+func normalize(out []byte) []string {
+	if r, ok := parseReport(out); ok {
+		var fields []string
+		for _, part := range []any{r.Part1, r.Part2} {
+			if part != nil {
+				fields = append(fields, strings.Fields(fmt.Sprint(part))...)
+			}
+		}
+		return fields
+	}
+
+	fields := strings.Fields(string(out))
+	if n := len(fields); n > 0 && duration.FindString(fields[n-1]) == fields[n-1] {
+		fields = fields[:n-1]
+	}
+	return fields
+}
+
+// match tells if an output matches the known answers, all of it when both
+// parts are known, its first or last tokens when only part 1 or 2 is
+func match(got []string, known [3]string) bool {
+	var expected []string
+	for _, a := range known[1:] {
+		expected = append(expected, strings.Fields(a)...)
+	}
+
+	if known[1] != "" && known[2] != "" {
+		return slices.Equal(got, expected)
+	}
+
+	if known[1] == "" { // part 2 only, it is printed last
+		return len(got) >= len(expected) && slices.Equal(got[len(got)-len(expected):], expected)
+	}
+	return len(got) >= len(expected) && slices.Equal(got[:len(expected)], expected)
+}
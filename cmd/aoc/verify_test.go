@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		out      string
+		expected []string
+	}{
+		{"1234 5678 123.5µs\n", []string{"1234", "5678"}},
+		{"1234\n5678\n", []string{"1234", "5678"}},
+		{"42 1.234ms\n", []string{"42"}},
+		{"EFJKZ\n", []string{"EFJKZ"}},
+		{"", nil},
+		{`{"year":2023,"day":1,"part1":12345678901234,"part2":null,"elapsed_ns":42}`, []string{"12345678901234"}},
+		{`{"year":2024,"day":24,"part1":1,"part2":"a,b","elapsed_ns":42}` + "\n", []string{"1", "a,b"}},
+	}
+
+	for _, tt := range tests {
+		if got := normalize([]byte(tt.out)); !slices.Equal(got, tt.expected) {
+			t.Errorf("normalize(%q) expected %q, got %q", tt.out, tt.expected, got)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		got      []string
+		known    [3]string
+		expected bool
+	}{
+		{"both", []string{"1", "2"}, [3]string{"", "1", "2"}, true},
+		{"wrong", []string{"1", "3"}, [3]string{"", "1", "2"}, false},
+		{"extra", []string{"1", "2", "3"}, [3]string{"", "1", "2"}, false},
+		{"part 1", []string{"1", "2"}, [3]string{"", "1", ""}, true},
+		{"part 2", []string{"1", "2"}, [3]string{"", "", "2"}, true},
+		{"short", []string{}, [3]string{"", "1", ""}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := match(tt.got, tt.known); got != tt.expected {
+				t.Errorf("match(%q, %q) expected %v, got %v", tt.got, tt.known, tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds solvers")
+	}
+
+	root := t.TempDir()
+	write := func(day int, name, data string) {
+		dir := filepath.Join(root, "2025", fmt.Sprint(day))
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for day := 1; day <= 3; day++ {
+		write(day, fmt.Sprintf(SRC, day), fmt.Sprintf(SOLVER, true))
+		write(day, INPUT, "input\n")
+		write(day, SAMPLE, "in\n")
+	}
+	write(1, ANSWERS, "1: 6\n")
+	write(1, EXPECTS, "1: 4\n") // wrong
+	write(2, ANSWERS, "1: 7\n") // wrong
+	write(2, EXPECTS, "1: 3\n")
+
+	s := setup{dir: root, Year: 2025}
+	if err := s.resolve(); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() { samples = false }()
+
+	tests := []struct {
+		sample bool
+		day    int
+		ok     bool
+	}{
+		{false, 1, true},
+		{false, 0, false}, // day 2
+		{true, 2, true},
+		{true, 0, false}, // day 1
+		{false, 3, true}, // missing answers don't fail
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%v/%d", tt.sample, tt.day), func(t *testing.T) {
+			samples, s.Day = tt.sample, tt.day
+			if err := verify(&s, nil); (err == nil) != tt.ok {
+				t.Errorf("verify() expected ok %v, got %v", tt.ok, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+// intcode is the workbench of the 2019 Intcode programs.
+//
+// usage:
+//
+//	intcode dis [file]
+//	intcode asm [file]
+//	intcode cc [-S] [file]
+//
+// dis lists the program in file, or on stdin, as instructions and data
+// with labels on jump targets, in the syntax of the TRACE=1 dump.
+//
+// asm assembles such a listing back into a comma separated program.
+//
+// cc compiles a program of the tiny language described in lib/intcode,
+// -S stops at its assembly.
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/erik-adelbert/aoc/lib/intcode"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("intcode: ")
+
+	args := os.Args[1:]
+	if len(args) == 0 {
+		usage()
+	}
+	cmd, args := args[0], args[1:]
+
+	var asmonly bool
+	if cmd == "cc" && len(args) > 0 && args[0] == "-S" {
+		asmonly, args = true, args[1:]
+	}
+	if len(args) > 1 {
+		usage()
+	}
+
+	src := os.Stdin
+	if len(args) == 1 {
+		f, err := os.Open(args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		src = f
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+
+	var err error
+	switch cmd {
+	case "dis":
+		err = dis(out, src)
+	case "asm":
+		err = asm(out, src)
+	case "cc":
+		err = cc(out, src, asmonly)
+	default:
+		usage()
+	}
+	if err != nil {
+		out.Flush()
+		log.Fatal(err)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: intcode dis [file] | asm [file] | cc [-S] [file]")
+	os.Exit(2)
+}
+
+// dis writes the listing of the program read from r
+func dis(w io.Writer, r io.Reader) error {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	code, err := intcode.Parse(strings.TrimSpace(string(raw)))
+	if err != nil {
+		return err
+	}
+	return intcode.Listing(w, code)
+}
+
+// asm writes the program assembled from r
+func asm(w io.Writer, r io.Reader) error {
+	src, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	code, err := intcode.Assemble(string(src))
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, code)
+	return err
+}
+
+// cc writes the program compiled from r, or its assembly
+func cc(w io.Writer, r io.Reader, asmonly bool) error {
+	src, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	s, err := intcode.Compile(string(src))
+	if err != nil {
+		return err
+	}
+	if asmonly {
+		_, err = io.WriteString(w, s)
+		return err
+	}
+	return asm(w, strings.NewReader(s))
+}
@@ -0,0 +1,89 @@
+# shared by every day Makefile through its year day.mk, which sets the
+# year overrides first
+
+GOC ?= go build
+GOR ?= go run
+GOV = go vet
+
+BENCH = $(AOC) bench -dir $(CURDIR)
+
+# make fuzz runs FUZZ of FUZZTESTS, a day Makefile may name another target
+# or, as the Intcode days do, another package
+FUZZ ?= FuzzParse
+FUZZTESTS ?= $(TESTS)
+FUZZTIME ?= 30s
+
+AOC = go run -C ../../cmd/aoc .
+
+EX = sample.txt
+IN = input.txt
+
+BIN = $(addprefix aoc,$(shell basename $(CURDIR)))
+SRC = $(BIN).go
+# a day lists its files, solver, hook.go and helpers, a day migrated to
+# the root module builds as a package with SRCS = ., see aoc migrate
+SRCS ?= $(filter-out %_test.go,$(wildcard *.go))
+TESTS = $(if $(filter .,$(SRCS)),.,$(wildcard *.go))
+
+bench: build
+	$(BENCH) $(BIN)
+	@$(MAKE) clean
+
+binrun: input.txt
+	./$(BIN) < $(IN)
+
+build: input.txt
+	$(GOC) -o $(BIN) $(SRCS)
+
+check:
+	$(GOV) $(SRCS)
+
+clean:
+	go clean
+	rm -f $(BIN)
+
+header:
+	@$(AOC) header -dir $(CURDIR)
+
+input.txt:
+	@$(AOC) download -dir $(CURDIR)
+
+sample.txt:
+	@$(AOC) sample -dir $(CURDIR)
+
+fuzz:
+	go test -run '^$$' -fuzz='^$(FUZZ)$$' -fuzztime=$(FUZZTIME) $(FUZZTESTS)
+
+gen:
+	@$(AOC) gen -dir $(CURDIR)
+
+gobench: input.txt
+	go test -bench=. -benchmem $(TESTS)
+
+cpuprof: build
+	./$(BIN) -cpuprofile=$(BIN).cpu.prof < $(IN)
+
+memprof: build
+	./$(BIN) -memprofile=$(BIN).mem.prof < $(IN)
+
+trace: build
+	./$(BIN) -trace=$(BIN).trace < $(IN)
+
+run: input.txt
+	$(GOR) $(SRCS) < $(IN)
+
+sample: sample.txt
+	$(GOR) $(SRCS) < $(EX)
+
+validate:
+	@$(AOC) validate -dir $(CURDIR)
+
+variants:
+	@$(AOC) variants -dir $(CURDIR)
+
+verify:
+	@$(AOC) verify -dir $(CURDIR)
+	@$(AOC) verify -sample -dir $(CURDIR)
+
+
+.PHONY: bench binrun build check clean cpuprof exemple fuzz gen gobench header memprof run sample trace validate variants verify
@@ -0,0 +1,3 @@
+module github.com/erik-adelbert/aoc
+
+go 1.25
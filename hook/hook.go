@@ -0,0 +1,200 @@
+// hook.go --
+// run hook shared by the daily solvers: profiling and output mode
+//
+// https://github.com/erik-adelbert/aoc
+//
+// (ɔ) Erik Adelbert - erik_AT_adelbert_DOT_fr
+// -------------------------------------------
+// every day directory links this file as its hook.go. A day main exits
+// with `runDay(Solve, show)`. Its Solve opens with `defer guard(&err)`.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	tracer "runtime/trace"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// hook is the run state: when it started and where answers go in json
+// mode. AOC_OUTPUT=json turns the solver output into diagnostics
+var hook struct {
+	start time.Time
+	json  *os.File
+}
+
+// Result holds the day answers
+type Result struct {
+	Part1, Part2 any
+}
+
+// runDay solves the day from stdin and shows its answers. It returns the
+// exit status, main exits with it once the profiles are written
+func runDay(solve func(io.Reader) (Result, error), show func(Result)) int {
+	defer profile()()
+
+	res, err := solve(os.Stdin)
+	if err == nil {
+		err = answer(res.Part1, res.Part2)
+	}
+	if err != nil {
+		log.Print(err)
+		return 1
+	}
+
+	show(res)
+	return 0
+}
+
+// profile starts the run and the profiles asked by -cpuprofile,
+// -memprofile and -trace, or by $AOC_PROFILE, eg.
+// AOC_PROFILE=cpu=aoc1.cpu.prof,mem=aoc1.mem.prof. It returns a func
+// writing them
+func profile() func() {
+	hook.start = time.Now()
+
+	if os.Getenv("AOC_OUTPUT") == "json" {
+		hook.json, os.Stdout = os.Stdout, os.Stderr
+		log.SetFlags(0) // a failed run ends on its bare error
+	}
+
+	cpu := flag.String("cpuprofile", "", "write a cpu profile to `file`")
+	mem := flag.String("memprofile", "", "write a memory profile to `file`")
+	exe := flag.String("trace", "", "write an execution trace to `file`")
+	flag.Parse()
+
+	// flags win over the environment
+	pick := func(flag, env string) string {
+		if flag != "" {
+			return flag
+		}
+		return env
+	}
+
+	for _, kv := range strings.Split(os.Getenv("AOC_PROFILE"), ",") {
+		k, v, _ := strings.Cut(kv, "=")
+		switch k {
+		case "cpu":
+			*cpu = pick(*cpu, v)
+		case "mem":
+			*mem = pick(*mem, v)
+		case "trace":
+			*exe = pick(*exe, v)
+		}
+	}
+
+	var stops []func()
+	stop := func() {
+		for _, f := range stops {
+			f()
+		}
+	}
+
+	// a failed start still writes the profiles already started
+	create := func(name string) *os.File {
+		fd, err := os.Create(name)
+		if err != nil {
+			stop()
+			log.Fatal(err)
+		}
+		return fd
+	}
+
+	if *cpu != "" {
+		fd := create(*cpu)
+		if err := pprof.StartCPUProfile(fd); err != nil {
+			stop()
+			log.Fatal(err)
+		}
+		stops = append(stops, func() {
+			pprof.StopCPUProfile()
+			fd.Close()
+		})
+	}
+
+	if *exe != "" {
+		fd := create(*exe)
+		if err := tracer.Start(fd); err != nil {
+			stop()
+			log.Fatal(err)
+		}
+		stops = append(stops, func() {
+			tracer.Stop()
+			fd.Close()
+		})
+	}
+
+	if *mem != "" {
+		stops = append(stops, func() {
+			fd, err := os.Create(*mem)
+			if err != nil {
+				log.Print(err)
+				return
+			}
+			defer fd.Close()
+
+			runtime.GC() // up-to-date statistics
+			if err := pprof.Lookup("allocs").WriteTo(fd, 0); err != nil {
+				log.Print(err)
+			}
+		})
+	}
+
+	return stop
+}
+
+// errPanic wraps the panics guard recovers
+var errPanic = errors.New("solve")
+
+// guard turns a solver panic into its error. Malformed inputs mostly end
+// up out of range. Solve defers it:
+//
+//	func Solve(r io.Reader) (_ Result, err error) {
+//		defer guard(&err)
+func guard(err *error) {
+	if x := recover(); x != nil {
+		*err = fmt.Errorf("%w: %v", errPanic, x)
+	}
+}
+
+// answer writes the day answers as json in json mode. The year and day
+// come from the <year>/<day> working directory, the time from profile and
+// the allocations from the runtime
+func answer(part1, part2 any) error {
+	if hook.json == nil {
+		return nil
+	}
+	elapsed := time.Since(hook.start)
+
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	var year, day int
+	if wd, err := os.Getwd(); err == nil {
+		day, _ = strconv.Atoi(filepath.Base(wd))
+		year, _ = strconv.Atoi(filepath.Base(filepath.Dir(wd)))
+	}
+
+	out := struct {
+		Year    int    `json:"year"`
+		Day     int    `json:"day"`
+		Part1   any    `json:"part1"`
+		Part2   any    `json:"part2"`
+		Elapsed int64  `json:"elapsed_ns"`
+		Allocs  uint64 `json:"allocs"`
+		Bytes   uint64 `json:"alloc_bytes"`
+	}{year, day, part1, part2, elapsed.Nanoseconds(), mem.Mallocs, mem.TotalAlloc}
+
+	return json.NewEncoder(hook.json).Encode(out)
+}
@@ -0,0 +1,134 @@
+// hook_test.go --
+// fuzz harness shared by the daily solvers
+//
+// https://github.com/erik-adelbert/aoc
+//
+// (ɔ) Erik Adelbert - erik_AT_adelbert_DOT_fr
+// -------------------------------------------
+// every day directory links this file as its hook_test.go next to hook.go,
+// a day fuzzes the parse step of its Solve, and round-trips the structures
+// it prints back as input:
+//
+//	func FuzzParse(f *testing.F) {
+//		seedExamples(f)
+//		fuzzParse(f, reader(parse))
+//	}
+//
+// and runs them with `make fuzz`
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// FUZZTIME bounds a fuzzed call, a slower one is deemed an endless loop
+const FUZZTIME = 2 * time.Second
+
+// seedExamples adds the day examples to the corpus of f, or its input
+// when it has none
+func seedExamples(f *testing.F) {
+	for _, data := range examples(f) {
+		f.Add(data)
+	}
+}
+
+// seedLines adds every line of the day examples to the corpus of f
+func seedLines(f *testing.F) {
+	for _, data := range examples(f) {
+		lines := bufio.NewScanner(bytes.NewReader(data))
+		for lines.Scan() {
+			f.Add(bytes.Clone(lines.Bytes()))
+		}
+	}
+}
+
+func examples(f *testing.F) [][]byte {
+	names, _ := filepath.Glob("sample*.txt")
+	if len(names) == 0 {
+		names = []string{"input.txt"}
+	}
+
+	var all [][]byte
+	for _, name := range names {
+		data, err := os.ReadFile(name)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			f.Fatal(err)
+		}
+		all = append(all, data)
+	}
+	return all
+}
+
+// fuzzParse checks parse turns down malformed inputs with an error: it
+// neither panics nor loops endlessly
+func fuzzParse[T any](f *testing.F, parse func([]byte) (T, error)) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		bounded(t, func() error {
+			_, err := parse(data)
+			return err
+		})
+	})
+}
+
+// fuzzRoundTrip checks what parse accepts prints back to a text parse
+// accepts and that prints the same
+func fuzzRoundTrip[T fmt.Stringer](f *testing.F, parse func([]byte) (T, error)) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var v T
+		if bounded(t, func() (err error) { v, err = parse(data); return }) != nil {
+			return // turned down
+		}
+		text := v.String()
+
+		var w T
+		if err := bounded(t, func() (err error) { w, err = parse([]byte(text)); return }); err != nil {
+			t.Fatalf("%q prints %q, which doesn't parse: %v", data, text, err)
+		}
+		if again := w.String(); again != text {
+			t.Errorf("%q prints %q, reparsed it prints %q", data, text, again)
+		}
+	})
+}
+
+// reader adapts a parser of the whole input to the fuzz helpers
+func reader[T any](parse func(io.Reader) (T, error)) func([]byte) (T, error) {
+	return func(data []byte) (T, error) {
+		return parse(bytes.NewReader(data))
+	}
+}
+
+// bounded runs fn and fails t when it panics or outlasts FUZZTIME
+func bounded(t *testing.T, fn func() error) error {
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if x := recover(); x != nil {
+				done <- fmt.Errorf("%w: %v", errPanic, x)
+			}
+		}()
+		done <- fn()
+	}()
+
+	select {
+	case err := <-done:
+		if errors.Is(err, errPanic) {
+			t.Fatal(err)
+		}
+		return err
+	case <-time.After(FUZZTIME):
+		t.Fatalf("still running after %v", FUZZTIME)
+		return nil
+	}
+}
@@ -0,0 +1,13 @@
+// main.go --
+// stub main of the hook directory
+//
+// https://github.com/erik-adelbert/aoc
+//
+// (ɔ) Erik Adelbert - erik_AT_adelbert_DOT_fr
+// -------------------------------------------
+// hook.go and hook_test.go are shared with the days, not a program: this
+// file only lets the root module build, the days don't link it
+
+package main
+
+func main() {}
@@ -0,0 +1,67 @@
+// bitset.go --
+// bit sets shared by the daily solvers
+//
+// https://github.com/erik-adelbert/aoc
+//
+// (ɔ) Erik Adelbert - erik_AT_adelbert_DOT_fr
+// -------------------------------------------
+// dense sets of small non negative integers, eg. grid cells as y*w+x
+
+// Package bitset is a growable set of small non negative integers.
+package bitset
+
+import "math/bits"
+
+// Bitset is a set of non negative integers, the zero value is empty and
+// ready to use
+type Bitset []uint64
+
+// New returns an empty set with room for [0, n)
+func New(n int) Bitset {
+	return make(Bitset, (n+63)/64)
+}
+
+// Add adds i to b, growing it as needed
+func (b *Bitset) Add(i int) {
+	if w := i / 64; w >= len(*b) {
+		*b = append(*b, make(Bitset, w-len(*b)+1)...)
+	}
+	(*b)[i/64] |= 1 << (i % 64)
+}
+
+// Remove removes i from b
+func (b Bitset) Remove(i int) {
+	if i/64 < len(b) {
+		b[i/64] &^= 1 << (i % 64)
+	}
+}
+
+// Has tells if i is in b
+func (b Bitset) Has(i int) bool {
+	return i/64 < len(b) && b[i/64]&(1<<(i%64)) != 0
+}
+
+// Len returns the item count of b
+func (b Bitset) Len() (n int) {
+	for _, w := range b {
+		n += bits.OnesCount64(w)
+	}
+	return
+}
+
+// Clear empties b, keeping its room
+func (b Bitset) Clear() {
+	clear(b)
+}
+
+// All calls yield on the items of b in increasing order
+func (b Bitset) All(yield func(int) bool) {
+	for i, w := range b {
+		for w != 0 {
+			if !yield(64*i + bits.TrailingZeros64(w)) {
+				return
+			}
+			w &= w - 1
+		}
+	}
+}
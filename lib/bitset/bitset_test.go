@@ -0,0 +1,64 @@
+package bitset
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestBitset(t *testing.T) {
+	var b Bitset // zero value
+	for _, i := range []int{3, 64, 0, 200, 64} {
+		b.Add(i)
+	}
+
+	if got := b.Len(); got != 4 {
+		t.Errorf("Len() = %d, expected 4", got)
+	}
+
+	var all []int
+	for i := range b.All {
+		all = append(all, i)
+	}
+	if expected := []int{0, 3, 64, 200}; !slices.Equal(all, expected) {
+		t.Errorf("All() = %v, expected %v", all, expected)
+	}
+
+	tests := []struct {
+		i        int
+		expected bool
+	}{
+		{0, true},
+		{1, false},
+		{64, true},
+		{200, true},
+		{10_000, false}, // past the end
+	}
+	for _, tt := range tests {
+		if got := b.Has(tt.i); got != tt.expected {
+			t.Errorf("Has(%d) = %v, expected %v", tt.i, got, tt.expected)
+		}
+	}
+
+	b.Remove(64)
+	b.Remove(10_000)
+	if b.Has(64) || b.Len() != 3 {
+		t.Errorf("Remove(64) leaves %v", b)
+	}
+
+	b.Clear()
+	if b.Len() != 0 || len(b) != 4 {
+		t.Errorf("Clear() leaves %d items in %d words", b.Len(), len(b))
+	}
+}
+
+func TestNew(t *testing.T) {
+	for _, n := range []int{0, 1, 64, 65} {
+		b := New(n)
+		if n > 0 {
+			b.Add(n - 1)
+		}
+		if len(b) != (n+63)/64 {
+			t.Errorf("New(%d) grows to %d words", n, len(b))
+		}
+	}
+}
@@ -0,0 +1,89 @@
+// heap.go --
+// priority queue shared by the daily solvers
+//
+// https://github.com/erik-adelbert/aoc
+//
+// (ɔ) Erik Adelbert - erik_AT_adelbert_DOT_fr
+// -------------------------------------------
+// it replaces the container/heap boilerplate the dijkstra days carried,
+// without its interface{} boxing
+
+// Package heap is a generic binary heap.
+package heap
+
+// Heap is a binary min heap ordered by less
+type Heap[T any] struct {
+	data []T
+	less func(a, b T) bool
+}
+
+// New returns an empty heap ordered by less, with room for n items
+func New[T any](less func(a, b T) bool, n int) *Heap[T] {
+	return &Heap[T]{data: make([]T, 0, n), less: less}
+}
+
+// Len returns the item count of h
+func (h *Heap[T]) Len() int {
+	return len(h.data)
+}
+
+// Push adds x to h
+func (h *Heap[T]) Push(x T) {
+	h.data = append(h.data, x)
+	h.up(len(h.data) - 1)
+}
+
+// Pop removes and returns the least item of h, it panics when h is empty
+func (h *Heap[T]) Pop() T {
+	n := len(h.data) - 1
+
+	x := h.data[0]
+	h.data[0] = h.data[n]
+
+	var zero T
+	h.data[n] = zero // no dangling pointer
+	h.data = h.data[:n]
+
+	h.down(0)
+	return x
+}
+
+// Peek returns the least item of h without removing it
+func (h *Heap[T]) Peek() T {
+	return h.data[0]
+}
+
+// Reset empties h, keeping its room
+func (h *Heap[T]) Reset() {
+	clear(h.data)
+	h.data = h.data[:0]
+}
+
+func (h *Heap[T]) up(i int) {
+	for i > 0 {
+		p := (i - 1) / 2
+		if !h.less(h.data[i], h.data[p]) {
+			return
+		}
+		h.data[i], h.data[p] = h.data[p], h.data[i]
+		i = p
+	}
+}
+
+func (h *Heap[T]) down(i int) {
+	n := len(h.data)
+	for {
+		min, l, r := i, 2*i+1, 2*i+2
+		if l < n && h.less(h.data[l], h.data[min]) {
+			min = l
+		}
+		if r < n && h.less(h.data[r], h.data[min]) {
+			min = r
+		}
+		if min == i {
+			return
+		}
+		h.data[i], h.data[min] = h.data[min], h.data[i]
+		i = min
+	}
+}
@@ -0,0 +1,58 @@
+package heap
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestHeap(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+
+	for n := range 50 {
+		h := New(func(a, b int) bool { return a < b }, 0)
+
+		var in []int
+		for range n {
+			x := r.IntN(20) - 10 // duplicates too
+			in = append(in, x)
+			h.Push(x)
+		}
+
+		if h.Len() != n {
+			t.Fatalf("Len() = %d, expected %d", h.Len(), n)
+		}
+
+		var out []int
+		for h.Len() > 0 {
+			if p := h.Peek(); p != h.data[0] {
+				t.Fatalf("Peek() = %d, expected %d", p, h.data[0])
+			}
+			out = append(out, h.Pop())
+		}
+
+		slices.Sort(in)
+		if !slices.Equal(out, in) {
+			t.Errorf("popped %v, expected %v", out, in)
+		}
+	}
+}
+
+func TestReset(t *testing.T) {
+	type item struct{ p *int }
+
+	h := New(func(a, b item) bool { return *a.p < *b.p }, 4)
+	for i := range 3 {
+		h.Push(item{&i})
+	}
+
+	h.Reset()
+	if h.Len() != 0 {
+		t.Fatalf("Len() = %d after Reset, expected 0", h.Len())
+	}
+	for _, x := range h.data[:cap(h.data)] {
+		if x.p != nil {
+			t.Fatal("Reset keeps a dangling pointer")
+		}
+	}
+}
@@ -0,0 +1,321 @@
+// asm.go --
+// intcode assembler
+//
+// https://github.com/erik-adelbert/aoc
+//
+// (ɔ) Erik Adelbert - erik_AT_adelbert_DOT_fr
+// -------------------------------------------
+// the syntax of the listings, the stack macros keep the relative base as
+// the stack pointer
+
+package intcode
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// String returns ic as its comma separated words, what Parse reads
+func (ic Code) String() string {
+	words := make([]string, len(ic))
+	for i, v := range ic {
+		words[i] = strconv.Itoa(v)
+	}
+	return strings.Join(words, ",")
+}
+
+// operand is a parameter or a data word, sym+off once resolved
+type operand struct {
+	mode int
+	sym  string // label, none for a number
+	off  int
+}
+
+// item is an assembled instruction or data run
+type item struct {
+	line int
+	op   int // none for data
+	args []operand
+}
+
+// size returns the word count of it
+func (it item) size() int {
+	if it.op == 0 {
+		return len(it.args)
+	}
+	return 1 + len(it.args)
+}
+
+// Assemble turns src into a program, src is made of lines of:
+//
+//	label:                      a label, on its own line or before the rest
+//	ADD $x 1 .2                 an instruction, $ for a position parameter,
+//	                            . for a relative one, immediate otherwise
+//	db 1,'a',"text\n",label     data words
+//	; comment                   up to the end of line
+//
+// parameters are numbers, 'c' characters, labels or label+n and label-n,
+// mnemonics are not case sensitive, and the stack macros use the relative
+// base as a stack pointer to the next free word:
+//
+//	push x      ADD x 0 .0; RBO 1
+//	pop x       RBO -1; ADD .0 0 x
+//	jmp label   JIT 1 label
+//	call label  push the return address, jmp label
+//	ret         RBO -1; JIF 0 .0
+func Assemble(src string) (Code, error) {
+	var items []item
+	labels := make(map[string]int)
+
+	pc, calls := 0, 0
+	for i, line := range strings.Split(src, "\n") {
+		n := i + 1
+		fail := func(format string, args ...any) (Code, error) {
+			return nil, fmt.Errorf("asm line %d: %s", n, fmt.Sprintf(format, args...))
+		}
+
+		line, _ = cutComment(line)
+		line = strings.TrimSpace(line)
+
+		if label, rest, ok := strings.Cut(line, ":"); ok && isIdent(strings.TrimSpace(label)) {
+			label = strings.TrimSpace(label)
+			if _, ok := labels[label]; ok {
+				return fail("label %s redefined", label)
+			}
+			labels[label] = pc
+			line = strings.TrimSpace(rest)
+		}
+		if line == "" {
+			continue
+		}
+
+		name, rest := line, ""
+		if i := strings.IndexAny(line, " \t"); i > 0 {
+			name, rest = line[:i], line[i:]
+		}
+		args, err := operands(rest)
+		if err != nil {
+			return fail("%v", err)
+		}
+
+		emit := func(op int, args ...operand) {
+			it := item{n, op, args}
+			items = append(items, it)
+			pc += it.size()
+		}
+		imm := func(v int) operand { return operand{IMM, "", v} }
+		rel := func(v int) operand { return operand{REL, "", v} }
+
+		want := func(k int) bool {
+			return len(args) == k
+		}
+
+		switch name = strings.ToUpper(name); name {
+		case "DB":
+			for _, a := range args {
+				if a.mode != IMM {
+					return fail("db %s word", [...]string{POS: "position", REL: "relative"}[a.mode])
+				}
+			}
+			emit(0, args...)
+		case "PUSH":
+			if !want(1) {
+				return fail("push takes 1 parameter")
+			}
+			emit(ADD, args[0], imm(0), rel(0))
+			emit(RBO, imm(1))
+		case "POP":
+			if !want(1) {
+				return fail("pop takes 1 parameter")
+			}
+			emit(RBO, imm(-1))
+			emit(ADD, rel(0), imm(0), args[0])
+		case "JMP":
+			if !want(1) {
+				return fail("jmp takes 1 parameter")
+			}
+			emit(JIT, imm(1), args[0])
+		case "CALL":
+			if !want(1) {
+				return fail("call takes 1 parameter")
+			}
+			// the return label can't clash with an identifier
+			calls++
+			ret := fmt.Sprintf("call#%d", calls)
+			emit(ADD, operand{IMM, ret, 0}, imm(0), rel(0))
+			emit(RBO, imm(1))
+			emit(JIT, imm(1), args[0])
+			labels[ret] = pc
+		case "RET":
+			if !want(0) {
+				return fail("ret takes no parameter")
+			}
+			emit(RBO, imm(-1))
+			emit(JIF, imm(0), rel(0))
+		default:
+			op := opcode(name)
+			if op == 0 {
+				return fail("unknown mnemonic %s", name)
+			}
+			if !want(arity[op]) {
+				return fail("%s takes %d parameters", name, arity[op])
+			}
+			emit(op, args...)
+		}
+	}
+
+	ic := make(Code, 0, pc)
+	for _, it := range items {
+		words := make([]int, len(it.args))
+		for i, a := range it.args {
+			words[i] = a.off
+			if a.sym != "" {
+				v, ok := labels[a.sym]
+				if !ok {
+					return nil, fmt.Errorf("asm line %d: undefined label %s", it.line, a.sym)
+				}
+				words[i] += v
+			}
+		}
+
+		if it.op != 0 {
+			w := it.op
+			for i, a := range it.args {
+				w += a.mode * [...]int{100, 1000, 10000}[i]
+			}
+			ic = append(ic, w)
+		}
+		ic = append(ic, words...)
+	}
+	return ic, nil
+}
+
+// opcode returns the opcode of a mnemonic, 0 when there is none
+func opcode(name string) int {
+	for op, m := range mnemonics {
+		if m == name && op != EOT {
+			return op
+		}
+	}
+	return 0
+}
+
+// cutComment splits line at its comment, out of quotes
+func cutComment(line string) (string, string) {
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '"', '\'':
+			q, err := strconv.QuotedPrefix(line[i:])
+			if err != nil {
+				return line, ""
+			}
+			i += len(q) - 1
+		case ';':
+			return line[:i], line[i+1:]
+		}
+	}
+	return line, ""
+}
+
+// operands parses the parameters in s, separated by spaces or commas, a
+// string is one operand per character
+func operands(s string) ([]operand, error) {
+	var args []operand
+	for {
+		s = strings.TrimLeft(s, " \t,")
+		if s == "" {
+			return args, nil
+		}
+
+		a := operand{mode: IMM}
+		switch s[0] {
+		case '$':
+			a.mode, s = POS, s[1:]
+		case '.':
+			a.mode, s = REL, s[1:]
+		}
+
+		if strings.HasPrefix(s, `"`) {
+			q, err := strconv.QuotedPrefix(s)
+			if err != nil {
+				return nil, fmt.Errorf("bad string %s", s)
+			}
+			text, _ := strconv.Unquote(q)
+			for _, c := range []byte(text) {
+				args = append(args, operand{a.mode, "", int(c)})
+			}
+			s = s[len(q):]
+			continue
+		}
+
+		var word string
+		if strings.HasPrefix(s, "'") {
+			q, err := strconv.QuotedPrefix(s)
+			if err != nil {
+				return nil, fmt.Errorf("bad character %s", s)
+			}
+			word, s = q, s[len(q):]
+		} else {
+			i := strings.IndexAny(s, " \t,")
+			if i < 0 {
+				i = len(s)
+			}
+			word, s = s[:i], s[i:]
+		}
+
+		if err := a.parse(word); err != nil {
+			return nil, err
+		}
+		args = append(args, a)
+	}
+}
+
+// parse reads a number, a character, a label or a label+n or label-n
+func (a *operand) parse(word string) error {
+	if n, err := strconv.Atoi(word); err == nil {
+		a.off = n
+		return nil
+	}
+
+	if strings.HasPrefix(word, "'") {
+		c, _, tail, err := strconv.UnquoteChar(word[1:], '\'')
+		if err != nil || tail != "'" {
+			return fmt.Errorf("bad character %s", word)
+		}
+		a.off = int(c)
+		return nil
+	}
+
+	sym, off := word, ""
+	if i := strings.IndexAny(word, "+-"); i > 0 {
+		sym, off = word[:i], word[i:]
+	}
+	if !isIdent(sym) {
+		return fmt.Errorf("bad parameter %s", word)
+	}
+	a.sym = sym
+
+	if off != "" {
+		n, err := strconv.Atoi(off)
+		if err != nil {
+			return fmt.Errorf("bad offset %s", word)
+		}
+		a.off = n
+	}
+	return nil
+}
+
+// isIdent tells if s is a label: a letter or _ then letters, digits, _ or .
+func isIdent(s string) bool {
+	for i, c := range s {
+		switch {
+		case c == '_', unicode.IsLetter(c):
+		case i > 0 && (c == '.' || unicode.IsDigit(c)):
+		default:
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,74 @@
+package intcode
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestAssemble(t *testing.T) {
+	tests := []struct {
+		src      string
+		expected Code
+	}{
+		{"ADD $9 10 $3\nMUL $3 11 $0\nHLT", Code{1001, 9, 10, 3, 1002, 3, 11, 0, 99}},
+		{"start: out 'h' ; comment\n\tjit 1, start", Code{104, 'h', 1105, 1, 0}},
+		{"JIT 1 end\ndb \"a;b\", 0, end+1\nend: HLT", Code{1105, 1, 8, 'a', ';', 'b', 0, 9, 99}},
+		{"INP .-1\nRBO 2\nOUT .1", Code{203, -1, 109, 2, 204, 1}},
+		// stack macros
+		{"push $5\npop .2", Code{21001, 5, 0, 0, 109, 1, 109, -1, 21201, 0, 0, 2}},
+		{"call f\nHLT\nf: ret", Code{21101, 9, 0, 0, 109, 1, 1105, 1, 10, 99, 109, -1, 2106, 0, 0}},
+	}
+
+	for _, tt := range tests {
+		got, err := Assemble(tt.src)
+		if err != nil || !slices.Equal(got, tt.expected) {
+			t.Errorf("Assemble(%q) = %v, %v, expected %v", tt.src, got, err, tt.expected)
+		}
+	}
+
+	bad := []string{
+		"NOP",
+		"ADD 1 2",
+		"JMP nowhere",
+		"a: HLT\na: HLT",
+		"db $1",
+		"OUT 'ab'",
+		"OUT 1x",
+	}
+	for _, src := range bad {
+		if _, err := Assemble(src); err == nil {
+			t.Errorf("Assemble(%q) expected to fail", src)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	programs := []Code{
+		// day 9 quine
+		{109, 1, 204, -1, 1001, 100, 1, 100, 1008, 100, 16, 101, 1006, 101, 0, 99},
+		// day 5, 999 below 8, 1000 at 8 and 1001 above
+		{
+			3, 21, 1008, 21, 8, 20, 1005, 20, 22, 107, 8, 21, 20, 1006, 20, 31,
+			1106, 0, 36, 98, 0, 0, 1002, 21, 125, 20, 4, 20, 1105, 1, 46, 104,
+			999, 1105, 1, 46, 1101, 1000, 1, 20, 4, 20, 1105, 1, 46, 98, 99,
+		},
+		{1105, 1, 7, 'h', 'i', '!', '\n', 104, 72, 99, -5, 100},
+		sum,
+	}
+
+	for _, ic := range programs {
+		var sb strings.Builder
+		if err := Listing(&sb, ic); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := Assemble(sb.String())
+		if err != nil {
+			t.Fatalf("Assemble(Listing(%v)): %v", ic, err)
+		}
+		if got, err := Parse(got.String()); err != nil || !slices.Equal(got, ic) {
+			t.Errorf("Parse(Assemble(Listing(%v))) = %v, %v", ic, got, err)
+		}
+	}
+}
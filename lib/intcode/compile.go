@@ -0,0 +1,562 @@
+// compile.go --
+// a tiny language compiled to intcode assembly
+//
+// https://github.com/erik-adelbert/aoc
+//
+// (ɔ) Erik Adelbert - erik_AT_adelbert_DOT_fr
+// -------------------------------------------
+// a stack machine: every expression pushes its value with the stack macros
+
+package intcode
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Compile turns src into assembly for Assemble, src is a list of global
+// variables and functions, main is called first and the program halts when
+// it returns:
+//
+//	var total = 0;                   // globals start with a number
+//
+//	fn main() {
+//	    var n = in();                // locals live on the stack
+//	    while n > 0 {
+//	        total = total + fact(n);
+//	        n = in();
+//	    }
+//	    out(total);
+//	    out("done\n");               // a string outputs its characters
+//	}
+//
+//	fn fact(n) {
+//	    if n < 2 { return 1; } else { return n * fact(n - 1); }
+//	}
+//
+// values are integers, false is 0 and true anything else, operators are
+// || && == != < <= > >= + - * and unary - !, there is no division and &&
+// and || evaluate both sides, a function without return returns 0
+//
+// a call pushes a result word, the arguments and the return address, the
+// relative base is the top of the stack and the compiler knows its depth
+// so that the locals and parameters are relative parameters
+func Compile(src string) (asm string, err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			ce, ok := e.(ccError)
+			if !ok {
+				panic(e)
+			}
+			asm, err = "", ce
+		}
+	}()
+
+	c := &compiler{
+		toks:    lex(src),
+		funcs:   make(map[string]int),
+		globals: make(map[string]int),
+	}
+	c.program()
+	return c.out.String(), nil
+}
+
+// ccError is a compile error, Compile recovers it
+type ccError struct{ error }
+
+// token kinds
+const (
+	eof = iota
+	word
+	number
+	str
+	punct
+)
+
+type token struct {
+	kind int
+	text string // identifier, punctuation or string value
+	val  int    // number value
+	line int
+}
+
+// lex cuts src in tokens, the last one is eof, a bad character is a punct
+// the parser rejects
+func lex(src string) []token {
+	var toks []token
+
+	line := 1
+	for i := 0; i < len(src); {
+		c := rune(src[i])
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case unicode.IsSpace(c):
+			i++
+		case strings.HasPrefix(src[i:], "//"):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case c == '_' || unicode.IsLetter(c):
+			j := i + 1
+			for j < len(src) && (src[j] == '_' || unicode.IsLetter(rune(src[j])) || unicode.IsDigit(rune(src[j]))) {
+				j++
+			}
+			toks, i = append(toks, token{word, src[i:j], 0, line}), j
+		case unicode.IsDigit(c):
+			j := i + 1
+			for j < len(src) && unicode.IsDigit(rune(src[j])) {
+				j++
+			}
+			n, err := strconv.Atoi(src[i:j])
+			if err != nil {
+				toks = append(toks, token{punct, src[i:j], 0, line}) // out of range
+			} else {
+				toks = append(toks, token{number, src[i:j], n, line})
+			}
+			i = j
+		case c == '"' || c == '\'':
+			q, err := strconv.QuotedPrefix(src[i:])
+			if err != nil {
+				toks, i = append(toks, token{punct, src[i : i+1], 0, line}), i+1
+				break
+			}
+			s, _ := strconv.Unquote(q)
+			if c == '"' {
+				toks = append(toks, token{str, s, 0, line})
+			} else {
+				toks = append(toks, token{number, q, int([]rune(s)[0]), line})
+			}
+			i += len(q)
+		default:
+			n := 1
+			for _, op := range []string{"==", "!=", "<=", ">=", "&&", "||"} {
+				if strings.HasPrefix(src[i:], op) {
+					n = 2
+				}
+			}
+			toks, i = append(toks, token{punct, src[i : i+n], 0, line}), i+n
+		}
+	}
+	return append(toks, token{eof, "end of file", 0, line})
+}
+
+// compiler generates code as it parses
+type compiler struct {
+	toks []token
+	pos  int
+
+	out    strings.Builder
+	labels int // generated labels
+
+	funcs   map[string]int // parameter counts
+	globals map[string]int // initial values
+	calls   []token        // checked once every function is known
+	nargs   []int
+	refs    []token // globals used
+
+	// the current function
+	depth  int              // words pushed since the call
+	scopes []map[string]int // locals and parameters stack slots
+	result int              // stack slot of the result
+}
+
+// fail aborts the compilation at tok
+func (c *compiler) fail(tok token, format string, args ...any) {
+	panic(ccError{fmt.Errorf("cc line %d: %s", tok.line, fmt.Sprintf(format, args...))})
+}
+
+func (c *compiler) peek() token {
+	return c.toks[c.pos]
+}
+
+func (c *compiler) next() token {
+	tok := c.toks[c.pos]
+	if tok.kind != eof {
+		c.pos++
+	}
+	return tok
+}
+
+// accept consumes the punctuation or keyword s if it comes next
+func (c *compiler) accept(s string) bool {
+	if tok := c.peek(); (tok.kind == punct || tok.kind == word) && tok.text == s {
+		c.pos++
+		return true
+	}
+	return false
+}
+
+func (c *compiler) expect(s string) {
+	if !c.accept(s) {
+		c.fail(c.peek(), "expected %s, found %s", s, c.peek().text)
+	}
+}
+
+var keywords = []string{"else", "fn", "if", "in", "out", "return", "var", "while"}
+
+func (c *compiler) ident() token {
+	tok := c.next()
+	if tok.kind != word || slices.Contains(keywords, tok.text) {
+		c.fail(tok, "expected a name, found %s", tok.text)
+	}
+	return tok
+}
+
+// emit writes an instruction
+func (c *compiler) emit(format string, args ...any) {
+	fmt.Fprintf(&c.out, "\t"+format+"\n", args...)
+}
+
+// label returns a new label
+func (c *compiler) label() string {
+	c.labels++
+	return fmt.Sprintf("L.%d", c.labels)
+}
+
+// mark places label l
+func (c *compiler) mark(l string) {
+	fmt.Fprintf(&c.out, "%s:\n", l)
+}
+
+// top returns the relative parameter of the stack word i below the top,
+// 1 is the top
+func (c *compiler) top(i int) string {
+	return fmt.Sprintf(".%d", -i)
+}
+
+// drop pops n words
+func (c *compiler) drop(n int) {
+	if n > 0 {
+		c.emit("RBO %d", -n)
+		c.depth -= n
+	}
+}
+
+// ref returns the parameter of variable tok
+func (c *compiler) ref(tok token) string {
+	for i := len(c.scopes) - 1; i >= 0; i-- {
+		if slot, ok := c.scopes[i][tok.text]; ok {
+			return fmt.Sprintf(".%d", slot-c.depth)
+		}
+	}
+	c.refs = append(c.refs, tok)
+	return "$var." + tok.text
+}
+
+func (c *compiler) program() {
+	c.emit("RBO L.stack")
+	c.emit("push 0")
+	c.emit("call main")
+	c.emit("HLT")
+
+	main := token{word, "main", 0, 1}
+	c.calls, c.nargs = append(c.calls, main), append(c.nargs, 0)
+
+	for c.peek().kind != eof {
+		switch tok := c.next(); tok.text {
+		case "fn":
+			c.function()
+		case "var":
+			name := c.ident()
+			if _, ok := c.globals[name.text]; ok {
+				c.fail(name, "%s redeclared", name.text)
+			}
+			c.expect("=")
+			sign := 1
+			if c.accept("-") {
+				sign = -1
+			}
+			v := c.next()
+			if v.kind != number {
+				c.fail(v, "a global starts with a number, found %s", v.text)
+			}
+			c.expect(";")
+			c.globals[name.text] = sign * v.val
+		default:
+			c.fail(tok, "expected fn or var, found %s", tok.text)
+		}
+	}
+
+	for i, call := range c.calls {
+		n, ok := c.funcs[call.text]
+		switch {
+		case !ok:
+			c.fail(call, "undefined function %s", call.text)
+		case n != c.nargs[i]:
+			c.fail(call, "%s takes %d arguments, not %d", call.text, n, c.nargs[i])
+		}
+	}
+	for _, ref := range c.refs {
+		if _, ok := c.globals[ref.text]; !ok {
+			c.fail(ref, "undefined variable %s", ref.text)
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(c.globals)) {
+		fmt.Fprintf(&c.out, "var.%s:\n", name)
+		c.emit("db %d", c.globals[name])
+	}
+	c.mark("L.stack")
+}
+
+func (c *compiler) function() {
+	name := c.ident()
+	if _, ok := c.funcs[name.text]; ok {
+		c.fail(name, "%s redefined", name.text)
+	}
+
+	var params []token
+	c.expect("(")
+	for !c.accept(")") {
+		if len(params) > 0 {
+			c.expect(",")
+		}
+		params = append(params, c.ident())
+	}
+	c.funcs[name.text] = len(params)
+
+	// the caller pushed the result, the arguments and the return address
+	n := len(params)
+	scope := make(map[string]int, n)
+	for i, p := range params {
+		if _, ok := scope[p.text]; ok {
+			c.fail(p, "parameter %s repeated", p.text)
+		}
+		scope[p.text] = i - n - 1
+	}
+	c.scopes, c.depth, c.result = []map[string]int{scope}, 0, -n-2
+
+	c.mark(name.text)
+	c.block()
+	if !strings.HasSuffix(c.out.String(), "\tret\n") { // no return last
+		c.emit("ret")
+	}
+}
+
+func (c *compiler) block() {
+	c.expect("{")
+	c.scopes = append(c.scopes, make(map[string]int))
+	for !c.accept("}") {
+		if c.peek().kind == eof {
+			c.fail(c.peek(), "expected }, found end of file")
+		}
+		c.statement()
+	}
+	c.drop(len(c.scopes[len(c.scopes)-1]))
+	c.scopes = c.scopes[:len(c.scopes)-1]
+}
+
+func (c *compiler) statement() {
+	tok := c.peek()
+	switch {
+	case c.accept("var"):
+		name := c.ident()
+		scope := c.scopes[len(c.scopes)-1]
+		if _, ok := scope[name.text]; ok {
+			c.fail(name, "%s redeclared", name.text)
+		}
+		c.expect("=")
+		c.expr()
+		c.expect(";")
+		scope[name.text] = c.depth - 1
+
+	case c.accept("if"):
+		c.cond()
+		skip := c.label()
+		c.emit("JIF .0 %s", skip)
+		c.block()
+
+		if !c.accept("else") {
+			c.mark(skip)
+			break
+		}
+		end := c.label()
+		c.emit("jmp %s", end)
+		c.mark(skip)
+		if c.peek().text == "if" {
+			c.statement()
+		} else {
+			c.block()
+		}
+		c.mark(end)
+
+	case c.accept("while"):
+		loop, end := c.label(), c.label()
+		c.mark(loop)
+		c.cond()
+		c.emit("JIF .0 %s", end)
+		c.block()
+		c.emit("jmp %s", loop)
+		c.mark(end)
+
+	case c.accept("return"):
+		if !c.accept(";") {
+			c.expr()
+			c.expect(";")
+			c.emit("pop .%d", c.result-c.depth+1)
+			c.depth--
+		}
+		if c.depth > 0 {
+			c.emit("RBO %d", -c.depth)
+		}
+		c.emit("ret")
+
+	case c.accept("out"):
+		c.expect("(")
+		if s := c.peek(); s.kind == str {
+			c.next()
+			for _, b := range []byte(s.text) {
+				c.emit("OUT %d", b)
+			}
+		} else {
+			c.expr()
+			c.emit("RBO -1")
+			c.emit("OUT .0")
+			c.depth--
+		}
+		c.expect(")")
+		c.expect(";")
+
+	case tok.kind == word && c.toks[c.pos+1].text == "=":
+		name := c.ident()
+		c.next()
+		c.expr()
+		c.expect(";")
+		c.depth--
+		c.emit("pop %s", c.ref(name))
+
+	default:
+		c.expr()
+		c.expect(";")
+		c.drop(1)
+	}
+}
+
+// cond pops a condition to .0
+func (c *compiler) cond() {
+	c.expr()
+	c.drop(1)
+}
+
+// binary operators by precedence, lowest first
+var precedence = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!=", "<", "<=", ">", ">="},
+	{"+", "-"},
+	{"*"},
+}
+
+func (c *compiler) expr() {
+	c.binary(0)
+}
+
+func (c *compiler) binary(level int) {
+	if level == len(precedence) {
+		c.unary()
+		return
+	}
+
+	c.binary(level + 1)
+	for {
+		op := c.peek().text
+		if c.peek().kind != punct || !slices.Contains(precedence[level], op) {
+			return
+		}
+		c.next()
+		c.binary(level + 1)
+
+		a, b := c.top(2), c.top(1)
+		switch op {
+		case "+":
+			c.emit("ADD %s %s %s", a, b, a)
+		case "-":
+			c.emit("MUL %s -1 %s", b, b)
+			c.emit("ADD %s %s %s", a, b, a)
+		case "*":
+			c.emit("MUL %s %s %s", a, b, a)
+		case "==", "!=":
+			c.emit("EQ %s %s %s", a, b, a)
+		case "<", ">=":
+			c.emit("LT %s %s %s", a, b, a)
+		case ">", "<=":
+			c.emit("LT %s %s %s", b, a, a)
+		case "&&", "||":
+			c.emit("EQ %s 0 %s", a, a)
+			c.emit("EQ %s 0 %s", b, b)
+			if op == "&&" {
+				c.emit("ADD %s %s %s", a, b, a)
+			} else {
+				c.emit("MUL %s %s %s", a, b, a)
+			}
+		}
+		switch op {
+		case "!=", ">=", "<=", "&&", "||":
+			c.emit("EQ %s 0 %s", a, a)
+		}
+		c.drop(1)
+	}
+}
+
+func (c *compiler) unary() {
+	switch {
+	case c.accept("-"):
+		c.unary()
+		c.emit("MUL .-1 -1 .-1")
+	case c.accept("!"):
+		c.unary()
+		c.emit("EQ .-1 0 .-1")
+	default:
+		c.primary()
+	}
+}
+
+func (c *compiler) primary() {
+	tok := c.next()
+	switch {
+	case tok.kind == number:
+		c.emit("push %d", tok.val)
+		c.depth++
+
+	case tok.text == "(" && tok.kind == punct:
+		c.expr()
+		c.expect(")")
+
+	case tok.text == "in" && tok.kind == word:
+		c.expect("(")
+		c.expect(")")
+		c.emit("INP .0")
+		c.emit("RBO 1")
+		c.depth++
+
+	case tok.kind == word && !slices.Contains(keywords, tok.text) && c.accept("("):
+		c.emit("push 0")
+		c.depth++
+
+		n := 0
+		for !c.accept(")") {
+			if n > 0 {
+				c.expect(",")
+			}
+			c.expr()
+			n++
+		}
+		c.calls, c.nargs = append(c.calls, tok), append(c.nargs, n)
+
+		c.emit("call %s", tok.text)
+		c.drop(n)
+
+	case tok.kind == word && !slices.Contains(keywords, tok.text):
+		c.emit("push %s", c.ref(tok))
+		c.depth++
+
+	default:
+		c.fail(tok, "unexpected %s", tok.text)
+	}
+}
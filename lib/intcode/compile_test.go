@@ -0,0 +1,116 @@
+package intcode
+
+import (
+	"slices"
+	"testing"
+)
+
+// cc compiles and assembles src, and runs it on inputs
+func cc(t *testing.T, src string, inputs ...int) []int {
+	t.Helper()
+
+	asm, err := Compile(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, err := Assemble(asm)
+	if err != nil {
+		t.Fatalf("%v in\n%s", err, asm)
+	}
+
+	cpu := New(0, code)
+	cpu.Push(inputs...)
+
+	var outs []int
+	for {
+		switch cpu.Run() {
+		case HaveOutput:
+			outs = append(outs, cpu.Pop())
+		case NeedInput:
+			t.Fatalf("%q reads past its inputs %v", src, inputs)
+		case Halted:
+			return outs
+		}
+	}
+}
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		src      string
+		inputs   []int
+		expected []int
+	}{
+		{`fn main() { out(1 + 2 * 3 - -4); out((1 + 2) * 3); }`, nil, []int{11, 9}},
+		{
+			`fn main() {
+				out(3 < 4); out(4 < 3); out(3 <= 3); out(3 > 3); out(3 >= 3);
+				out(2 == 2); out(2 != 2); out(!7); out(1 && 0); out(0 || 5);
+			}`,
+			nil, []int{1, 0, 1, 0, 1, 1, 0, 0, 0, 1},
+		},
+		{`fn main() { out("hi\n"); out('!'); }`, nil, []int{'h', 'i', '\n', '!'}},
+		{
+			// globals, locals, while and calls
+			`var total = 0;
+			fn main() {
+				var n = in();
+				while n > 0 {
+					total = total + fact(n);
+					n = in();
+				}
+				out(total);
+			}
+			fn fact(n) {
+				if n < 2 { return 1; } else { return n * fact(n - 1); }
+			}`,
+			[]int{3, 4, 0}, []int{30},
+		},
+		{
+			// locals in blocks, else if and early returns
+			`fn main() {
+				var i = 0;
+				while i < 8 {
+					var f = fib(i);
+					out(f);
+					i = i + 1;
+				}
+				out(sign(-5)); out(sign(0)); out(sign(5));
+			}
+			fn fib(n) {
+				if n < 2 { return n; }
+				var a = fib(n - 1);
+				var b = fib(n - 2);
+				return a + b;
+			}
+			fn sign(x) {
+				if x < 0 { return -1; } else if x == 0 { return 0; }
+				return 1;
+			}`,
+			nil, []int{0, 1, 1, 2, 3, 5, 8, 13, -1, 0, 1},
+		},
+		{`fn main() { noop(); out(noop()); } fn noop() { }`, nil, []int{0}},
+	}
+
+	for _, tt := range tests {
+		if got := cc(t, tt.src, tt.inputs...); !slices.Equal(got, tt.expected) {
+			t.Errorf("%s on %v outputs %v, expected %v", tt.src, tt.inputs, got, tt.expected)
+		}
+	}
+
+	bad := []string{
+		``,
+		`fn f() { }`,
+		`fn main() { out(x); }`,
+		`fn main() { f(1); } fn f() { }`,
+		`fn main() { var x = 1; var x = 2; }`,
+		`fn main() { out(1) }`,
+		`fn main() { if 1 { }`,
+		`fn main() { out(1 / 2); }`,
+		`var g = x; fn main() { }`,
+	}
+	for _, src := range bad {
+		if _, err := Compile(src); err == nil {
+			t.Errorf("Compile(%q) expected to fail", src)
+		}
+	}
+}
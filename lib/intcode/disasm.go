@@ -0,0 +1,299 @@
+// disasm.go --
+// intcode disassembler
+//
+// https://github.com/erik-adelbert/aoc
+//
+// (ɔ) Erik Adelbert - erik_AT_adelbert_DOT_fr
+// -------------------------------------------
+// code is what a walk from address 0 reaches, everything else is data
+
+package intcode
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Instr is a decoded instruction
+type Instr struct {
+	Op    int
+	Modes [3]int
+	Args  []int // parameters as written
+}
+
+// Decode returns the instruction at a, false when the words there are no
+// valid instruction: unknown opcode or mode, or past the end of ic
+func Decode(ic Code, a int) (Instr, bool) {
+	if a < 0 || a >= len(ic) || ic[a] < 0 {
+		return Instr{}, false
+	}
+
+	w := ic[a]
+	in := Instr{Op: w % 100}
+	if in.Op >= len(arity) || mnemonics[in.Op] == "" || in.Op == EOT {
+		return Instr{}, false
+	}
+
+	n := arity[in.Op]
+	if a+n >= len(ic) {
+		return Instr{}, false
+	}
+
+	w /= 100
+	for i := range n {
+		in.Modes[i] = w % 10
+		if in.Modes[i] > REL {
+			return Instr{}, false
+		}
+		w /= 10
+	}
+	if w != 0 { // modes past the last parameter
+		return Instr{}, false
+	}
+
+	in.Args = ic[a+1 : a+1+n]
+	return in, true
+}
+
+// Len returns the word count of in
+func (in Instr) Len() int {
+	return 1 + len(in.Args)
+}
+
+// jump returns the target of an immediate jump and whether it jumps
+// always, ok is false when the target is computed or it never jumps
+func (in Instr) jump() (target int, always, ok bool) {
+	if (in.Op != JIT && in.Op != JIF) || in.Modes[1] != IMM {
+		return 0, false, false
+	}
+	if in.Modes[0] == IMM {
+		if (in.Op == JIT) != (in.Args[0] != 0) {
+			return 0, false, false // never
+		}
+		always = true
+	}
+	return in.Args[1], always, true
+}
+
+// ends tells if control never falls through in
+func (in Instr) ends() bool {
+	switch in.Op {
+	case HLT:
+		return true
+	case JIT, JIF:
+		if in.Modes[0] == IMM {
+			return (in.Op == JIT) == (in.Args[0] != 0)
+		}
+	}
+	return false
+}
+
+// String returns in as its mnemonic and parameters, $ for a position and
+// . for a relative one, like the trace does
+func (in Instr) String() string {
+	return in.format(nil)
+}
+
+// format is String with the immediate jump targets found in labels named
+func (in Instr) format(labels map[int]bool) string {
+	var sb strings.Builder
+	sb.WriteString(mnemonics[in.Op])
+
+	target, _, isjump := in.jump()
+	for i, v := range in.Args {
+		sb.WriteByte(' ')
+		switch in.Modes[i] {
+		case POS:
+			sb.WriteByte('$')
+		case REL:
+			sb.WriteByte('.')
+		}
+		if isjump && i == 1 && labels[target] {
+			fmt.Fprintf(&sb, "L%d", v)
+			continue
+		}
+		sb.WriteString(strconv.Itoa(v))
+	}
+	return sb.String()
+}
+
+// Line is a line of a listing: an instruction or a run of data words
+type Line struct {
+	Addr  int
+	Label bool  // a jump lands here
+	Code  bool  // Instr is valid, the line is data otherwise
+	Instr Instr // the instruction
+	Words []int // the words of the line
+}
+
+// Disasm splits ic into instructions and data: it walks the program from
+// address 0 following its immediate jumps and the return addresses of its
+// calls, an immediate address pushed before an always taken jump
+func Disasm(ic Code) []Line {
+	code := make([]bool, len(ic))   // instruction starts
+	inside := make([]bool, len(ic)) // words of instructions
+	labels := make(map[int]bool)
+
+	todo := []int{0}
+	for len(todo) > 0 {
+		a := todo[len(todo)-1]
+		todo = todo[:len(todo)-1]
+
+		rets := make(map[int]bool) // addresses written from immediates
+		for !inside[a] {
+			in, ok := Decode(ic, a)
+			if !ok {
+				break
+			}
+			code[a] = true
+			for i := range in.Len() {
+				inside[a+i] = true
+			}
+
+			if (in.Op == ADD || in.Op == MUL) && in.Modes[0] == IMM && in.Modes[1] == IMM {
+				v := in.Args[0] + in.Args[1]
+				if in.Op == MUL {
+					v = in.Args[0] * in.Args[1]
+				}
+				rets[v] = true
+			}
+
+			next := a + in.Len()
+			if target, always, ok := in.jump(); ok && target >= 0 && target < len(ic) {
+				labels[target] = true
+				todo = append(todo, target)
+				if always && rets[next] && next < len(ic) {
+					labels[next] = true
+					todo = append(todo, next)
+				}
+			}
+			if in.ends() || next >= len(ic) {
+				break
+			}
+			a = next
+		}
+	}
+
+	var lines []Line
+	for a := 0; a < len(ic); {
+		if code[a] {
+			in, _ := Decode(ic, a)
+			lines = append(lines, Line{a, labels[a], true, in, ic[a : a+in.Len()]})
+			a += in.Len()
+			continue
+		}
+
+		// data runs to the next instruction or label, split in strings and
+		// rows of numbers
+		end := a + 1
+		for end < len(ic) && !code[end] && !labels[end] {
+			end++
+		}
+		for _, w := range split(ic[a:end]) {
+			lines = append(lines, Line{Addr: a, Label: labels[a], Words: w})
+			a += len(w)
+		}
+	}
+	return lines
+}
+
+// MINSTR is the shortest run of ASCII data shown as a string
+const MINSTR = 4
+
+// ascii tells if v prints
+func ascii(v int) bool {
+	return v == '\n' || (v >= ' ' && v <= '~')
+}
+
+// split cuts data in ASCII lines and rows of at most 8 numbers
+func split(data []int) [][]int {
+	var rows [][]int
+	for len(data) > 0 {
+		n := strlen(data)
+		if n < MINSTR {
+			n = 1
+			for n < min(8, len(data)) && strlen(data[n:]) < MINSTR {
+				n++
+			}
+		}
+		rows, data = append(rows, data[:n]), data[n:]
+	}
+	return rows
+}
+
+// strlen returns the length of the ASCII line data starts with, newline
+// included
+func strlen(data []int) int {
+	n := 0
+	for n < len(data) && ascii(data[n]) {
+		n++
+		if data[n-1] == '\n' {
+			break
+		}
+	}
+	return n
+}
+
+// String returns l as an instruction or a db directive, a string when its
+// words are printable
+func (l Line) String() string {
+	return l.format(nil)
+}
+
+func (l Line) format(labels map[int]bool) string {
+	if l.Code {
+		return l.Instr.format(labels)
+	}
+
+	if len(l.Words) >= MINSTR && strlen(l.Words) == len(l.Words) {
+		b := make([]byte, len(l.Words))
+		for i, v := range l.Words {
+			b[i] = byte(v)
+		}
+		return "db " + strconv.Quote(string(b))
+	}
+
+	words := make([]string, len(l.Words))
+	for i, v := range l.Words {
+		words[i] = strconv.Itoa(v)
+	}
+	return "db " + strings.Join(words, ",")
+}
+
+// Listing writes the disassembly of ic to w: labels named L and their
+// address, one line per instruction or data row commented with its
+// address and words
+func Listing(w io.Writer, ic Code) error {
+	lines := Disasm(ic)
+
+	labels := make(map[int]bool)
+	for _, l := range lines {
+		if l.Label {
+			labels[l.Addr] = true
+		}
+	}
+
+	for _, l := range lines {
+		if l.Label {
+			if _, err := fmt.Fprintf(w, "L%d:\n", l.Addr); err != nil {
+				return err
+			}
+		}
+
+		words := make([]string, len(l.Words))
+		for i, v := range l.Words {
+			words[i] = strconv.Itoa(v)
+		}
+		comment := fmt.Sprintf("%d: %s", l.Addr, strings.Join(words, ","))
+		if !l.Code {
+			comment = strconv.Itoa(l.Addr)
+		}
+
+		if _, err := fmt.Fprintf(w, "\t%-24s; %s\n", l.format(labels), comment); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package intcode
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	ic := Code{1002, 4, 3, 4, 33, 21101, 0, 1, 99, 101101, 1, 2, 3, 1001, 4}
+
+	tests := []struct {
+		addr     int
+		expected string
+		ok       bool
+	}{
+		{0, "MUL $4 3 $4", true},
+		{5, "ADD 0 1 .99", true},
+		{8, "HLT", true},
+		{4, "", false},  // unknown opcode
+		{9, "", false},  // mode past the parameters
+		{13, "", false}, // past the end
+	}
+
+	for _, tt := range tests {
+		in, ok := Decode(ic, tt.addr)
+		if ok != tt.ok || (ok && in.String() != tt.expected) {
+			t.Errorf("Decode(%d) = %q, %v, expected %q, %v", tt.addr, in, ok, tt.expected, tt.ok)
+		}
+	}
+}
+
+func TestDisasm(t *testing.T) {
+	tests := []struct {
+		code     Code
+		expected string
+	}{
+		{
+			// jumps over a string
+			Code{1105, 1, 7, 'h', 'i', '!', '\n', 104, 72, 99},
+			"JIT 1 L7|db \"hi!\\n\"|L7:OUT 72|HLT",
+		},
+		{
+			// calls 8 with 7 pushed as its return address
+			Code{21101, 7, 0, 0, 1105, 1, 8, 99, 2106, 0, 0},
+			"ADD 7 0 .0|JIT 1 L8|L7:HLT|L8:JIF 0 .0",
+		},
+		{
+			// day 5, 999 below 8, 1000 at 8 and 1001 above
+			Code{
+				3, 21, 1008, 21, 8, 20, 1005, 20, 22, 107, 8, 21, 20, 1006, 20, 31,
+				1106, 0, 36, 98, 0, 0, 1002, 21, 125, 20, 4, 20, 1105, 1, 46, 104,
+				999, 1105, 1, 46, 1101, 1000, 1, 20, 4, 20, 1105, 1, 46, 98, 99,
+			},
+			"INP $21|EQ $21 8 $20|JIT $20 L22|LT 8 $21 $20|JIF $20 L31|JIF 0 L36|db 98,0,0|" +
+				"L22:MUL $21 125 $20|OUT $20|JIT 1 L46|L31:OUT 999|JIT 1 L46|" +
+				"L36:ADD 1000 1 $20|OUT $20|JIT 1 L46|db 98|L46:HLT",
+		},
+	}
+
+	for _, tt := range tests {
+		var sb strings.Builder
+		if err := Listing(&sb, tt.code); err != nil {
+			t.Fatal(err)
+		}
+
+		// keep the labels and instructions only
+		var got []string
+		for line := range strings.Lines(sb.String()) {
+			line, _, _ = strings.Cut(line, ";")
+			got = append(got, strings.TrimSpace(line))
+		}
+		if s := strings.ReplaceAll(strings.Join(got, "|"), ":|", ":"); s != tt.expected {
+			t.Errorf("Listing(%v) =\n%s\nexpected\n%s", tt.code, s, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,385 @@
+// intcode.go --
+// intcode computer shared by the 2019 daily solvers
+//
+// https://adventofcode.com/2019/day/9
+// https://github.com/erik-adelbert/aoc
+//
+// (ɔ) Erik Adelbert - erik_AT_adelbert_DOT_fr
+// -------------------------------------------
+// the complete opcode set of 2019 day 9, relative mode and growable memory
+// in copy-on-write pages for cheap snapshots and forks
+
+// Package intcode runs the Intcode programs of advent of code 2019, step
+// by step with Run, Push and Pop or in a goroutine behind channels with
+// Exec.
+package intcode
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/erik-adelbert/aoc/lib/bitset"
+)
+
+// TRACE prints every executed instruction to stderr, it is set from the
+// TRACE environment variable
+var TRACE bool
+
+func init() {
+	if val, err := strconv.ParseBool(os.Getenv("TRACE")); err == nil {
+		TRACE = val
+	}
+}
+
+// Code is an Intcode program, or the memory of a CPU running it
+type Code []int
+
+// Parse reads a program from its comma separated words, the surrounding
+// whitespace of a file, eg. its final newline, is ignored
+func Parse(s string) (Code, error) {
+	words := strings.Split(strings.TrimSpace(s), ",")
+
+	ic := make(Code, len(words))
+	for i, w := range words {
+		n, err := strconv.Atoi(w)
+		if err != nil {
+			return nil, fmt.Errorf("intcode word %d: %w", i+1, err)
+		}
+		ic[i] = n
+	}
+	return ic, nil
+}
+
+// Clone returns a copy of ic
+func (ic Code) Clone() Code {
+	return append(Code(nil), ic...)
+}
+
+// Patch writes the address/value pairs of p to ic and returns it
+func (ic Code) Patch(p map[int]int) Code {
+	for i, v := range p {
+		ic[i] = v
+	}
+	return ic
+}
+
+// opcodes, EOT is internal: it ends a program whose input is closed
+const (
+	ADD = iota + 1
+	MUL
+	INP
+	OUT
+	JIT
+	JIF
+	LT
+	EQ
+	RBO
+	HLT = 99
+	EOT = 100
+)
+
+// parameter modes
+const (
+	POS = iota // position
+	IMM        // immediate
+	REL        // relative
+)
+
+// Status tells why Run returned
+type Status int
+
+// Run statuses
+const (
+	NeedInput  Status = iota // the program reads an input none pushed
+	HaveOutput               // the program wrote an output, Pop it
+	Halted                   // the program ended
+)
+
+func (s Status) String() string {
+	return [...]string{"NeedInput", "HaveOutput", "Halted"}[s]
+}
+
+// PAGE is the word count of a memory page, the snapshots and forks of a
+// CPU share its pages until one of them writes there
+const PAGE = 256
+
+type page [PAGE]int
+
+// CPU is an Intcode computer, Run executes its program until it needs an
+// input, writes an output or halts, Push feeds it and Pop drains it
+type CPU struct {
+	id, pc, rbo int
+
+	size  int           // memory words in use
+	pages []*page       // nil until written
+	owned bitset.Bitset // pages no snapshot shares
+
+	ins, outs []int // pending inputs and outputs
+	out       chan int
+}
+
+// New returns a CPU loaded with a copy of code
+func New(id int, code Code) *CPU {
+	cpu := &CPU{id: id, size: len(code), out: make(chan int, 1)}
+	for i := 0; i < len(code); i += PAGE {
+		p := new(page)
+		copy(p[:], code[i:])
+		cpu.pages = append(cpu.pages, p)
+		cpu.owned.Add(i / PAGE)
+	}
+	return cpu
+}
+
+// Memory returns a copy of the memory of cpu, up to the last word written
+func (cpu *CPU) Memory() Code {
+	mem := make(Code, cpu.size)
+	for i, p := range cpu.pages {
+		if p != nil {
+			copy(mem[i*PAGE:], p[:])
+		}
+	}
+	return mem
+}
+
+// Push queues inputs for the program
+func (cpu *CPU) Push(vs ...int) {
+	cpu.ins = append(cpu.ins, vs...)
+}
+
+// Pop returns the oldest pending output, Run returning HaveOutput tells
+// there is one
+func (cpu *CPU) Pop() int {
+	if len(cpu.outs) == 0 {
+		panic(fmt.Errorf("cpu%02d: no output at %d", cpu.id, cpu.pc))
+	}
+
+	v := cpu.outs[0]
+	cpu.outs = cpu.outs[1:]
+	return v
+}
+
+// Run executes the program until it reads an input none pushed, writes an
+// output or halts and tells which: it resumes where it stopped
+func (cpu *CPU) Run() Status {
+	for {
+		op := cpu.read(cpu.pc) % 100
+
+		switch op {
+		case HLT:
+			cpu.trace(op)
+			return Halted
+		case ADD, MUL, LT, EQ: // binary op
+			a, b, dst := cpu.arg(1), cpu.arg(2), cpu.dst(3)
+			cpu.trace(op, a, b)
+
+			var v int
+			switch {
+			case op == ADD:
+				v = a + b
+			case op == MUL:
+				v = a * b
+			case op == LT && a < b, op == EQ && a == b:
+				v = 1
+			}
+			cpu.write(dst, v)
+			cpu.pc += 4
+		case JIT, JIF: // conditional jump
+			a, b := cpu.arg(1), cpu.arg(2)
+			cpu.trace(op, a, b)
+
+			switch {
+			case op == JIT && a != 0, op == JIF && a == 0:
+				cpu.pc = b
+			default:
+				cpu.pc += 3
+			}
+		case INP:
+			if len(cpu.ins) == 0 {
+				return NeedInput
+			}
+
+			dst, v := cpu.dst(1), cpu.ins[0]
+			cpu.ins = cpu.ins[1:]
+			cpu.trace(op, v)
+
+			cpu.write(dst, v)
+			cpu.pc += 2
+		case OUT:
+			a := cpu.arg(1)
+			cpu.trace(op, a)
+
+			cpu.outs = append(cpu.outs, a)
+			cpu.pc += 2
+			return HaveOutput
+		case RBO:
+			a := cpu.arg(1)
+			cpu.trace(op, a)
+
+			cpu.rbo += a
+			cpu.pc += 2
+		default:
+			panic(fmt.Errorf("cpu%02d: opcode %d at %d", cpu.id, cpu.read(cpu.pc), cpu.pc))
+		}
+	}
+}
+
+// ReadLine runs the program to the end of its next ASCII output line and
+// returns it without its newline, or what it has when the program waits
+// for an input or halts
+func (cpu *CPU) ReadLine() string {
+	line := make([]byte, 0, 32)
+	for cpu.Run() == HaveOutput {
+		v := cpu.Pop()
+		if v == '\n' {
+			break
+		}
+		line = append(line, byte(v))
+	}
+	return string(line)
+}
+
+// WriteLine pushes s and a newline as ASCII inputs
+func (cpu *CPU) WriteLine(s string) {
+	for _, c := range []byte(s) {
+		cpu.ins = append(cpu.ins, int(c))
+	}
+	cpu.ins = append(cpu.ins, '\n')
+}
+
+// Out returns the output of Exec, it is closed when the program ends
+func (cpu *CPU) Out() <-chan int {
+	return cpu.out
+}
+
+// Exec is the channel mode of Run: it runs the program reading in and
+// writing Out until it halts or in is closed, it then closes Out and
+// returns the final memory
+func (cpu *CPU) Exec(in <-chan int) Code {
+	defer close(cpu.out)
+
+	for {
+		switch cpu.Run() {
+		case NeedInput:
+			v, ok := <-in
+			if !ok {
+				cpu.trace(EOT)
+				return cpu.Memory()
+			}
+			cpu.Push(v)
+		case HaveOutput:
+			cpu.out <- cpu.Pop()
+		case Halted:
+			return cpu.Memory()
+		}
+	}
+}
+
+// mode returns the mode of the ith parameter of the current instruction
+func (cpu *CPU) mode(i int) int {
+	return cpu.read(cpu.pc) / [...]int{1, 100, 1000, 10000}[i] % 10
+}
+
+// addr returns the address of the ith parameter of the current instruction
+func (cpu *CPU) addr(i int) int {
+	a := cpu.pc + i
+	switch cpu.mode(i) {
+	case POS:
+		a = cpu.read(a)
+	case REL:
+		a = cpu.rbo + cpu.read(a)
+	}
+	return a
+}
+
+// arg returns the value of the ith parameter
+func (cpu *CPU) arg(i int) int {
+	return cpu.read(cpu.addr(i))
+}
+
+// dst returns the address the ith parameter writes to, an immediate
+// destination is a position one
+func (cpu *CPU) dst(i int) int {
+	if cpu.mode(i) == IMM {
+		return cpu.read(cpu.pc + i)
+	}
+	return cpu.addr(i)
+}
+
+// read returns the word at a, 0 past the memory in use
+func (cpu *CPU) read(a int) int {
+	if a < 0 {
+		panic(fmt.Errorf("cpu%02d: address %d at %d", cpu.id, a, cpu.pc))
+	}
+	if i := a / PAGE; i < len(cpu.pages) && cpu.pages[i] != nil {
+		return cpu.pages[i][a%PAGE]
+	}
+	return 0
+}
+
+// write sets the word at a, growing the memory to it and copying its page
+// when shared
+func (cpu *CPU) write(a, v int) {
+	if a < 0 {
+		panic(fmt.Errorf("cpu%02d: address %d at %d", cpu.id, a, cpu.pc))
+	}
+
+	i := a / PAGE
+	if i >= len(cpu.pages) {
+		cpu.pages = append(cpu.pages, make([]*page, i-len(cpu.pages)+1)...)
+	}
+	switch {
+	case cpu.pages[i] == nil:
+		cpu.pages[i] = new(page)
+		cpu.owned.Add(i)
+	case !cpu.owned.Has(i):
+		p := *cpu.pages[i]
+		cpu.pages[i] = &p
+		cpu.owned.Add(i)
+	}
+
+	cpu.pages[i][a%PAGE] = v
+	cpu.size = max(cpu.size, a+1)
+}
+
+var mnemonics = [...]string{
+	ADD: "ADD", MUL: "MUL", INP: "INP", OUT: "OUT", JIT: "JIT", JIF: "JIF",
+	LT: "LT", EQ: "EQ", RBO: "RBO", HLT: "HLT", EOT: "EOT",
+}
+
+// arity is the parameter count of each opcode
+var arity = [...]int{ADD: 3, MUL: 3, INP: 1, OUT: 1, JIT: 2, JIF: 2, LT: 3, EQ: 3, RBO: 1, HLT: 0, EOT: 0}
+
+// trace prints the current instruction, its parameters as written, $ for a
+// position and . for a relative one, and the values it reads
+func (cpu *CPU) trace(op int, vals ...int) {
+	if !TRACE {
+		return
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "cpu%02d: %4d %s", cpu.id, cpu.pc, mnemonics[op])
+
+	for i := 1; i <= arity[op]; i++ {
+		var sym string
+		switch cpu.mode(i) {
+		case POS:
+			sym = "$"
+		case REL:
+			sym = "."
+		}
+		fmt.Fprintf(&sb, " %s%d", sym, cpu.read(cpu.pc+i))
+	}
+
+	if len(vals) > 0 {
+		sb.WriteString("\t <-")
+		for i, v := range vals {
+			if i > 0 {
+				sb.WriteByte(',')
+			}
+			fmt.Fprintf(&sb, " %d", v)
+		}
+	}
+	fmt.Fprintln(os.Stderr, sb.String())
+}
@@ -0,0 +1,197 @@
+package intcode
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// exec runs code on inputs and returns its outputs and final memory
+func exec(t *testing.T, code Code, inputs ...int) ([]int, Code) {
+	t.Helper()
+
+	in := make(chan int, len(inputs))
+	for _, v := range inputs {
+		in <- v
+	}
+	close(in)
+
+	cpu := New(0, code)
+
+	var mem Code
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		mem = cpu.Exec(in)
+	}()
+
+	var outs []int
+	for v := range cpu.Out() {
+		outs = append(outs, v)
+	}
+	<-done
+
+	return outs, mem
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		s        string
+		expected Code
+		fails    bool
+	}{
+		{"1,0,0,0,99", Code{1, 0, 0, 0, 99}, false},
+		{"1,0,0,0,99\n", Code{1, 0, 0, 0, 99}, false},
+		{"1,0,0,0,99\r\n", Code{1, 0, 0, 0, 99}, false},
+		{"-1", Code{-1}, false},
+		{"1,,2", nil, true},
+		{"1, 2", nil, true},
+		{"", nil, true},
+	}
+
+	for _, tt := range tests {
+		got, err := Parse(tt.s)
+		if (err != nil) != tt.fails || !slices.Equal(got, tt.expected) {
+			t.Errorf("Parse(%q) = %v, %v, expected %v", tt.s, got, err, tt.expected)
+		}
+	}
+}
+
+// FuzzParse checks Parse turns down malformed programs with an error and
+// that a program it accepts reads the same once printed back, the corpus
+// is seeded with the examples of the 2019 days
+func FuzzParse(f *testing.F) {
+	names, _ := filepath.Glob("../../2019/*/sample.txt")
+	for _, name := range names {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(strings.TrimSpace(string(data)))
+	}
+	f.Add("1,0,0,0,99")
+
+	f.Fuzz(func(t *testing.T, s string) {
+		code, err := Parse(s)
+		if err != nil {
+			return // turned down
+		}
+
+		words := make([]string, len(code))
+		for i, n := range code {
+			words[i] = strconv.Itoa(n)
+		}
+		text := strings.Join(words, ",")
+
+		again, err := Parse(text)
+		if err != nil || !slices.Equal(again, code) {
+			t.Errorf("%q prints %q, which parses to %v, %v", s, text, again, err)
+		}
+	})
+}
+
+func TestMemory(t *testing.T) {
+	tests := []struct {
+		code     Code
+		expected Code
+	}{
+		// day 2
+		{Code{1, 9, 10, 3, 2, 3, 11, 0, 99, 30, 40, 50}, Code{3500, 9, 10, 70, 2, 3, 11, 0, 99, 30, 40, 50}},
+		{Code{1, 1, 1, 4, 99, 5, 6, 0, 99}, Code{30, 1, 1, 4, 2, 5, 6, 0, 99}},
+		// day 5, modes and negative values
+		{Code{1002, 4, 3, 4, 33}, Code{1002, 4, 3, 4, 99}},
+		{Code{1101, 100, -1, 4, 0}, Code{1101, 100, -1, 4, 99}},
+	}
+
+	for _, tt := range tests {
+		if _, got := exec(t, tt.code.Clone()); !slices.Equal(got, tt.expected) {
+			t.Errorf("Exec(%v) leaves %v, expected %v", tt.code, got, tt.expected)
+		}
+	}
+}
+
+func TestIO(t *testing.T) {
+	// day 9 quine
+	quine := Code{109, 1, 204, -1, 1001, 100, 1, 100, 1008, 100, 16, 101, 1006, 101, 0, 99}
+
+	// day 5, 999 below 8, 1000 at 8 and 1001 above
+	cmp := Code{
+		3, 21, 1008, 21, 8, 20, 1005, 20, 22, 107, 8, 21, 20, 1006, 20, 31,
+		1106, 0, 36, 98, 0, 0, 1002, 21, 125, 20, 4, 20, 1105, 1, 46, 104,
+		999, 1105, 1, 46, 1101, 1000, 1, 20, 4, 20, 1105, 1, 46, 98, 99,
+	}
+
+	tests := []struct {
+		code     Code
+		inputs   []int
+		expected []int
+	}{
+		{quine, nil, quine},
+		{Code{104, 1125899906842624, 99}, nil, []int{1125899906842624}},
+		{Code{1102, 34915192, 34915192, 7, 4, 7, 99, 0}, nil, []int{1219070632396864}},
+		{Code{3, 9, 8, 9, 10, 9, 4, 9, 99, -1, 8}, []int{8}, []int{1}},
+		{Code{3, 3, 1107, -1, 8, 3, 4, 3, 99}, []int{9}, []int{0}},
+		{cmp, []int{7}, []int{999}},
+		{cmp, []int{8}, []int{1000}},
+		{cmp, []int{9}, []int{1001}},
+		{Code{3, 0, 4, 0, 3, 0, 4, 0, 99}, []int{5}, []int{5}},      // ends at EOT
+		{Code{109, 2000, 203, 5, 204, 5, 99}, []int{42}, []int{42}}, // relative input
+	}
+
+	for _, tt := range tests {
+		if got, _ := exec(t, tt.code.Clone(), tt.inputs...); !slices.Equal(got, tt.expected) {
+			t.Errorf("Exec(%v) on %v outputs %v, expected %v", tt.code, tt.inputs, got, tt.expected)
+		}
+	}
+}
+
+func TestLines(t *testing.T) {
+	// prints "hi\nyo" from an immediate string, then echoes its input
+	code := Code{104, 'h', 104, 'i', 104, '\n', 104, 'y', 104, 'o', 3, 100, 4, 100, 1105, 1, 10}
+
+	cpu := New(0, code)
+	for _, expected := range []string{"hi", "yo", ""} {
+		if got := cpu.ReadLine(); got != expected {
+			t.Errorf("ReadLine() = %q, expected %q", got, expected)
+		}
+	}
+
+	cpu.WriteLine("ok")
+	if got := cpu.ReadLine(); got != "ok" {
+		t.Errorf("ReadLine() echoes %q, expected %q", got, "ok")
+	}
+}
+
+func TestRun(t *testing.T) {
+	// adds its inputs two by two until it reads a 0
+	cpu := New(0, Code{3, 20, 1005, 20, 6, 99, 3, 21, 1, 20, 21, 22, 4, 22, 1105, 1, 0})
+
+	steps := []struct {
+		push     []int
+		expected Status
+		pop      int
+	}{
+		{nil, NeedInput, 0},
+		{[]int{1}, NeedInput, 0},
+		{[]int{2}, HaveOutput, 3},
+		{[]int{20, 22}, HaveOutput, 42},
+		{nil, NeedInput, 0},
+		{[]int{0}, Halted, 0},
+		{nil, Halted, 0}, // stays halted
+	}
+
+	for i, st := range steps {
+		cpu.Push(st.push...)
+		if got := cpu.Run(); got != st.expected {
+			t.Fatalf("step %d: Run() = %v, expected %v", i, got, st.expected)
+		}
+		if st.expected == HaveOutput {
+			if got := cpu.Pop(); got != st.pop {
+				t.Errorf("step %d: Pop() = %d, expected %d", i, got, st.pop)
+			}
+		}
+	}
+}
@@ -0,0 +1,138 @@
+// snapshot.go --
+// intcode computer snapshots
+//
+// https://github.com/erik-adelbert/aoc
+//
+// (ɔ) Erik Adelbert - erik_AT_adelbert_DOT_fr
+// -------------------------------------------
+// a snapshot shares the memory pages of its CPU until either writes them
+
+package intcode
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"slices"
+)
+
+// MAGIC starts an encoded snapshot
+const MAGIC = "intcode1"
+
+// Snapshot is the state of a CPU: its registers, memory and pending
+// inputs and outputs
+type Snapshot struct {
+	pc, rbo, size int
+	pages         []*page
+	ins, outs     []int
+}
+
+// Snapshot returns the current state of cpu, it is cheap: cpu copies a
+// memory page only when it next writes there
+func (cpu *CPU) Snapshot() *Snapshot {
+	cpu.owned.Clear() // every page is shared now
+
+	return &Snapshot{
+		pc: cpu.pc, rbo: cpu.rbo, size: cpu.size,
+		pages: slices.Clone(cpu.pages),
+		ins:   slices.Clone(cpu.ins),
+		outs:  slices.Clone(cpu.outs),
+	}
+}
+
+// Restore sets cpu back to the state s, s stays valid for more restores
+func (cpu *CPU) Restore(s *Snapshot) {
+	cpu.pc, cpu.rbo, cpu.size = s.pc, s.rbo, s.size
+	cpu.pages = slices.Clone(s.pages)
+	cpu.owned.Clear()
+	cpu.ins, cpu.outs = slices.Clone(s.ins), slices.Clone(s.outs)
+}
+
+// Fork returns a copy of cpu which runs on from its current state
+func (cpu *CPU) Fork() *CPU {
+	fork := &CPU{id: cpu.id, out: make(chan int, 1)}
+	fork.Restore(cpu.Snapshot())
+	return fork
+}
+
+var errSnapshot = errors.New("intcode: bad snapshot")
+
+// MarshalBinary encodes s, its memory words included, as MAGIC and varints
+func (s *Snapshot) MarshalBinary() ([]byte, error) {
+	buf := []byte(MAGIC)
+
+	put := func(vs ...int) {
+		for _, v := range vs {
+			buf = binary.AppendVarint(buf, int64(v))
+		}
+	}
+
+	put(s.pc, s.rbo, s.size, len(s.ins), len(s.outs))
+	put(s.ins...)
+	put(s.outs...)
+
+	cpu := CPU{size: s.size, pages: s.pages}
+	for a := range s.size {
+		put(cpu.read(a))
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary decodes a snapshot encoded by MarshalBinary into s
+func (s *Snapshot) UnmarshalBinary(data []byte) error {
+	buf, ok := bytes.CutPrefix(data, []byte(MAGIC))
+	if !ok {
+		return errSnapshot
+	}
+
+	get := func() (int, error) {
+		v, n := binary.Varint(buf)
+		if n <= 0 {
+			return 0, errSnapshot
+		}
+		buf = buf[n:]
+		return int(v), nil
+	}
+
+	// gets reads n words, each one is a byte at least
+	gets := func(n int) ([]int, error) {
+		if n < 0 || n > len(buf) {
+			return nil, errSnapshot
+		}
+		vs := make([]int, n)
+		for i := range vs {
+			v, err := get()
+			if err != nil {
+				return nil, err
+			}
+			vs[i] = v
+		}
+		return vs, nil
+	}
+
+	head, err := gets(5)
+	if err != nil {
+		return err
+	}
+	pc, rbo, size := head[0], head[1], head[2]
+
+	ins, err := gets(head[3])
+	if err != nil {
+		return err
+	}
+	outs, err := gets(head[4])
+	if err != nil {
+		return err
+	}
+	mem, err := gets(size)
+	if err != nil {
+		return err
+	}
+	if len(buf) > 0 || pc < 0 {
+		return errSnapshot
+	}
+
+	cpu := New(0, mem)
+	*s = Snapshot{pc: pc, rbo: rbo, size: size, pages: cpu.pages, ins: ins, outs: outs}
+	return nil
+}
@@ -0,0 +1,94 @@
+package intcode
+
+import (
+	"slices"
+	"testing"
+)
+
+// adds its inputs and outputs the running sum, kept past a page
+var sum = Code{3, 1001, 1, 1000, 1001, 1000, 4, 1000, 1105, 1, 0}
+
+// feed pushes vs one by one and returns the outputs
+func feed(t *testing.T, cpu *CPU, vs ...int) []int {
+	t.Helper()
+
+	var outs []int
+	for _, v := range vs {
+		cpu.Push(v)
+		if st := cpu.Run(); st != HaveOutput {
+			t.Fatalf("Run() = %v, expected %v", st, HaveOutput)
+		}
+		outs = append(outs, cpu.Pop())
+	}
+	return outs
+}
+
+func TestSnapshot(t *testing.T) {
+	cpu := New(0, sum)
+	feed(t, cpu, 1, 2)
+
+	snap := cpu.Snapshot()
+	if got := feed(t, cpu, 10, 20); !slices.Equal(got, []int{13, 33}) {
+		t.Fatalf("outputs %v, expected [13 33]", got)
+	}
+
+	for range 2 { // a snapshot restores more than once
+		cpu.Restore(snap)
+		if got := feed(t, cpu, 100); !slices.Equal(got, []int{103}) {
+			t.Errorf("restored outputs %v, expected [103]", got)
+		}
+	}
+}
+
+func TestFork(t *testing.T) {
+	cpu := New(0, sum)
+	feed(t, cpu, 5)
+
+	fork := cpu.Fork()
+	a, b := feed(t, cpu, 1), feed(t, fork, 2)
+	if a[0] != 6 || b[0] != 7 {
+		t.Errorf("cpu and fork output %v and %v, expected [6] and [7]", a, b)
+	}
+
+	// they share the program page only
+	if cpu.pages[0] != fork.pages[0] || cpu.pages[3] == fork.pages[3] {
+		t.Error("cpu and fork don't share their memory copy-on-write")
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	cpu := New(0, sum)
+	feed(t, cpu, 1, 2)
+	cpu.Push(7) // pending
+
+	data, err := cpu.Snapshot().MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var snap Snapshot
+	if err := snap.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+
+	resumed := New(1, nil)
+	resumed.Restore(&snap)
+	if !slices.Equal(resumed.Memory(), cpu.Memory()) {
+		t.Errorf("resumed memory %v, expected %v", resumed.Memory(), cpu.Memory())
+	}
+	if got := feed(t, resumed, 3, 3); !slices.Equal(got, []int{10, 13}) {
+		t.Errorf("resumed outputs %v, expected [10 13]", got) // 7 pending first
+	}
+
+	bad := [][]byte{
+		nil,
+		[]byte("intcode0"),
+		data[:len(data)-1],
+		append(slices.Clone(data), 0),
+	}
+	for _, data := range bad {
+		if err := snap.UnmarshalBinary(data); err == nil {
+			t.Errorf("UnmarshalBinary(%q) expected to fail", data)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+// num.go --
+// integer helpers shared by the daily solvers
+//
+// https://github.com/erik-adelbert/aoc
+//
+// (ɔ) Erik Adelbert - erik_AT_adelbert_DOT_fr
+// -------------------------------------------
+// they replace the atoi, abs, mod, gcd and lcm every day used to carry
+
+// Package num holds the integer helpers of the daily solvers.
+package num
+
+// Integer is any integer type
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// Signed is any signed integer type
+type Signed interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64
+}
+
+// Atoi converts an optionally signed decimal. It trusts its input, as the
+// solvers do once it is validated. Anything but digits after the sign
+// makes a wrong number
+func Atoi[S ~string | ~[]byte](s S) (n int) {
+	neg := len(s) > 0 && s[0] == '-'
+	if neg || len(s) > 0 && s[0] == '+' {
+		s = s[1:]
+	}
+
+	for i := range len(s) {
+		n = 10*n + int(s[i]-'0')
+	}
+
+	if neg {
+		return -n
+	}
+	return n
+}
+
+// Abs returns the absolute value of n
+func Abs[T Signed](n T) T {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// Sign returns -1, 0 or +1 as n is negative, zero or positive
+func Sign[T Signed](n T) T {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
+
+// Mod returns the euclidean remainder of a by b, in [0, |b|) even when a
+// is negative
+func Mod[T Integer](a, b T) T {
+	m := a % b
+	if m < 0 {
+		if b < 0 {
+			return m - b
+		}
+		return m + b
+	}
+	return m
+}
+
+// GCD returns the greatest common divisor of a and b
+func GCD[T Integer](a, b T) T {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// LCM returns the least common multiple of its arguments, 1 without any
+func LCM[T Integer](ns ...T) T {
+	Π := T(1)
+	for _, n := range ns {
+		Π = Π / GCD(Π, n) * n
+	}
+	return Π
+}
@@ -0,0 +1,87 @@
+package num
+
+import "testing"
+
+func TestAtoi(t *testing.T) {
+	tests := []struct {
+		s        string
+		expected int
+	}{
+		{"0", 0},
+		{"42", 42},
+		{"-17", -17},
+		{"+5", 5},
+		{"1234567890123", 1234567890123},
+		{"", 0},
+	}
+
+	for _, tt := range tests {
+		if got := Atoi(tt.s); got != tt.expected {
+			t.Errorf("Atoi(%q) = %d, expected %d", tt.s, got, tt.expected)
+		}
+		if got := Atoi([]byte(tt.s)); got != tt.expected {
+			t.Errorf("Atoi([]byte(%q)) = %d, expected %d", tt.s, got, tt.expected)
+		}
+	}
+}
+
+func TestAbsSign(t *testing.T) {
+	tests := []struct {
+		n, abs, sign int
+	}{
+		{-3, 3, -1},
+		{0, 0, 0},
+		{7, 7, 1},
+	}
+
+	for _, tt := range tests {
+		if got := Abs(tt.n); got != tt.abs {
+			t.Errorf("Abs(%d) = %d, expected %d", tt.n, got, tt.abs)
+		}
+		if got := Sign(tt.n); got != tt.sign {
+			t.Errorf("Sign(%d) = %d, expected %d", tt.n, got, tt.sign)
+		}
+	}
+}
+
+func TestMod(t *testing.T) {
+	tests := []struct {
+		a, b, expected int
+	}{
+		{7, 3, 1},
+		{-7, 3, 2},
+		{-3, 3, 0},
+		{7, -3, 1},
+		{-7, -3, 2},
+	}
+
+	for _, tt := range tests {
+		if got := Mod(tt.a, tt.b); got != tt.expected {
+			t.Errorf("Mod(%d, %d) = %d, expected %d", tt.a, tt.b, got, tt.expected)
+		}
+	}
+}
+
+func TestGCDLCM(t *testing.T) {
+	if got := GCD(84, 36); got != 12 {
+		t.Errorf("GCD(84, 36) = %d, expected 12", got)
+	}
+	if got := GCD(uint8(7), 0); got != 7 {
+		t.Errorf("GCD(7, 0) = %d, expected 7", got)
+	}
+
+	tests := []struct {
+		ns       []int
+		expected int
+	}{
+		{nil, 1},
+		{[]int{4, 6}, 12},
+		{[]int{18, 28, 44}, 2772},
+	}
+
+	for _, tt := range tests {
+		if got := LCM(tt.ns...); got != tt.expected {
+			t.Errorf("LCM(%v) = %d, expected %d", tt.ns, got, tt.expected)
+		}
+	}
+}